@@ -0,0 +1,121 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+// Headers added by the rating ingester to dead-letter messages.
+const (
+	headerError         = "x-error"
+	headerOriginalTopic = "x-original-topic"
+	headerPrefix        = "x-"
+)
+
+func main() {
+	brokers := flag.String("brokers", "localhost:9092", "Kafka bootstrap servers")
+	dlq := flag.String("dlq", "ratings-dlq", "dead-letter topic to replay")
+	topic := flag.String("topic", "", "topic to replay messages to, defaults to the original topic of each message")
+	groupID := flag.String("group", "rating-dlq-replay", "consumer group used to track replayed messages")
+	idle := flag.Duration("idle", 10*time.Second, "stop after no message was received for this long")
+	maxMessages := flag.Int("max", 0, "maximum number of messages to replay, 0 means no limit")
+	dryRun := flag.Bool("dry-run", false, "print the messages without replaying or committing them")
+	flag.Parse()
+
+	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers":  *brokers,
+		"group.id":           *groupID,
+		"auto.offset.reset":  "earliest",
+		"enable.auto.commit": false,
+	})
+	if err != nil {
+		log.Fatalf("cannot create consumer: %v", err)
+	}
+	defer consumer.Close()
+	if err := consumer.SubscribeTopics([]string{*dlq}, nil); err != nil {
+		log.Fatalf("cannot subscribe to %s: %v", *dlq, err)
+	}
+
+	var producer *kafka.Producer
+	if !*dryRun {
+		producer, err = kafka.NewProducer(&kafka.ConfigMap{
+			"bootstrap.servers":  *brokers,
+			"enable.idempotence": true,
+		})
+		if err != nil {
+			log.Fatalf("cannot create producer: %v", err)
+		}
+		defer producer.Close()
+	}
+
+	fmt.Println("Replaying messages from " + *dlq)
+	replayed := 0
+	for *maxMessages == 0 || replayed < *maxMessages {
+		msg, err := consumer.ReadMessage(*idle)
+		if err != nil {
+			if kerr, ok := err.(kafka.Error); ok && kerr.Code() == kafka.ErrTimedOut {
+				break
+			}
+			log.Fatalf("cannot read message: %v", err)
+		}
+		target := *topic
+		if target == "" {
+			target = header(msg, headerOriginalTopic)
+		}
+		if target == "" {
+			log.Fatalf("message at offset %v has no original topic, use -topic", msg.TopicPartition.Offset)
+		}
+		if *dryRun {
+			fmt.Printf("%s -> %s: %s (error: %s)\n", msg.TopicPartition.Offset, target, msg.Value, header(msg, headerError))
+			replayed++
+			continue
+		}
+		if err := replay(producer, target, msg); err != nil {
+			log.Fatalf("cannot replay message at offset %v: %v", msg.TopicPartition.Offset, err)
+		}
+		if _, err := consumer.CommitMessage(msg); err != nil {
+			log.Fatalf("cannot commit message at offset %v: %v", msg.TopicPartition.Offset, err)
+		}
+		replayed++
+	}
+	fmt.Printf("Replayed %d messages\n", replayed)
+}
+
+// replay produces a dead-letter message to the target topic without the
+// dead-letter headers and waits for its delivery.
+func replay(producer *kafka.Producer, topic string, msg *kafka.Message) error {
+	var headers []kafka.Header
+	for _, h := range msg.Headers {
+		if !strings.HasPrefix(h.Key, headerPrefix) {
+			headers = append(headers, h)
+		}
+	}
+	delivery := make(chan kafka.Event, 1)
+	if err := producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+		Key:            msg.Key,
+		Value:          msg.Value,
+		Headers:        headers,
+	}, delivery); err != nil {
+		return err
+	}
+	report, ok := (<-delivery).(*kafka.Message)
+	if !ok {
+		return fmt.Errorf("unexpected delivery report")
+	}
+	return report.TopicPartition.Error
+}
+
+func header(msg *kafka.Message, key string) string {
+	for _, h := range msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/abhishek622/movieapp/gen"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// checkpoint records the progress of an export so that it can be resumed.
+type checkpoint struct {
+	Cursor       string    `json:"cursor"`
+	SnapshotTime time.Time `json:"snapshotTime"`
+	Format       string    `json:"format"`
+	// Offset is the size of the output file after the last exported batch.
+	Offset   int64 `json:"offset"`
+	Exported int   `json:"exported"`
+	Done     bool  `json:"done"`
+}
+
+func main() {
+	addr := flag.String("addr", "localhost:8082", "rating service gRPC address")
+	caFile := flag.String("ca", "", "CA certificate file, the connection is insecure if empty")
+	certFile := flag.String("cert", "", "client certificate file")
+	keyFile := flag.String("key", "", "client key file")
+	format := flag.String("format", "csv", "output format: csv or ndjson")
+	out := flag.String("out", "ratings.csv", "output file")
+	recordType := flag.String("type", "", "export only ratings of this record type")
+	since := flag.String("since", "", "export only ratings updated at or after this RFC 3339 time")
+	until := flag.String("until", "", "export only ratings updated at or before this RFC 3339 time")
+	checkpointFile := flag.String("checkpoint", "", "checkpoint file used to resume an interrupted export")
+	flag.Parse()
+
+	if *format != "csv" && *format != "ndjson" {
+		log.Fatalf("unsupported format %q", *format)
+	}
+	req := &gen.ExportRatingsRequest{RecordType: *recordType}
+	if *since != "" {
+		t, err := time.Parse(time.RFC3339, *since)
+		if err != nil {
+			log.Fatalf("invalid start time: %v", err)
+		}
+		req.StartTime = timestamppb.New(t)
+	}
+	if *until != "" {
+		t, err := time.Parse(time.RFC3339, *until)
+		if err != nil {
+			log.Fatalf("invalid end time: %v", err)
+		}
+		req.EndTime = timestamppb.New(t)
+	}
+
+	cp, err := readCheckpoint(*checkpointFile)
+	if err != nil {
+		log.Fatalf("cannot read checkpoint: %v", err)
+	}
+	if cp.Done {
+		fmt.Println("Export already completed according to checkpoint " + *checkpointFile)
+		return
+	}
+	resume := cp.Cursor != ""
+	if resume {
+		if cp.Format != *format {
+			log.Fatalf("checkpoint was written for format %q", cp.Format)
+		}
+		req.Cursor = cp.Cursor
+		req.SnapshotTime = timestamppb.New(cp.SnapshotTime)
+		fmt.Printf("Resuming export after %d ratings\n", cp.Exported)
+	}
+	cp.Format = *format
+
+	f, err := openOutput(*out, resume, cp.Offset)
+	if err != nil {
+		log.Fatalf("cannot open output: %v", err)
+	}
+	defer f.Close()
+
+	creds, err := transportCredentials(*caFile, *certFile, *keyFile)
+	if err != nil {
+		log.Fatalf("cannot load credentials: %v", err)
+	}
+	conn, err := grpc.NewClient(*addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		log.Fatalf("cannot connect to rating service: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := export(ctx, gen.NewRatingServiceClient(conn), req, f, !resume, cp, *checkpointFile); err != nil {
+		log.Fatalf("export failed after %d ratings: %v", cp.Exported, err)
+	}
+	fmt.Printf("Exported %d ratings to %s\n", cp.Exported, *out)
+	if !cp.SnapshotTime.IsZero() {
+		// Ratings updated while the export ran, or before it was resumed, are
+		// not part of its snapshot.
+		fmt.Printf("Export the ratings updated later with -since %s\n", cp.SnapshotTime.Format(time.RFC3339Nano))
+	}
+}
+
+func export(ctx context.Context, client gen.RatingServiceClient, req *gen.ExportRatingsRequest, f *os.File, header bool, cp *checkpoint, checkpointFile string) error {
+	stream, err := client.ExportRatings(ctx, req)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(f)
+	w := newRatingWriter(cp.Format, bw)
+	if header {
+		if err := w.Header(); err != nil {
+			return err
+		}
+	}
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return err
+		}
+		for _, r := range resp.Ratings {
+			if err := w.Write(model.RatingFromExportProto(r)); err != nil {
+				return err
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+		if err := f.Sync(); err != nil {
+			return err
+		}
+		offset, err := f.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		cp.Cursor = resp.Cursor
+		cp.SnapshotTime = resp.SnapshotTime.AsTime()
+		cp.Offset = offset
+		cp.Exported += len(resp.Ratings)
+		if err := writeCheckpoint(checkpointFile, cp); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	cp.Done = true
+	return writeCheckpoint(checkpointFile, cp)
+}
+
+// openOutput opens the output file. When resuming, everything written after
+// the last checkpoint is discarded so that no rating is exported twice.
+func openOutput(name string, resume bool, offset int64) (*os.File, error) {
+	if !resume {
+		return os.Create(name)
+	}
+	f, err := os.OpenFile(name, os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Truncate(offset); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+func readCheckpoint(name string) (*checkpoint, error) {
+	cp := &checkpoint{}
+	if name == "" {
+		return cp, nil
+	}
+	b, err := os.ReadFile(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return cp, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, cp); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// writeCheckpoint atomically replaces the checkpoint file.
+func writeCheckpoint(name string, cp *checkpoint) error {
+	if name == "" {
+		return nil
+	}
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
+
+type ratingWriter interface {
+	Header() error
+	Write(r *model.Rating) error
+	Flush() error
+}
+
+func newRatingWriter(format string, w io.Writer) ratingWriter {
+	if format == "ndjson" {
+		return &ndjsonWriter{json.NewEncoder(w)}
+	}
+	return &csvWriter{csv.NewWriter(w)}
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (w *csvWriter) Header() error {
+	return w.w.Write([]string{"record_id", "record_type", "user_id", "value", "updated_at", "provider_id"})
+}
+
+func (w *csvWriter) Write(r *model.Rating) error {
+	return w.w.Write([]string{r.RecordID, r.RecordType, string(r.UserID), strconv.Itoa(int(r.Value)), r.UpdatedAt.Format(time.RFC3339Nano), r.ProviderID})
+}
+
+func (w *csvWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+func (w *ndjsonWriter) Header() error { return nil }
+
+func (w *ndjsonWriter) Write(r *model.Rating) error { return w.enc.Encode(r) }
+
+func (w *ndjsonWriter) Flush() error { return nil }
+
+func transportCredentials(caFile, certFile, keyFile string) (credentials.TransportCredentials, error) {
+	if caFile == "" {
+		return insecure.NewCredentials(), nil
+	}
+	caCert, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(caCert) {
+		return nil, errors.New("failed to append CA certificate")
+	}
+	cfg := &tls.Config{RootCAs: certPool, MinVersion: tls.VersionTLS13}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(cfg), nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+)
+
+// generatorConfig configures the distributions of generated ratings.
+type generatorConfig struct {
+	Users   int
+	Movies  int
+	Ratings int
+	Seed    uint64
+
+	// MovieZipfS is the Zipf exponent of movie popularity. Higher values
+	// concentrate ratings on fewer movies. It must be greater than 1.
+	MovieZipfS float64
+	// UserZipfS is the Zipf exponent of user activity. Users are picked
+	// uniformly if it is not greater than 1.
+	UserZipfS float64
+
+	// MeanRating and QualityStdDev describe the distribution of the quality
+	// of movies, which is the average rating of a movie by unbiased users.
+	MeanRating    float64
+	QualityStdDev float64
+	// UserBiasStdDev is the standard deviation of the per-user bias added to
+	// every rating of a user.
+	UserBiasStdDev float64
+	// NoiseStdDev is the standard deviation of the noise added to every rating.
+	NoiseStdDev float64
+	MinValue    int
+	MaxValue    int
+
+	// Rate is the average number of ratings per second outside of bursts.
+	Rate float64
+	// BurstProbability is the probability that a burst starts after a rating.
+	// A burst lasts for BurstLength ratings that arrive BurstFactor times faster.
+	BurstProbability float64
+	BurstLength      int
+	BurstFactor      float64
+	// Start is the timestamp of the first rating.
+	Start time.Time
+
+	ProviderID string
+}
+
+// movie describes a generated movie.
+type movie struct {
+	ID       string
+	Title    string
+	Director string
+	Quality  float64
+}
+
+var directors = []string{"A. Smith", "B. Jones", "C. Nguyen", "D. Garcia", "E. Kowalski", "F. Tanaka", "G. Okafor", "H. Müller"}
+
+// generator generates synthetic movies and ratings. The same configuration
+// always generates the same data, including event ids.
+type generator struct {
+	cfg    generatorConfig
+	rnd    *rand.Rand
+	movies []movie
+	// popularity maps popularity ranks to movies so that popularity does not
+	// follow the movie ids.
+	popularity []int
+	movieZipf  *rand.Zipf
+	userZipf   *rand.Zipf
+	userBias   []float64
+	now        time.Time
+	burstLeft  int
+	seq        int
+}
+
+func newGenerator(cfg generatorConfig) (*generator, error) {
+	if cfg.Users <= 0 || cfg.Movies <= 0 {
+		return nil, fmt.Errorf("users and movies must be positive")
+	}
+	if cfg.MovieZipfS <= 1 {
+		return nil, fmt.Errorf("movie Zipf exponent must be greater than 1")
+	}
+	if cfg.Rate <= 0 {
+		return nil, fmt.Errorf("rate must be positive")
+	}
+	if cfg.BurstProbability > 0 && cfg.BurstFactor <= 0 {
+		return nil, fmt.Errorf("burst factor must be positive")
+	}
+	if cfg.MinValue > cfg.MaxValue {
+		return nil, fmt.Errorf("min value is greater than max value")
+	}
+	rnd := rand.New(rand.NewPCG(cfg.Seed, cfg.Seed))
+	g := &generator{
+		cfg:        cfg,
+		rnd:        rnd,
+		popularity: rnd.Perm(cfg.Movies),
+		movieZipf:  rand.NewZipf(rnd, cfg.MovieZipfS, 1, uint64(cfg.Movies-1)),
+		userBias:   make([]float64, cfg.Users),
+		now:        cfg.Start,
+	}
+	if cfg.UserZipfS > 1 {
+		g.userZipf = rand.NewZipf(rnd, cfg.UserZipfS, 1, uint64(cfg.Users-1))
+	}
+	for i := range cfg.Movies {
+		g.movies = append(g.movies, movie{
+			ID:       fmt.Sprint(i + 1),
+			Title:    fmt.Sprintf("Synthetic Movie %d", i+1),
+			Director: directors[rnd.IntN(len(directors))],
+			Quality:  cfg.MeanRating + rnd.NormFloat64()*cfg.QualityStdDev,
+		})
+	}
+	for i := range g.userBias {
+		g.userBias[i] = rnd.NormFloat64() * cfg.UserBiasStdDev
+	}
+	return g, nil
+}
+
+// next returns the next rating event.
+func (g *generator) next() model.RatingEvent {
+	m := g.movies[g.popularity[g.movieZipf.Uint64()]]
+	var user int
+	if g.userZipf != nil {
+		user = int(g.userZipf.Uint64())
+	} else {
+		user = g.rnd.IntN(g.cfg.Users)
+	}
+	v := math.Round(m.Quality + g.userBias[user] + g.rnd.NormFloat64()*g.cfg.NoiseStdDev)
+	v = max(float64(g.cfg.MinValue), min(float64(g.cfg.MaxValue), v))
+
+	e := model.RatingEvent{
+		Rating: model.Rating{
+			RecordID:   m.ID,
+			RecordType: string(model.RecordTypeMovie),
+			UserID:     model.UserID(fmt.Sprintf("user-%d", user+1)),
+			Value:      model.RatingValue(v),
+			ProviderID: g.cfg.ProviderID,
+		},
+		EventID:   fmt.Sprintf("gen-%d-%d", g.cfg.Seed, g.seq),
+		EventType: model.RatingEventTypePut,
+		Timestamp: g.now,
+	}
+	g.seq++
+	g.advance()
+	return e
+}
+
+// advance moves the clock to the arrival of the next rating. Arrivals are a
+// Poisson process whose rate is multiplied by the burst factor during bursts.
+func (g *generator) advance() {
+	rate := g.cfg.Rate
+	if g.burstLeft > 0 {
+		g.burstLeft--
+		rate *= g.cfg.BurstFactor
+	} else if g.rnd.Float64() < g.cfg.BurstProbability {
+		g.burstLeft = g.cfg.BurstLength
+	}
+	g.now = g.now.Add(time.Duration(g.rnd.ExpFloat64() / rate * float64(time.Second)))
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig(seed uint64) generatorConfig {
+	return generatorConfig{
+		Users:            50,
+		Movies:           20,
+		Seed:             seed,
+		MovieZipfS:       1.1,
+		UserZipfS:        1.2,
+		MeanRating:       3,
+		QualityStdDev:    0.8,
+		UserBiasStdDev:   0.7,
+		NoiseStdDev:      0.5,
+		MinValue:         1,
+		MaxValue:         5,
+		Rate:             10,
+		BurstProbability: 0.05,
+		BurstLength:      10,
+		BurstFactor:      20,
+		Start:            time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		ProviderID:       "synthetic",
+	}
+}
+
+func generate(t *testing.T, cfg generatorConfig, n int) ([]movie, []model.RatingEvent) {
+	t.Helper()
+	g, err := newGenerator(cfg)
+	require.NoError(t, err)
+	var events []model.RatingEvent
+	for range n {
+		events = append(events, g.next())
+	}
+	return g.movies, events
+}
+
+func TestGeneratorDeterministic(t *testing.T) {
+	movies, events := generate(t, testConfig(1), 500)
+	sameMovies, sameEvents := generate(t, testConfig(1), 500)
+	assert.Equal(t, movies, sameMovies)
+	assert.Equal(t, events, sameEvents)
+
+	otherMovies, otherEvents := generate(t, testConfig(2), 500)
+	assert.NotEqual(t, movies, otherMovies)
+	assert.NotEqual(t, events, otherEvents)
+}
+
+func TestGeneratorEvents(t *testing.T) {
+	cfg := testConfig(1)
+	movies, events := generate(t, cfg, 500)
+	assert.Len(t, movies, cfg.Movies)
+	ids := map[string]bool{}
+	for _, m := range movies {
+		ids[m.ID] = true
+	}
+	eventIDs := map[string]bool{}
+	last := cfg.Start
+	for _, e := range events {
+		assert.True(t, ids[e.RecordID], "unknown movie %s", e.RecordID)
+		assert.Equal(t, string(model.RecordTypeMovie), e.RecordType)
+		assert.Equal(t, cfg.ProviderID, e.ProviderID)
+		assert.Equal(t, model.RatingEventTypePut, e.EventType)
+		assert.GreaterOrEqual(t, int(e.Value), cfg.MinValue)
+		assert.LessOrEqual(t, int(e.Value), cfg.MaxValue)
+		assert.False(t, e.Timestamp.Before(last), "timestamps must not decrease")
+		last = e.Timestamp
+		assert.False(t, eventIDs[e.EventID], "duplicate event id %s", e.EventID)
+		eventIDs[e.EventID] = true
+	}
+}
+
+func TestNewGeneratorValidates(t *testing.T) {
+	for name, change := range map[string]func(*generatorConfig){
+		"no users":          func(c *generatorConfig) { c.Users = 0 },
+		"no movies":         func(c *generatorConfig) { c.Movies = 0 },
+		"movie zipf":        func(c *generatorConfig) { c.MovieZipfS = 1 },
+		"rate":              func(c *generatorConfig) { c.Rate = 0 },
+		"burst factor":      func(c *generatorConfig) { c.BurstFactor = 0 },
+		"min above maximum": func(c *generatorConfig) { c.MinValue = 6 },
+	} {
+		cfg := testConfig(1)
+		change(&cfg)
+		_, err := newGenerator(cfg)
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/abhishek622/movieapp/gen"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func main() {
+	var cfg generatorConfig
+	flag.IntVar(&cfg.Users, "users", 1000, "number of users")
+	flag.IntVar(&cfg.Movies, "movies", 100, "number of movies")
+	flag.IntVar(&cfg.Ratings, "ratings", 10000, "number of ratings")
+	flag.Uint64Var(&cfg.Seed, "seed", 1, "random seed, the same seed generates the same data")
+	flag.Float64Var(&cfg.MovieZipfS, "movie-zipf", 1.1, "Zipf exponent of movie popularity, greater than 1")
+	flag.Float64Var(&cfg.UserZipfS, "user-zipf", 0, "Zipf exponent of user activity, users are picked uniformly if not greater than 1")
+	flag.Float64Var(&cfg.MeanRating, "mean", 3, "mean movie quality")
+	flag.Float64Var(&cfg.QualityStdDev, "quality-stddev", 0.8, "standard deviation of movie quality")
+	flag.Float64Var(&cfg.UserBiasStdDev, "user-bias-stddev", 0.7, "standard deviation of the per-user rating bias")
+	flag.Float64Var(&cfg.NoiseStdDev, "noise-stddev", 0.5, "standard deviation of the per-rating noise")
+	flag.IntVar(&cfg.MinValue, "min", 1, "minimum rating value")
+	flag.IntVar(&cfg.MaxValue, "max", 5, "maximum rating value")
+	flag.Float64Var(&cfg.Rate, "rate", 10, "average number of ratings per second outside of bursts")
+	flag.Float64Var(&cfg.BurstProbability, "burst-prob", 0.01, "probability that a burst starts after a rating")
+	flag.IntVar(&cfg.BurstLength, "burst-length", 200, "number of ratings in a burst")
+	flag.Float64Var(&cfg.BurstFactor, "burst-factor", 20, "arrival rate multiplier during bursts")
+	flag.StringVar(&cfg.ProviderID, "provider", "synthetic", "provider id of the ratings")
+	start := flag.String("start", "", "RFC 3339 timestamp of the first rating, now if empty")
+
+	sink := flag.String("sink", "file", "where ratings are written: file or grpc")
+	out := flag.String("out", "-", "output file of the file sink, - for stdout")
+	format := flag.String("format", "ndjson", "output format of the file sink: ndjson or csv")
+	ratingAddr := flag.String("rating-addr", "localhost:8082", "rating service gRPC address of the grpc sink")
+	metadataAddr := flag.String("metadata-addr", "", "metadata service gRPC address, movies are not created if empty")
+	concurrency := flag.Int("concurrency", 8, "number of concurrent requests of the grpc sink")
+	speedup := flag.Float64("speedup", 0, "replay arrival times this many times faster than real time, as fast as possible if 0")
+	caFile := flag.String("ca", "", "CA certificate file, connections are insecure if empty")
+	certFile := flag.String("cert", "", "client certificate file")
+	keyFile := flag.String("key", "", "client key file")
+	flag.Parse()
+
+	cfg.Start = time.Now().UTC()
+	if *start != "" {
+		t, err := time.Parse(time.RFC3339, *start)
+		if err != nil {
+			log.Fatalf("invalid start time: %v", err)
+		}
+		cfg.Start = t
+	}
+	g, err := newGenerator(cfg)
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	var creds credentials.TransportCredentials
+	if *sink == "grpc" || *metadataAddr != "" {
+		if creds, err = transportCredentials(*caFile, *certFile, *keyFile); err != nil {
+			log.Fatalf("cannot load credentials: %v", err)
+		}
+	}
+	if *metadataAddr != "" {
+		if err := putMovies(ctx, *metadataAddr, creds, g.movies); err != nil {
+			log.Fatalf("cannot create movies: %v", err)
+		}
+		log.Printf("Created %d movies", len(g.movies))
+	}
+
+	var w ratingWriter
+	switch *sink {
+	case "file":
+		f := os.Stdout
+		if *out != "-" {
+			if f, err = os.Create(*out); err != nil {
+				log.Fatalf("cannot create output: %v", err)
+			}
+			defer f.Close()
+		}
+		if w, err = newFileWriter(f, *format); err != nil {
+			log.Fatal(err)
+		}
+	case "grpc":
+		conn, err := grpc.NewClient(*ratingAddr, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			log.Fatalf("cannot connect to rating service: %v", err)
+		}
+		defer conn.Close()
+		w = newGRPCWriter(ctx, gen.NewRatingServiceClient(conn), *concurrency)
+	default:
+		log.Fatalf("unsupported sink %q", *sink)
+	}
+
+	started := time.Now()
+	n := 0
+	for ; n < cfg.Ratings && ctx.Err() == nil; n++ {
+		e := g.next()
+		if *speedup > 0 {
+			wait := time.Duration(float64(e.Timestamp.Sub(cfg.Start))/(*speedup)) - time.Since(started)
+			if wait > 0 {
+				select {
+				case <-ctx.Done():
+					continue
+				case <-time.After(wait):
+				}
+			}
+		}
+		if err := w.Write(&e); err != nil {
+			log.Fatalf("cannot write rating %d: %v", n, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		log.Fatalf("cannot write ratings: %v", err)
+	}
+	log.Printf("Generated %d ratings of %d movies by %d users in %v", n, cfg.Movies, cfg.Users, time.Since(started).Round(time.Millisecond))
+}
+
+func putMovies(ctx context.Context, addr string, creds credentials.TransportCredentials, movies []movie) error {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	client := gen.NewMetadataServiceClient(conn)
+	for _, m := range movies {
+		if _, err := client.PutMetadata(ctx, &gen.PutMetadataRequest{Metadata: &gen.Metadata{
+			Id:          m.ID,
+			Title:       m.Title,
+			Description: fmt.Sprintf("A synthetic movie with an average quality of %.1f", m.Quality),
+			Director:    m.Director,
+		}}); err != nil {
+			return fmt.Errorf("movie %s: %w", m.ID, err)
+		}
+	}
+	return nil
+}
+
+type ratingWriter interface {
+	Write(e *model.RatingEvent) error
+	Close() error
+}
+
+// newFileWriter returns a writer of rating events in a format read by the rating producer.
+func newFileWriter(w io.Writer, format string) (ratingWriter, error) {
+	bw := bufio.NewWriter(w)
+	switch format {
+	case "ndjson":
+		return &ndjsonWriter{bw, json.NewEncoder(bw)}, nil
+	case "csv":
+		cw := csv.NewWriter(bw)
+		if err := cw.Write([]string{"record_id", "record_type", "user_id", "value", "provider_id", "event_type", "event_id", "timestamp"}); err != nil {
+			return nil, err
+		}
+		return &csvWriter{bw, cw}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+type ndjsonWriter struct {
+	bw  *bufio.Writer
+	enc *json.Encoder
+}
+
+func (w *ndjsonWriter) Write(e *model.RatingEvent) error { return w.enc.Encode(e) }
+
+func (w *ndjsonWriter) Close() error { return w.bw.Flush() }
+
+type csvWriter struct {
+	bw *bufio.Writer
+	cw *csv.Writer
+}
+
+func (w *csvWriter) Write(e *model.RatingEvent) error {
+	return w.cw.Write([]string{e.RecordID, e.RecordType, string(e.UserID), strconv.Itoa(int(e.Value)), e.ProviderID, string(e.EventType), e.EventID, e.Timestamp.Format(time.RFC3339Nano)})
+}
+
+func (w *csvWriter) Close() error {
+	w.cw.Flush()
+	if err := w.cw.Error(); err != nil {
+		return err
+	}
+	return w.bw.Flush()
+}
+
+// grpcWriter writes ratings with concurrent PutRating calls. Failed calls are
+// logged and counted, and make Close return an error.
+type grpcWriter struct {
+	ctx    context.Context
+	client gen.RatingServiceClient
+	events chan *model.RatingEvent
+	wg     sync.WaitGroup
+	failed atomic.Int64
+}
+
+func newGRPCWriter(ctx context.Context, client gen.RatingServiceClient, concurrency int) *grpcWriter {
+	w := &grpcWriter{ctx: ctx, client: client, events: make(chan *model.RatingEvent, concurrency)}
+	for range max(concurrency, 1) {
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			for e := range w.events {
+				if _, err := w.client.PutRating(w.ctx, &gen.PutRatingRequest{
+					UserId:      string(e.UserID),
+					RecordId:    e.RecordID,
+					RecordType:  e.RecordType,
+					RatingValue: int32(e.Value),
+					ProviderId:  e.ProviderID,
+					UpdatedAt:   timestamppb.New(e.Timestamp),
+				}); err != nil {
+					w.failed.Add(1)
+					log.Printf("put rating failed: %v", err)
+				}
+			}
+		}()
+	}
+	return w
+}
+
+func (w *grpcWriter) Write(e *model.RatingEvent) error {
+	select {
+	case <-w.ctx.Done():
+		return w.ctx.Err()
+	case w.events <- e:
+		return nil
+	}
+}
+
+func (w *grpcWriter) Close() error {
+	close(w.events)
+	w.wg.Wait()
+	if n := w.failed.Load(); n > 0 {
+		return fmt.Errorf("%d ratings failed", n)
+	}
+	return nil
+}
+
+func transportCredentials(caFile, certFile, keyFile string) (credentials.TransportCredentials, error) {
+	if caFile == "" {
+		return insecure.NewCredentials(), nil
+	}
+	caCert, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(caCert) {
+		return nil, errors.New("failed to append CA certificate")
+	}
+	cfg := &tls.Config{RootCAs: certPool, MinVersion: tls.VersionTLS13}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(cfg), nil
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+)
+
+// Input formats.
+const (
+	inputAuto   = "auto"
+	inputJSON   = "json"
+	inputNDJSON = "ndjson"
+	inputCSV    = "csv"
+)
+
+// readEvents calls fn for every event read from r in the given input format.
+// The auto format is detected from the file name, or from the first
+// character of the input if the extension is unknown. name is used in error
+// messages together with the position of an event.
+func readEvents(r io.Reader, name string, format string, fn func(pos string, e *model.RatingEvent) error) error {
+	br := bufio.NewReader(r)
+	if format == inputAuto {
+		f, err := detectFormat(br, name)
+		if err != nil {
+			return err
+		}
+		format = f
+	}
+	switch format {
+	case inputJSON:
+		return readJSONArray(br, name, fn)
+	case inputNDJSON:
+		return readNDJSON(br, name, fn)
+	case inputCSV:
+		return readCSV(br, name, fn)
+	default:
+		return fmt.Errorf("unsupported input format %q", format)
+	}
+}
+
+func detectFormat(r *bufio.Reader, name string) (string, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return inputCSV, nil
+	case ".ndjson", ".jsonl":
+		return inputNDJSON, nil
+	}
+	for {
+		b, err := r.Peek(1)
+		if err == io.EOF {
+			return inputNDJSON, nil
+		} else if err != nil {
+			return "", err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			r.ReadByte()
+		case '[':
+			return inputJSON, nil
+		case '{':
+			return inputNDJSON, nil
+		default:
+			return inputCSV, nil
+		}
+	}
+}
+
+func readJSONArray(r io.Reader, name string, fn func(string, *model.RatingEvent) error) error {
+	dec := json.NewDecoder(r)
+	if t, err := dec.Token(); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	} else if t != json.Delim('[') {
+		return fmt.Errorf("%s: expected a JSON array of events, got %v", name, t)
+	}
+	for i := 0; dec.More(); i++ {
+		var e model.RatingEvent
+		if err := dec.Decode(&e); err != nil {
+			return fmt.Errorf("%s: event %d: %w", name, i, err)
+		}
+		if err := fn(fmt.Sprintf("%s: event %d", name, i), &e); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+func readNDJSON(r io.Reader, name string, fn func(string, *model.RatingEvent) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		b := bytes.TrimSpace(scanner.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		var e model.RatingEvent
+		if err := json.Unmarshal(b, &e); err != nil {
+			return fmt.Errorf("%s: line %d: %w", name, line, err)
+		}
+		if err := fn(fmt.Sprintf("%s: line %d", name, line), &e); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// readCSV reads events from CSV with a header row. The columns are
+// record_id, record_type, user_id, value, provider_id, event_type, event_id
+// and timestamp, in any order. updated_at is accepted for timestamp so that
+// exported ratings can be produced again.
+func readCSV(r io.Reader, name string, fn func(string, *model.RatingEvent) error) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("%s: header: %w", name, err)
+	}
+	columns := map[string]int{}
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if h == "updated_at" {
+			h = "timestamp"
+		}
+		columns[h] = i
+	}
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("%s: line %d: %w", name, line, err)
+		}
+		field := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		e := &model.RatingEvent{
+			Rating: model.Rating{
+				RecordID:   field("record_id"),
+				RecordType: field("record_type"),
+				UserID:     model.UserID(field("user_id")),
+				ProviderID: field("provider_id"),
+			},
+			EventID:   field("event_id"),
+			EventType: model.RatingEventType(field("event_type")),
+		}
+		if v := field("value"); v != "" {
+			value, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("%s: line %d: invalid value %q", name, line, v)
+			}
+			e.Value = model.RatingValue(value)
+		}
+		if ts := field("timestamp"); ts != "" {
+			t, err := time.Parse(time.RFC3339Nano, ts)
+			if err != nil {
+				return fmt.Errorf("%s: line %d: invalid timestamp %q", name, line, ts)
+			}
+			e.Timestamp = t
+		}
+		if err := fn(fmt.Sprintf("%s: line %d", name, line), e); err != nil {
+			return err
+		}
+	}
+}
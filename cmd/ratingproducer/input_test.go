@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		input string
+		want  string
+	}{
+		{name: "csv extension", file: "ratings.CSV", input: "[", want: inputCSV},
+		{name: "ndjson extension", file: "ratings.ndjson", input: "[", want: inputNDJSON},
+		{name: "jsonl extension", file: "ratings.jsonl", input: "", want: inputNDJSON},
+		{name: "array", file: "ratings.json", input: "\n  [{}]", want: inputJSON},
+		{name: "object", file: "-", input: "\t{}", want: inputNDJSON},
+		{name: "header row", file: "-", input: "record_id,user_id", want: inputCSV},
+		{name: "empty", file: "-", input: " \n", want: inputNDJSON},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := detectFormat(bufio.NewReader(strings.NewReader(tt.input)), tt.file)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReadEvents(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	want := []model.RatingEvent{
+		{Rating: model.Rating{RecordID: "1", RecordType: "movie", UserID: "alice", Value: 5, ProviderID: "imdb"}, EventID: "e1", EventType: model.RatingEventTypePut, Timestamp: ts},
+		{Rating: model.Rating{RecordID: "2", RecordType: "movie", UserID: "bob"}, EventType: model.RatingEventTypeDelete},
+	}
+	tests := []struct {
+		name      string
+		format    string
+		input     string
+		wantPos   []string
+		wantError string
+	}{
+		{
+			name:   "json array",
+			format: inputJSON,
+			input: `[{"recordId":"1","recordType":"movie","userId":"alice","value":5,"providerId":"imdb","eventId":"e1","eventType":"put","timestamp":"2024-01-02T03:04:05Z"},
+				{"recordId":"2","recordType":"movie","userId":"bob","eventType":"delete"}]`,
+			wantPos: []string{"in: event 0", "in: event 1"},
+		},
+		{
+			name:      "json object",
+			format:    inputJSON,
+			input:     `{"recordId":"1"}`,
+			wantError: "expected a JSON array",
+		},
+		{
+			name:      "truncated json array",
+			format:    inputJSON,
+			input:     `[{"recordId":"1","recordType":"movie","userId":"alice","value":5,"providerId":"imdb","eventId":"e1","eventType":"put","timestamp":"2024-01-02T03:04:05Z"}`,
+			wantError: "in: event 1",
+		},
+		{
+			name:   "ndjson",
+			format: inputNDJSON,
+			input: `{"recordId":"1","recordType":"movie","userId":"alice","value":5,"providerId":"imdb","eventId":"e1","eventType":"put","timestamp":"2024-01-02T03:04:05Z"}
+
+{"recordId":"2","recordType":"movie","userId":"bob","eventType":"delete"}
+`,
+			wantPos: []string{"in: line 1", "in: line 3"},
+		},
+		{
+			name:      "malformed ndjson",
+			format:    inputNDJSON,
+			input:     "{\"recordId\":\"1\"}\n{\"recordId\":",
+			wantError: "in: line 2",
+		},
+		{
+			name:   "csv",
+			format: inputCSV,
+			input: `Event_Type,record_id,record_type,user_id,value,provider_id,event_id,updated_at
+put,1,movie,alice,5,imdb,e1,2024-01-02T03:04:05Z
+delete,2,movie,bob
+`,
+			wantPos: []string{"in: line 2", "in: line 3"},
+		},
+		{
+			name:      "csv with an invalid value",
+			format:    inputCSV,
+			input:     "record_id,value\n1,five\n",
+			wantError: `in: line 2: invalid value "five"`,
+		},
+		{
+			name:      "csv with an invalid timestamp",
+			format:    inputCSV,
+			input:     "record_id,timestamp\n1,yesterday\n",
+			wantError: `in: line 2: invalid timestamp "yesterday"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []model.RatingEvent
+			var pos []string
+			err := readEvents(strings.NewReader(tt.input), "in", tt.format, func(p string, e *model.RatingEvent) error {
+				pos = append(pos, p)
+				got = append(got, *e)
+				return nil
+			})
+			if tt.wantError != "" {
+				assert.ErrorContains(t, err, tt.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+			assert.Equal(t, tt.wantPos, pos)
+		})
+	}
+}
@@ -1,85 +1,207 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
+	"strconv"
+	"sync/atomic"
+	"time"
 
+	"github.com/abhishek622/movieapp/pkg/schemaregistry"
 	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/abhishek622/movieapp/rating/pkg/ratingevent"
 	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/google/uuid"
+	"golang.org/x/time/rate"
 )
 
+// flushTimeout is the maximum time to wait for produced events to be delivered.
+const flushTimeout = 30 * time.Second
+
 func main() {
-	fmt.Println("Creating a kafka producer")
+	brokers := flag.String("brokers", "localhost:9092", "Kafka bootstrap servers")
+	topic := flag.String("topic", "ratings", "topic to produce rating events to")
+	format := flag.String("format", "json", "payload format: json or protobuf")
+	inputFormat := flag.String("input-format", inputAuto, "input format: auto, json, ndjson or csv")
+	messageKey := flag.String("message-key", "record", "message key: record to key messages by record, or none")
+	eventsPerSecond := flag.Float64("rate", 0, "maximum number of events produced per second, unlimited if 0")
+	dryRun := flag.Bool("dry-run", false, "validate the events without producing them")
+	registryDir := flag.String("schema-registry", "../../schemas", "schema registry directory, schemas are not registered if empty")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [file ...]\n\nReads rating events from the files, or from stdin if there are none or a file is -.\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
 
-	producer, err := kafka.NewProducer(&kafka.ConfigMap{
-		"bootstrap.servers": "localhost:9092", // 1️⃣ add port
-	})
-	if err != nil {
-		log.Fatalf("cannot create producer: %v", err)
+	contentType := ratingevent.ContentTypeJSON
+	if *format == "protobuf" {
+		contentType = ratingevent.ContentTypeProtobuf
+	} else if *format != "json" {
+		log.Fatalf("unsupported format %q", *format)
+	}
+	if *messageKey != "record" && *messageKey != "none" {
+		log.Fatalf("unsupported message key %q", *messageKey)
+	}
+	inputs := flag.Args()
+	if len(inputs) == 0 {
+		inputs = []string{"-"}
 	}
-	defer producer.Close()
 
-	// 2️⃣ delivery-report goroutine
-	go func() {
-		for e := range producer.Events() {
-			switch ev := e.(type) {
-			case *kafka.Message:
-				if ev.TopicPartition.Error != nil {
-					log.Printf("delivery failed: %v", ev.TopicPartition)
-				}
+	headers := []kafka.Header{{Key: ratingevent.HeaderContentType, Value: []byte(contentType)}}
+	if *registryDir != "" {
+		registry := schemaregistry.New(*registryDir)
+		if *dryRun {
+			if err := registry.Check(ratingevent.Subject, ratingevent.Descriptor()); err != nil {
+				log.Fatalf("rating event schema is not compatible: %v", err)
 			}
+		} else {
+			version, err := registry.Register(ratingevent.Subject, ratingevent.Descriptor())
+			if err != nil {
+				log.Fatalf("cannot register rating event schema: %v", err)
+			}
+			fmt.Printf("Using rating event schema version %d\n", version)
+			headers = append(headers, kafka.Header{Key: ratingevent.HeaderSchemaVersion, Value: []byte(strconv.Itoa(version))})
 		}
-	}()
-
-	const fileName = "ratingsdata.json"
-	fmt.Println("Reading rating events from file " + fileName)
+	}
 
-	ratingEvents, err := readRatingEvents(fileName)
-	if err != nil {
-		log.Fatalf("cannot read events: %v", err)
+	p := &producer{topic: *topic, contentType: contentType, headers: headers, keyByRecord: *messageKey == "record"}
+	if *eventsPerSecond > 0 {
+		p.limiter = rate.NewLimiter(rate.Limit(*eventsPerSecond), 1)
+	}
+	if !*dryRun {
+		kp, err := kafka.NewProducer(&kafka.ConfigMap{
+			"bootstrap.servers":  *brokers,
+			"enable.idempotence": true,
+		})
+		if err != nil {
+			log.Fatalf("cannot create producer: %v", err)
+		}
+		defer kp.Close()
+		p.kafka = kp
+		go p.deliveryReports()
 	}
 
-	const topic = "ratings"
-	if err := produceRatingEvents(topic, producer, ratingEvents); err != nil {
-		log.Fatalf("cannot produce events: %v", err)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	readErr := false
+	for _, name := range inputs {
+		if err := p.produceFile(ctx, name, *inputFormat); err != nil {
+			log.Printf("cannot read events: %v", err)
+			readErr = true
+			break
+		}
 	}
 
-	// 3️⃣ wait for outstanding messages or timeout
-	remaining := producer.Flush(10_000) // 10 s
-	if remaining != 0 {
-		log.Fatalf("still %d messages not delivered", remaining)
+	if p.kafka != nil {
+		if remaining := p.kafka.Flush(int(flushTimeout.Milliseconds())); remaining != 0 {
+			p.failed.Add(int64(remaining))
+			log.Printf("%d events not delivered", remaining)
+		}
+	}
+	if *dryRun {
+		fmt.Printf("%d valid events, %d invalid events\n", p.produced.Load(), p.invalid.Load())
+	} else {
+		fmt.Printf("Produced %d events to %s, skipped %d invalid events, %d failed\n", p.produced.Load()-p.failed.Load(), *topic, p.invalid.Load(), p.failed.Load())
+	}
+	if readErr || p.invalid.Load() > 0 || p.failed.Load() > 0 {
+		os.Exit(1)
 	}
-	fmt.Println("all events produced")
 }
 
-func readRatingEvents(fileName string) ([]model.RatingEvent, error) {
-	f, err := os.Open(fileName)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
+type producer struct {
+	kafka       *kafka.Producer
+	topic       string
+	contentType string
+	headers     []kafka.Header
+	keyByRecord bool
+	limiter     *rate.Limiter
 
-	var ratings []model.RatingEvent
-	if err := json.NewDecoder(f).Decode(&ratings); err != nil {
-		return nil, err
-	}
-	return ratings, nil
+	produced atomic.Int64
+	invalid  atomic.Int64
+	failed   atomic.Int64
 }
 
-func produceRatingEvents(topic string, producer *kafka.Producer, events []model.RatingEvent) error {
-	for _, re := range events {
-		payload, err := json.Marshal(re)
+func (p *producer) produceFile(ctx context.Context, name string, format string) error {
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
 		if err != nil {
 			return err
 		}
-		if err := producer.Produce(&kafka.Message{
-			TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
-			Value:          payload,
-		}, nil); err != nil {
+		defer f.Close()
+		r = f
+	}
+	return readEvents(r, name, format, func(pos string, e *model.RatingEvent) error {
+		if err := ctx.Err(); err != nil {
 			return err
 		}
+		return p.produce(ctx, pos, e)
+	})
+}
+
+// produce validates an event and writes it to the topic unless the producer
+// runs dry. Invalid events are skipped.
+func (p *producer) produce(ctx context.Context, pos string, e *model.RatingEvent) error {
+	if err := ratingevent.Validate(e); err != nil {
+		log.Printf("%s: invalid event: %v", pos, err)
+		p.invalid.Add(1)
+		return nil
+	}
+	// Event ids let the rating service drop redelivered events and timestamps
+	// decide which of two ratings of a user is the latest one.
+	if e.EventID == "" {
+		e.EventID = uuid.NewString()
+	}
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now().UTC()
+	}
+	payload, err := ratingevent.Encode(e, p.contentType)
+	if err != nil {
+		log.Printf("%s: cannot encode event: %v", pos, err)
+		p.invalid.Add(1)
+		return nil
+	}
+	if p.kafka == nil {
+		p.produced.Add(1)
+		return nil
+	}
+	if p.limiter != nil {
+		if err := p.limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	msg := &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &p.topic, Partition: kafka.PartitionAny},
+		Value:          payload,
+		Headers:        p.headers,
+	}
+	if p.keyByRecord {
+		msg.Key = ratingevent.Key(e)
+	}
+	for {
+		err := p.kafka.Produce(msg, nil)
+		if kerr, ok := err.(kafka.Error); ok && kerr.Code() == kafka.ErrQueueFull {
+			// Wait for queued events to be delivered.
+			p.kafka.Flush(100)
+			continue
+		} else if err != nil {
+			return err
+		}
+		p.produced.Add(1)
+		return nil
+	}
+}
+
+func (p *producer) deliveryReports() {
+	for e := range p.kafka.Events() {
+		if ev, ok := e.(*kafka.Message); ok && ev.TopicPartition.Error != nil {
+			p.failed.Add(1)
+			log.Printf("delivery failed: %v", ev.TopicPartition)
+		}
 	}
-	return nil
 }
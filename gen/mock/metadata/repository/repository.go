@@ -51,21 +51,51 @@ func (m *MockmetadataRepository) Get(ctx context.Context, id string) (*model.Met
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockmetadataRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+func (mr *MockmetadataRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockmetadataRepository)(nil).Get), ctx, id)
 }
 
-// Put mocks base method.
-func (m *MockmetadataRepository) Put(ctx context.Context, id string, metadata *model.Metadata) error {
+// GetBatch mocks base method.
+func (m *MockmetadataRepository) GetBatch(ctx context.Context, ids []string) ([]*model.Metadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatch", ctx, ids)
+	ret0, _ := ret[0].([]*model.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBatch indicates an expected call of GetBatch.
+func (mr *MockmetadataRepositoryMockRecorder) GetBatch(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatch", reflect.TypeOf((*MockmetadataRepository)(nil).GetBatch), ctx, ids)
+}
+
+// List mocks base method.
+func (m *MockmetadataRepository) List(ctx context.Context, filter model.Filter, after *model.TitleCursor, limit int) ([]*model.Metadata, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Put", ctx, id, metadata)
+	ret := m.ctrl.Call(m, "List", ctx, filter, after, limit)
+	ret0, _ := ret[0].([]*model.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockmetadataRepositoryMockRecorder) List(ctx, filter, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockmetadataRepository)(nil).List), ctx, filter, after, limit)
+}
+
+// Put mocks base method.
+func (m_2 *MockmetadataRepository) Put(ctx context.Context, id string, m *model.Metadata) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "Put", ctx, id, m)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Put indicates an expected call of Put.
-func (mr *MockmetadataRepositoryMockRecorder) Put(ctx, id, metadata interface{}) *gomock.Call {
+func (mr *MockmetadataRepositoryMockRecorder) Put(ctx, id, m any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockmetadataRepository)(nil).Put), ctx, id, metadata)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockmetadataRepository)(nil).Put), ctx, id, m)
 }
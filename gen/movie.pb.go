@@ -9,6 +9,8 @@ package gen
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,12 +23,68 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type SectionStatus int32
+
+const (
+	SectionStatus_SECTION_STATUS_UNSPECIFIED SectionStatus = 0
+	SectionStatus_SECTION_STATUS_OK          SectionStatus = 1
+	SectionStatus_SECTION_STATUS_NOT_FOUND   SectionStatus = 2
+	SectionStatus_SECTION_STATUS_UNAVAILABLE SectionStatus = 3
+	SectionStatus_SECTION_STATUS_STALE       SectionStatus = 4
+)
+
+// Enum value maps for SectionStatus.
+var (
+	SectionStatus_name = map[int32]string{
+		0: "SECTION_STATUS_UNSPECIFIED",
+		1: "SECTION_STATUS_OK",
+		2: "SECTION_STATUS_NOT_FOUND",
+		3: "SECTION_STATUS_UNAVAILABLE",
+		4: "SECTION_STATUS_STALE",
+	}
+	SectionStatus_value = map[string]int32{
+		"SECTION_STATUS_UNSPECIFIED": 0,
+		"SECTION_STATUS_OK":          1,
+		"SECTION_STATUS_NOT_FOUND":   2,
+		"SECTION_STATUS_UNAVAILABLE": 3,
+		"SECTION_STATUS_STALE":       4,
+	}
+)
+
+func (x SectionStatus) Enum() *SectionStatus {
+	p := new(SectionStatus)
+	*p = x
+	return p
+}
+
+func (x SectionStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SectionStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_movie_proto_enumTypes[0].Descriptor()
+}
+
+func (SectionStatus) Type() protoreflect.EnumType {
+	return &file_movie_proto_enumTypes[0]
+}
+
+func (x SectionStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SectionStatus.Descriptor instead.
+func (SectionStatus) EnumDescriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{0}
+}
+
 type Metadata struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
 	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
 	Director      string                 `protobuf:"bytes,4,opt,name=director,proto3" json:"director,omitempty"`
+	Genres        []string               `protobuf:"bytes,5,rep,name=genres,proto3" json:"genres,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -89,12 +147,21 @@ func (x *Metadata) GetDirector() string {
 	return ""
 }
 
+func (x *Metadata) GetGenres() []string {
+	if x != nil {
+		return x.Genres
+	}
+	return nil
+}
+
 type MovieDetails struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Rating        float64                `protobuf:"fixed64,1,opt,name=rating,proto3" json:"rating,omitempty"`
-	Metadata      *Metadata              `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Rating         float64                `protobuf:"fixed64,1,opt,name=rating,proto3" json:"rating,omitempty"`
+	Metadata       *Metadata              `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	MetadataStatus SectionStatus          `protobuf:"varint,3,opt,name=metadata_status,json=metadataStatus,proto3,enum=SectionStatus" json:"metadata_status,omitempty"`
+	RatingStatus   SectionStatus          `protobuf:"varint,4,opt,name=rating_status,json=ratingStatus,proto3,enum=SectionStatus" json:"rating_status,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *MovieDetails) Reset() {
@@ -141,6 +208,20 @@ func (x *MovieDetails) GetMetadata() *Metadata {
 	return nil
 }
 
+func (x *MovieDetails) GetMetadataStatus() SectionStatus {
+	if x != nil {
+		return x.MetadataStatus
+	}
+	return SectionStatus_SECTION_STATUS_UNSPECIFIED
+}
+
+func (x *MovieDetails) GetRatingStatus() SectionStatus {
+	if x != nil {
+		return x.RatingStatus
+	}
+	return SectionStatus_SECTION_STATUS_UNSPECIFIED
+}
+
 type GetMetadataRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	MovieId       string                 `protobuf:"bytes,1,opt,name=movie_id,json=movieId,proto3" json:"movie_id,omitempty"`
@@ -309,28 +390,30 @@ func (*PutMetadataResponse) Descriptor() ([]byte, []int) {
 	return file_movie_proto_rawDescGZIP(), []int{5}
 }
 
-type GetAggregatedRatingRequest struct {
+type ListMetadataRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	RecordId      string                 `protobuf:"bytes,1,opt,name=record_id,json=recordId,proto3" json:"record_id,omitempty"`
-	RecordType    string                 `protobuf:"bytes,2,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	Director      string                 `protobuf:"bytes,1,opt,name=director,proto3" json:"director,omitempty"`
+	Genre         string                 `protobuf:"bytes,2,opt,name=genre,proto3" json:"genre,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAggregatedRatingRequest) Reset() {
-	*x = GetAggregatedRatingRequest{}
+func (x *ListMetadataRequest) Reset() {
+	*x = ListMetadataRequest{}
 	mi := &file_movie_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAggregatedRatingRequest) String() string {
+func (x *ListMetadataRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAggregatedRatingRequest) ProtoMessage() {}
+func (*ListMetadataRequest) ProtoMessage() {}
 
-func (x *GetAggregatedRatingRequest) ProtoReflect() protoreflect.Message {
+func (x *ListMetadataRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_movie_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -342,46 +425,61 @@ func (x *GetAggregatedRatingRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAggregatedRatingRequest.ProtoReflect.Descriptor instead.
-func (*GetAggregatedRatingRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListMetadataRequest.ProtoReflect.Descriptor instead.
+func (*ListMetadataRequest) Descriptor() ([]byte, []int) {
 	return file_movie_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *GetAggregatedRatingRequest) GetRecordId() string {
+func (x *ListMetadataRequest) GetDirector() string {
 	if x != nil {
-		return x.RecordId
+		return x.Director
 	}
 	return ""
 }
 
-func (x *GetAggregatedRatingRequest) GetRecordType() string {
+func (x *ListMetadataRequest) GetGenre() string {
 	if x != nil {
-		return x.RecordType
+		return x.Genre
 	}
 	return ""
 }
 
-type GetAggregatedRatingResponse struct {
+func (x *ListMetadataRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListMetadataRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListMetadataResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	RatingValue   float64                `protobuf:"fixed64,1,opt,name=rating_value,json=ratingValue,proto3" json:"rating_value,omitempty"`
+	Metadata      []*Metadata            `protobuf:"bytes,1,rep,name=metadata,proto3" json:"metadata,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAggregatedRatingResponse) Reset() {
-	*x = GetAggregatedRatingResponse{}
+func (x *ListMetadataResponse) Reset() {
+	*x = ListMetadataResponse{}
 	mi := &file_movie_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAggregatedRatingResponse) String() string {
+func (x *ListMetadataResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAggregatedRatingResponse) ProtoMessage() {}
+func (*ListMetadataResponse) ProtoMessage() {}
 
-func (x *GetAggregatedRatingResponse) ProtoReflect() protoreflect.Message {
+func (x *ListMetadataResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_movie_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -393,42 +491,46 @@ func (x *GetAggregatedRatingResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAggregatedRatingResponse.ProtoReflect.Descriptor instead.
-func (*GetAggregatedRatingResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListMetadataResponse.ProtoReflect.Descriptor instead.
+func (*ListMetadataResponse) Descriptor() ([]byte, []int) {
 	return file_movie_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *GetAggregatedRatingResponse) GetRatingValue() float64 {
+func (x *ListMetadataResponse) GetMetadata() []*Metadata {
 	if x != nil {
-		return x.RatingValue
+		return x.Metadata
 	}
-	return 0
+	return nil
 }
 
-type PutRatingRequest struct {
+func (x *ListMetadataResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetMetadataBatchRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	RecordId      string                 `protobuf:"bytes,2,opt,name=record_id,json=recordId,proto3" json:"record_id,omitempty"`
-	RecordType    string                 `protobuf:"bytes,3,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
-	RatingValue   int32                  `protobuf:"varint,4,opt,name=rating_value,json=ratingValue,proto3" json:"rating_value,omitempty"`
+	MovieIds      []string               `protobuf:"bytes,1,rep,name=movie_ids,json=movieIds,proto3" json:"movie_ids,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PutRatingRequest) Reset() {
-	*x = PutRatingRequest{}
+func (x *GetMetadataBatchRequest) Reset() {
+	*x = GetMetadataBatchRequest{}
 	mi := &file_movie_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PutRatingRequest) String() string {
+func (x *GetMetadataBatchRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PutRatingRequest) ProtoMessage() {}
+func (*GetMetadataBatchRequest) ProtoMessage() {}
 
-func (x *PutRatingRequest) ProtoReflect() protoreflect.Message {
+func (x *GetMetadataBatchRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_movie_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -440,60 +542,155 @@ func (x *PutRatingRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PutRatingRequest.ProtoReflect.Descriptor instead.
-func (*PutRatingRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetMetadataBatchRequest.ProtoReflect.Descriptor instead.
+func (*GetMetadataBatchRequest) Descriptor() ([]byte, []int) {
 	return file_movie_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *PutRatingRequest) GetUserId() string {
+func (x *GetMetadataBatchRequest) GetMovieIds() []string {
 	if x != nil {
-		return x.UserId
+		return x.MovieIds
 	}
-	return ""
+	return nil
 }
 
-func (x *PutRatingRequest) GetRecordId() string {
+type GetMetadataBatchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The metadata of the movies that are found, in the order of the request.
+	Metadata      []*Metadata `protobuf:"bytes,1,rep,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMetadataBatchResponse) Reset() {
+	*x = GetMetadataBatchResponse{}
+	mi := &file_movie_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetadataBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetadataBatchResponse) ProtoMessage() {}
+
+func (x *GetMetadataBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetadataBatchResponse.ProtoReflect.Descriptor instead.
+func (*GetMetadataBatchResponse) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetMetadataBatchResponse) GetMetadata() []*Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type GetAggregatedRatingRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	RecordId         string                 `protobuf:"bytes,1,opt,name=record_id,json=recordId,proto3" json:"record_id,omitempty"`
+	RecordType       string                 `protobuf:"bytes,2,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	IncludeProviders bool                   `protobuf:"varint,3,opt,name=include_providers,json=includeProviders,proto3" json:"include_providers,omitempty"`
+	RollUp           bool                   `protobuf:"varint,4,opt,name=roll_up,json=rollUp,proto3" json:"roll_up,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetAggregatedRatingRequest) Reset() {
+	*x = GetAggregatedRatingRequest{}
+	mi := &file_movie_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAggregatedRatingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAggregatedRatingRequest) ProtoMessage() {}
+
+func (x *GetAggregatedRatingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAggregatedRatingRequest.ProtoReflect.Descriptor instead.
+func (*GetAggregatedRatingRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetAggregatedRatingRequest) GetRecordId() string {
 	if x != nil {
 		return x.RecordId
 	}
 	return ""
 }
 
-func (x *PutRatingRequest) GetRecordType() string {
+func (x *GetAggregatedRatingRequest) GetRecordType() string {
 	if x != nil {
 		return x.RecordType
 	}
 	return ""
 }
 
-func (x *PutRatingRequest) GetRatingValue() int32 {
+func (x *GetAggregatedRatingRequest) GetIncludeProviders() bool {
 	if x != nil {
-		return x.RatingValue
+		return x.IncludeProviders
 	}
-	return 0
+	return false
 }
 
-type PutRatingResponse struct {
+func (x *GetAggregatedRatingRequest) GetRollUp() bool {
+	if x != nil {
+		return x.RollUp
+	}
+	return false
+}
+
+type GetAggregatedRatingResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	RatingValue   float64                `protobuf:"fixed64,1,opt,name=rating_value,json=ratingValue,proto3" json:"rating_value,omitempty"`
+	VoteCount     int32                  `protobuf:"varint,2,opt,name=vote_count,json=voteCount,proto3" json:"vote_count,omitempty"`
+	Providers     []*ProviderRating      `protobuf:"bytes,3,rep,name=providers,proto3" json:"providers,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PutRatingResponse) Reset() {
-	*x = PutRatingResponse{}
-	mi := &file_movie_proto_msgTypes[9]
+func (x *GetAggregatedRatingResponse) Reset() {
+	*x = GetAggregatedRatingResponse{}
+	mi := &file_movie_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PutRatingResponse) String() string {
+func (x *GetAggregatedRatingResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PutRatingResponse) ProtoMessage() {}
+func (*GetAggregatedRatingResponse) ProtoMessage() {}
 
-func (x *PutRatingResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_movie_proto_msgTypes[9]
+func (x *GetAggregatedRatingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -504,33 +701,55 @@ func (x *PutRatingResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PutRatingResponse.ProtoReflect.Descriptor instead.
-func (*PutRatingResponse) Descriptor() ([]byte, []int) {
-	return file_movie_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use GetAggregatedRatingResponse.ProtoReflect.Descriptor instead.
+func (*GetAggregatedRatingResponse) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{11}
 }
 
-type GetMovieDetailsRequest struct {
+func (x *GetAggregatedRatingResponse) GetRatingValue() float64 {
+	if x != nil {
+		return x.RatingValue
+	}
+	return 0
+}
+
+func (x *GetAggregatedRatingResponse) GetVoteCount() int32 {
+	if x != nil {
+		return x.VoteCount
+	}
+	return 0
+}
+
+func (x *GetAggregatedRatingResponse) GetProviders() []*ProviderRating {
+	if x != nil {
+		return x.Providers
+	}
+	return nil
+}
+
+type GetAggregatedRatingsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MovieId       string                 `protobuf:"bytes,1,opt,name=movie_id,json=movieId,proto3" json:"movie_id,omitempty"`
+	RecordIds     []string               `protobuf:"bytes,1,rep,name=record_ids,json=recordIds,proto3" json:"record_ids,omitempty"`
+	RecordType    string                 `protobuf:"bytes,2,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetMovieDetailsRequest) Reset() {
-	*x = GetMovieDetailsRequest{}
-	mi := &file_movie_proto_msgTypes[10]
+func (x *GetAggregatedRatingsRequest) Reset() {
+	*x = GetAggregatedRatingsRequest{}
+	mi := &file_movie_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetMovieDetailsRequest) String() string {
+func (x *GetAggregatedRatingsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetMovieDetailsRequest) ProtoMessage() {}
+func (*GetAggregatedRatingsRequest) ProtoMessage() {}
 
-func (x *GetMovieDetailsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_movie_proto_msgTypes[10]
+func (x *GetAggregatedRatingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -541,40 +760,47 @@ func (x *GetMovieDetailsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetMovieDetailsRequest.ProtoReflect.Descriptor instead.
-func (*GetMovieDetailsRequest) Descriptor() ([]byte, []int) {
-	return file_movie_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use GetAggregatedRatingsRequest.ProtoReflect.Descriptor instead.
+func (*GetAggregatedRatingsRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *GetMovieDetailsRequest) GetMovieId() string {
+func (x *GetAggregatedRatingsRequest) GetRecordIds() []string {
 	if x != nil {
-		return x.MovieId
+		return x.RecordIds
+	}
+	return nil
+}
+
+func (x *GetAggregatedRatingsRequest) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
 	}
 	return ""
 }
 
-type GetMovieDetailsResponse struct {
+type GetAggregatedRatingsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MovieDetails  *MovieDetails          `protobuf:"bytes,1,opt,name=movie_details,json=movieDetails,proto3" json:"movie_details,omitempty"`
+	Records       []*RatedRecord         `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetMovieDetailsResponse) Reset() {
-	*x = GetMovieDetailsResponse{}
-	mi := &file_movie_proto_msgTypes[11]
+func (x *GetAggregatedRatingsResponse) Reset() {
+	*x = GetAggregatedRatingsResponse{}
+	mi := &file_movie_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetMovieDetailsResponse) String() string {
+func (x *GetAggregatedRatingsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetMovieDetailsResponse) ProtoMessage() {}
+func (*GetAggregatedRatingsResponse) ProtoMessage() {}
 
-func (x *GetMovieDetailsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_movie_proto_msgTypes[11]
+func (x *GetAggregatedRatingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -585,63 +811,2413 @@ func (x *GetMovieDetailsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetMovieDetailsResponse.ProtoReflect.Descriptor instead.
-func (*GetMovieDetailsResponse) Descriptor() ([]byte, []int) {
-	return file_movie_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use GetAggregatedRatingsResponse.ProtoReflect.Descriptor instead.
+func (*GetAggregatedRatingsResponse) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *GetMovieDetailsResponse) GetMovieDetails() *MovieDetails {
+func (x *GetAggregatedRatingsResponse) GetRecords() []*RatedRecord {
 	if x != nil {
-		return x.MovieDetails
+		return x.Records
 	}
 	return nil
 }
 
-var File_movie_proto protoreflect.FileDescriptor
+type ProviderRating struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProviderId    string                 `protobuf:"bytes,1,opt,name=provider_id,json=providerId,proto3" json:"provider_id,omitempty"`
+	RatingValue   float64                `protobuf:"fixed64,2,opt,name=rating_value,json=ratingValue,proto3" json:"rating_value,omitempty"`
+	VoteCount     int32                  `protobuf:"varint,3,opt,name=vote_count,json=voteCount,proto3" json:"vote_count,omitempty"`
+	Weight        float64                `protobuf:"fixed64,4,opt,name=weight,proto3" json:"weight,omitempty"`
+	Enabled       bool                   `protobuf:"varint,5,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_movie_proto_rawDesc = "" +
+func (x *ProviderRating) Reset() {
+	*x = ProviderRating{}
+	mi := &file_movie_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProviderRating) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProviderRating) ProtoMessage() {}
+
+func (x *ProviderRating) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProviderRating.ProtoReflect.Descriptor instead.
+func (*ProviderRating) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ProviderRating) GetProviderId() string {
+	if x != nil {
+		return x.ProviderId
+	}
+	return ""
+}
+
+func (x *ProviderRating) GetRatingValue() float64 {
+	if x != nil {
+		return x.RatingValue
+	}
+	return 0
+}
+
+func (x *ProviderRating) GetVoteCount() int32 {
+	if x != nil {
+		return x.VoteCount
+	}
+	return 0
+}
+
+func (x *ProviderRating) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *ProviderRating) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+type PutRatingRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	UserId      string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	RecordId    string                 `protobuf:"bytes,2,opt,name=record_id,json=recordId,proto3" json:"record_id,omitempty"`
+	RecordType  string                 `protobuf:"bytes,3,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	RatingValue int32                  `protobuf:"varint,4,opt,name=rating_value,json=ratingValue,proto3" json:"rating_value,omitempty"`
+	// provider_id is the provider of the rating, the rating service itself if empty.
+	ProviderId string `protobuf:"bytes,5,opt,name=provider_id,json=providerId,proto3" json:"provider_id,omitempty"`
+	// updated_at is the time of the rating, the time of the request if unset.
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutRatingRequest) Reset() {
+	*x = PutRatingRequest{}
+	mi := &file_movie_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutRatingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutRatingRequest) ProtoMessage() {}
+
+func (x *PutRatingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutRatingRequest.ProtoReflect.Descriptor instead.
+func (*PutRatingRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PutRatingRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PutRatingRequest) GetRecordId() string {
+	if x != nil {
+		return x.RecordId
+	}
+	return ""
+}
+
+func (x *PutRatingRequest) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *PutRatingRequest) GetRatingValue() int32 {
+	if x != nil {
+		return x.RatingValue
+	}
+	return 0
+}
+
+func (x *PutRatingRequest) GetProviderId() string {
+	if x != nil {
+		return x.ProviderId
+	}
+	return ""
+}
+
+func (x *PutRatingRequest) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type PutRatingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutRatingResponse) Reset() {
+	*x = PutRatingResponse{}
+	mi := &file_movie_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutRatingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutRatingResponse) ProtoMessage() {}
+
+func (x *PutRatingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutRatingResponse.ProtoReflect.Descriptor instead.
+func (*PutRatingResponse) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{16}
+}
+
+type DeleteRatingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	RecordId      string                 `protobuf:"bytes,2,opt,name=record_id,json=recordId,proto3" json:"record_id,omitempty"`
+	RecordType    string                 `protobuf:"bytes,3,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRatingRequest) Reset() {
+	*x = DeleteRatingRequest{}
+	mi := &file_movie_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRatingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRatingRequest) ProtoMessage() {}
+
+func (x *DeleteRatingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRatingRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRatingRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DeleteRatingRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DeleteRatingRequest) GetRecordId() string {
+	if x != nil {
+		return x.RecordId
+	}
+	return ""
+}
+
+func (x *DeleteRatingRequest) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+type DeleteRatingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRatingResponse) Reset() {
+	*x = DeleteRatingResponse{}
+	mi := &file_movie_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRatingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRatingResponse) ProtoMessage() {}
+
+func (x *DeleteRatingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRatingResponse.ProtoReflect.Descriptor instead.
+func (*DeleteRatingResponse) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{18}
+}
+
+type GetTopRatedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RecordType    string                 `protobuf:"bytes,1,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	MinVoteCount  int32                  `protobuf:"varint,3,opt,name=min_vote_count,json=minVoteCount,proto3" json:"min_vote_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTopRatedRequest) Reset() {
+	*x = GetTopRatedRequest{}
+	mi := &file_movie_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTopRatedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTopRatedRequest) ProtoMessage() {}
+
+func (x *GetTopRatedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTopRatedRequest.ProtoReflect.Descriptor instead.
+func (*GetTopRatedRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetTopRatedRequest) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *GetTopRatedRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetTopRatedRequest) GetMinVoteCount() int32 {
+	if x != nil {
+		return x.MinVoteCount
+	}
+	return 0
+}
+
+type RatedRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RecordId      string                 `protobuf:"bytes,1,opt,name=record_id,json=recordId,proto3" json:"record_id,omitempty"`
+	RecordType    string                 `protobuf:"bytes,2,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	RatingValue   float64                `protobuf:"fixed64,3,opt,name=rating_value,json=ratingValue,proto3" json:"rating_value,omitempty"`
+	VoteCount     int32                  `protobuf:"varint,4,opt,name=vote_count,json=voteCount,proto3" json:"vote_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RatedRecord) Reset() {
+	*x = RatedRecord{}
+	mi := &file_movie_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RatedRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RatedRecord) ProtoMessage() {}
+
+func (x *RatedRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RatedRecord.ProtoReflect.Descriptor instead.
+func (*RatedRecord) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RatedRecord) GetRecordId() string {
+	if x != nil {
+		return x.RecordId
+	}
+	return ""
+}
+
+func (x *RatedRecord) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *RatedRecord) GetRatingValue() float64 {
+	if x != nil {
+		return x.RatingValue
+	}
+	return 0
+}
+
+func (x *RatedRecord) GetVoteCount() int32 {
+	if x != nil {
+		return x.VoteCount
+	}
+	return 0
+}
+
+type GetTopRatedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       []*RatedRecord         `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTopRatedResponse) Reset() {
+	*x = GetTopRatedResponse{}
+	mi := &file_movie_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTopRatedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTopRatedResponse) ProtoMessage() {}
+
+func (x *GetTopRatedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTopRatedResponse.ProtoReflect.Descriptor instead.
+func (*GetTopRatedResponse) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetTopRatedResponse) GetRecords() []*RatedRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+type WatchAggregatedRatingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RecordId      string                 `protobuf:"bytes,1,opt,name=record_id,json=recordId,proto3" json:"record_id,omitempty"`
+	RecordType    string                 `protobuf:"bytes,2,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	MinIntervalMs int64                  `protobuf:"varint,3,opt,name=min_interval_ms,json=minIntervalMs,proto3" json:"min_interval_ms,omitempty"`
+	Coalesce      bool                   `protobuf:"varint,4,opt,name=coalesce,proto3" json:"coalesce,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchAggregatedRatingRequest) Reset() {
+	*x = WatchAggregatedRatingRequest{}
+	mi := &file_movie_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchAggregatedRatingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchAggregatedRatingRequest) ProtoMessage() {}
+
+func (x *WatchAggregatedRatingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchAggregatedRatingRequest.ProtoReflect.Descriptor instead.
+func (*WatchAggregatedRatingRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *WatchAggregatedRatingRequest) GetRecordId() string {
+	if x != nil {
+		return x.RecordId
+	}
+	return ""
+}
+
+func (x *WatchAggregatedRatingRequest) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *WatchAggregatedRatingRequest) GetMinIntervalMs() int64 {
+	if x != nil {
+		return x.MinIntervalMs
+	}
+	return 0
+}
+
+func (x *WatchAggregatedRatingRequest) GetCoalesce() bool {
+	if x != nil {
+		return x.Coalesce
+	}
+	return false
+}
+
+type WatchAggregatedRatingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RatingValue   float64                `protobuf:"fixed64,1,opt,name=rating_value,json=ratingValue,proto3" json:"rating_value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchAggregatedRatingResponse) Reset() {
+	*x = WatchAggregatedRatingResponse{}
+	mi := &file_movie_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchAggregatedRatingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchAggregatedRatingResponse) ProtoMessage() {}
+
+func (x *WatchAggregatedRatingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchAggregatedRatingResponse.ProtoReflect.Descriptor instead.
+func (*WatchAggregatedRatingResponse) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *WatchAggregatedRatingResponse) GetRatingValue() float64 {
+	if x != nil {
+		return x.RatingValue
+	}
+	return 0
+}
+
+type Review struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RecordId      string                 `protobuf:"bytes,2,opt,name=record_id,json=recordId,proto3" json:"record_id,omitempty"`
+	RecordType    string                 `protobuf:"bytes,3,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	UserId        string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Text          string                 `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
+	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	HelpfulVotes  int32                  `protobuf:"varint,7,opt,name=helpful_votes,json=helpfulVotes,proto3" json:"helpful_votes,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Review) Reset() {
+	*x = Review{}
+	mi := &file_movie_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Review) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Review) ProtoMessage() {}
+
+func (x *Review) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Review.ProtoReflect.Descriptor instead.
+func (*Review) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *Review) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Review) GetRecordId() string {
+	if x != nil {
+		return x.RecordId
+	}
+	return ""
+}
+
+func (x *Review) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *Review) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Review) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Review) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Review) GetHelpfulVotes() int32 {
+	if x != nil {
+		return x.HelpfulVotes
+	}
+	return 0
+}
+
+func (x *Review) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Review) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+type CreateReviewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	RecordId      string                 `protobuf:"bytes,2,opt,name=record_id,json=recordId,proto3" json:"record_id,omitempty"`
+	RecordType    string                 `protobuf:"bytes,3,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	Text          string                 `protobuf:"bytes,4,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateReviewRequest) Reset() {
+	*x = CreateReviewRequest{}
+	mi := &file_movie_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateReviewRequest) ProtoMessage() {}
+
+func (x *CreateReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateReviewRequest.ProtoReflect.Descriptor instead.
+func (*CreateReviewRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *CreateReviewRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateReviewRequest) GetRecordId() string {
+	if x != nil {
+		return x.RecordId
+	}
+	return ""
+}
+
+func (x *CreateReviewRequest) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *CreateReviewRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type CreateReviewResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Review        *Review                `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateReviewResponse) Reset() {
+	*x = CreateReviewResponse{}
+	mi := &file_movie_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateReviewResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateReviewResponse) ProtoMessage() {}
+
+func (x *CreateReviewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateReviewResponse.ProtoReflect.Descriptor instead.
+func (*CreateReviewResponse) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *CreateReviewResponse) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+type EditReviewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Text          string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EditReviewRequest) Reset() {
+	*x = EditReviewRequest{}
+	mi := &file_movie_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditReviewRequest) ProtoMessage() {}
+
+func (x *EditReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditReviewRequest.ProtoReflect.Descriptor instead.
+func (*EditReviewRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *EditReviewRequest) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+func (x *EditReviewRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *EditReviewRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type EditReviewResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Review        *Review                `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EditReviewResponse) Reset() {
+	*x = EditReviewResponse{}
+	mi := &file_movie_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditReviewResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditReviewResponse) ProtoMessage() {}
+
+func (x *EditReviewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditReviewResponse.ProtoReflect.Descriptor instead.
+func (*EditReviewResponse) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *EditReviewResponse) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+type DeleteReviewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteReviewRequest) Reset() {
+	*x = DeleteReviewRequest{}
+	mi := &file_movie_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteReviewRequest) ProtoMessage() {}
+
+func (x *DeleteReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteReviewRequest.ProtoReflect.Descriptor instead.
+func (*DeleteReviewRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *DeleteReviewRequest) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+func (x *DeleteReviewRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type DeleteReviewResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteReviewResponse) Reset() {
+	*x = DeleteReviewResponse{}
+	mi := &file_movie_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteReviewResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteReviewResponse) ProtoMessage() {}
+
+func (x *DeleteReviewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteReviewResponse.ProtoReflect.Descriptor instead.
+func (*DeleteReviewResponse) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{30}
+}
+
+type ModerateReviewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModerateReviewRequest) Reset() {
+	*x = ModerateReviewRequest{}
+	mi := &file_movie_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModerateReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerateReviewRequest) ProtoMessage() {}
+
+func (x *ModerateReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerateReviewRequest.ProtoReflect.Descriptor instead.
+func (*ModerateReviewRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ModerateReviewRequest) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+func (x *ModerateReviewRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ModerateReviewResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Review        *Review                `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModerateReviewResponse) Reset() {
+	*x = ModerateReviewResponse{}
+	mi := &file_movie_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModerateReviewResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerateReviewResponse) ProtoMessage() {}
+
+func (x *ModerateReviewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerateReviewResponse.ProtoReflect.Descriptor instead.
+func (*ModerateReviewResponse) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ModerateReviewResponse) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+type ListReviewsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RecordId      string                 `protobuf:"bytes,1,opt,name=record_id,json=recordId,proto3" json:"record_id,omitempty"`
+	RecordType    string                 `protobuf:"bytes,2,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewsRequest) Reset() {
+	*x = ListReviewsRequest{}
+	mi := &file_movie_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewsRequest) ProtoMessage() {}
+
+func (x *ListReviewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewsRequest.ProtoReflect.Descriptor instead.
+func (*ListReviewsRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ListReviewsRequest) GetRecordId() string {
+	if x != nil {
+		return x.RecordId
+	}
+	return ""
+}
+
+func (x *ListReviewsRequest) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *ListReviewsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListReviewsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListReviewsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reviews       []*Review              `protobuf:"bytes,1,rep,name=reviews,proto3" json:"reviews,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewsResponse) Reset() {
+	*x = ListReviewsResponse{}
+	mi := &file_movie_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewsResponse) ProtoMessage() {}
+
+func (x *ListReviewsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewsResponse.ProtoReflect.Descriptor instead.
+func (*ListReviewsResponse) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ListReviewsResponse) GetReviews() []*Review {
+	if x != nil {
+		return x.Reviews
+	}
+	return nil
+}
+
+func (x *ListReviewsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type VoteReviewHelpfulRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VoteReviewHelpfulRequest) Reset() {
+	*x = VoteReviewHelpfulRequest{}
+	mi := &file_movie_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VoteReviewHelpfulRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoteReviewHelpfulRequest) ProtoMessage() {}
+
+func (x *VoteReviewHelpfulRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoteReviewHelpfulRequest.ProtoReflect.Descriptor instead.
+func (*VoteReviewHelpfulRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *VoteReviewHelpfulRequest) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+func (x *VoteReviewHelpfulRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type VoteReviewHelpfulResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HelpfulVotes  int32                  `protobuf:"varint,1,opt,name=helpful_votes,json=helpfulVotes,proto3" json:"helpful_votes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VoteReviewHelpfulResponse) Reset() {
+	*x = VoteReviewHelpfulResponse{}
+	mi := &file_movie_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VoteReviewHelpfulResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoteReviewHelpfulResponse) ProtoMessage() {}
+
+func (x *VoteReviewHelpfulResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoteReviewHelpfulResponse.ProtoReflect.Descriptor instead.
+func (*VoteReviewHelpfulResponse) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *VoteReviewHelpfulResponse) GetHelpfulVotes() int32 {
+	if x != nil {
+		return x.HelpfulVotes
+	}
+	return 0
+}
+
+type ExportRatingsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RecordType    string                 `protobuf:"bytes,1,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	SnapshotTime  *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=snapshot_time,json=snapshotTime,proto3" json:"snapshot_time,omitempty"`
+	Cursor        string                 `protobuf:"bytes,5,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportRatingsRequest) Reset() {
+	*x = ExportRatingsRequest{}
+	mi := &file_movie_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportRatingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportRatingsRequest) ProtoMessage() {}
+
+func (x *ExportRatingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportRatingsRequest.ProtoReflect.Descriptor instead.
+func (*ExportRatingsRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ExportRatingsRequest) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *ExportRatingsRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *ExportRatingsRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *ExportRatingsRequest) GetSnapshotTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SnapshotTime
+	}
+	return nil
+}
+
+func (x *ExportRatingsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type ExportedRating struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RecordId      string                 `protobuf:"bytes,1,opt,name=record_id,json=recordId,proto3" json:"record_id,omitempty"`
+	RecordType    string                 `protobuf:"bytes,2,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	RatingValue   int32                  `protobuf:"varint,4,opt,name=rating_value,json=ratingValue,proto3" json:"rating_value,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ProviderId    string                 `protobuf:"bytes,6,opt,name=provider_id,json=providerId,proto3" json:"provider_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportedRating) Reset() {
+	*x = ExportedRating{}
+	mi := &file_movie_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportedRating) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportedRating) ProtoMessage() {}
+
+func (x *ExportedRating) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportedRating.ProtoReflect.Descriptor instead.
+func (*ExportedRating) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ExportedRating) GetRecordId() string {
+	if x != nil {
+		return x.RecordId
+	}
+	return ""
+}
+
+func (x *ExportedRating) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *ExportedRating) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ExportedRating) GetRatingValue() int32 {
+	if x != nil {
+		return x.RatingValue
+	}
+	return 0
+}
+
+func (x *ExportedRating) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *ExportedRating) GetProviderId() string {
+	if x != nil {
+		return x.ProviderId
+	}
+	return ""
+}
+
+type ExportRatingsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ratings       []*ExportedRating      `protobuf:"bytes,1,rep,name=ratings,proto3" json:"ratings,omitempty"`
+	Cursor        string                 `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	SnapshotTime  *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=snapshot_time,json=snapshotTime,proto3" json:"snapshot_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportRatingsResponse) Reset() {
+	*x = ExportRatingsResponse{}
+	mi := &file_movie_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportRatingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportRatingsResponse) ProtoMessage() {}
+
+func (x *ExportRatingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportRatingsResponse.ProtoReflect.Descriptor instead.
+func (*ExportRatingsResponse) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ExportRatingsResponse) GetRatings() []*ExportedRating {
+	if x != nil {
+		return x.Ratings
+	}
+	return nil
+}
+
+func (x *ExportRatingsResponse) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ExportRatingsResponse) GetSnapshotTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SnapshotTime
+	}
+	return nil
+}
+
+type GetMovieDetailsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MovieId       string                 `protobuf:"bytes,1,opt,name=movie_id,json=movieId,proto3" json:"movie_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMovieDetailsRequest) Reset() {
+	*x = GetMovieDetailsRequest{}
+	mi := &file_movie_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMovieDetailsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMovieDetailsRequest) ProtoMessage() {}
+
+func (x *GetMovieDetailsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMovieDetailsRequest.ProtoReflect.Descriptor instead.
+func (*GetMovieDetailsRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *GetMovieDetailsRequest) GetMovieId() string {
+	if x != nil {
+		return x.MovieId
+	}
+	return ""
+}
+
+type GetMovieDetailsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MovieDetails  *MovieDetails          `protobuf:"bytes,1,opt,name=movie_details,json=movieDetails,proto3" json:"movie_details,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMovieDetailsResponse) Reset() {
+	*x = GetMovieDetailsResponse{}
+	mi := &file_movie_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMovieDetailsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMovieDetailsResponse) ProtoMessage() {}
+
+func (x *GetMovieDetailsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMovieDetailsResponse.ProtoReflect.Descriptor instead.
+func (*GetMovieDetailsResponse) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *GetMovieDetailsResponse) GetMovieDetails() *MovieDetails {
+	if x != nil {
+		return x.MovieDetails
+	}
+	return nil
+}
+
+type RankedMovie struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metadata      *Metadata              `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Rating        float64                `protobuf:"fixed64,2,opt,name=rating,proto3" json:"rating,omitempty"`
+	VoteCount     int32                  `protobuf:"varint,3,opt,name=vote_count,json=voteCount,proto3" json:"vote_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RankedMovie) Reset() {
+	*x = RankedMovie{}
+	mi := &file_movie_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RankedMovie) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RankedMovie) ProtoMessage() {}
+
+func (x *RankedMovie) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RankedMovie.ProtoReflect.Descriptor instead.
+func (*RankedMovie) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *RankedMovie) GetMetadata() *Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *RankedMovie) GetRating() float64 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *RankedMovie) GetVoteCount() int32 {
+	if x != nil {
+		return x.VoteCount
+	}
+	return 0
+}
+
+type GetTopRatedMoviesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	MinVoteCount  int32                  `protobuf:"varint,2,opt,name=min_vote_count,json=minVoteCount,proto3" json:"min_vote_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTopRatedMoviesRequest) Reset() {
+	*x = GetTopRatedMoviesRequest{}
+	mi := &file_movie_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTopRatedMoviesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTopRatedMoviesRequest) ProtoMessage() {}
+
+func (x *GetTopRatedMoviesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTopRatedMoviesRequest.ProtoReflect.Descriptor instead.
+func (*GetTopRatedMoviesRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *GetTopRatedMoviesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetTopRatedMoviesRequest) GetMinVoteCount() int32 {
+	if x != nil {
+		return x.MinVoteCount
+	}
+	return 0
+}
+
+type GetTopRatedMoviesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Movies        []*RankedMovie         `protobuf:"bytes,1,rep,name=movies,proto3" json:"movies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTopRatedMoviesResponse) Reset() {
+	*x = GetTopRatedMoviesResponse{}
+	mi := &file_movie_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTopRatedMoviesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTopRatedMoviesResponse) ProtoMessage() {}
+
+func (x *GetTopRatedMoviesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTopRatedMoviesResponse.ProtoReflect.Descriptor instead.
+func (*GetTopRatedMoviesResponse) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *GetTopRatedMoviesResponse) GetMovies() []*RankedMovie {
+	if x != nil {
+		return x.Movies
+	}
+	return nil
+}
+
+type RecordRating struct {
+	state          protoimpl.MessageState  `protogen:"open.v1"`
+	RecordId       string                  `protobuf:"bytes,1,opt,name=record_id,json=recordId,proto3" json:"record_id,omitempty"`
+	RecordType     string                  `protobuf:"bytes,2,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	Rating         *wrapperspb.DoubleValue `protobuf:"bytes,3,opt,name=rating,proto3" json:"rating,omitempty"`
+	RolledUpRating *wrapperspb.DoubleValue `protobuf:"bytes,4,opt,name=rolled_up_rating,json=rolledUpRating,proto3" json:"rolled_up_rating,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RecordRating) Reset() {
+	*x = RecordRating{}
+	mi := &file_movie_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordRating) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordRating) ProtoMessage() {}
+
+func (x *RecordRating) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordRating.ProtoReflect.Descriptor instead.
+func (*RecordRating) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *RecordRating) GetRecordId() string {
+	if x != nil {
+		return x.RecordId
+	}
+	return ""
+}
+
+func (x *RecordRating) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *RecordRating) GetRating() *wrapperspb.DoubleValue {
+	if x != nil {
+		return x.Rating
+	}
+	return nil
+}
+
+func (x *RecordRating) GetRolledUpRating() *wrapperspb.DoubleValue {
+	if x != nil {
+		return x.RolledUpRating
+	}
+	return nil
+}
+
+type GetRecordRatingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RecordId      string                 `protobuf:"bytes,1,opt,name=record_id,json=recordId,proto3" json:"record_id,omitempty"`
+	RecordType    string                 `protobuf:"bytes,2,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecordRatingRequest) Reset() {
+	*x = GetRecordRatingRequest{}
+	mi := &file_movie_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecordRatingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecordRatingRequest) ProtoMessage() {}
+
+func (x *GetRecordRatingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecordRatingRequest.ProtoReflect.Descriptor instead.
+func (*GetRecordRatingRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *GetRecordRatingRequest) GetRecordId() string {
+	if x != nil {
+		return x.RecordId
+	}
+	return ""
+}
+
+func (x *GetRecordRatingRequest) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+type GetRecordRatingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RecordRating  *RecordRating          `protobuf:"bytes,1,opt,name=record_rating,json=recordRating,proto3" json:"record_rating,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecordRatingResponse) Reset() {
+	*x = GetRecordRatingResponse{}
+	mi := &file_movie_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecordRatingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecordRatingResponse) ProtoMessage() {}
+
+func (x *GetRecordRatingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecordRatingResponse.ProtoReflect.Descriptor instead.
+func (*GetRecordRatingResponse) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *GetRecordRatingResponse) GetRecordRating() *RecordRating {
+	if x != nil {
+		return x.RecordRating
+	}
+	return nil
+}
+
+type ListMoviesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Director      string                 `protobuf:"bytes,1,opt,name=director,proto3" json:"director,omitempty"`
+	Genre         string                 `protobuf:"bytes,2,opt,name=genre,proto3" json:"genre,omitempty"`
+	SortBy        string                 `protobuf:"bytes,3,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMoviesRequest) Reset() {
+	*x = ListMoviesRequest{}
+	mi := &file_movie_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMoviesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMoviesRequest) ProtoMessage() {}
+
+func (x *ListMoviesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMoviesRequest.ProtoReflect.Descriptor instead.
+func (*ListMoviesRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ListMoviesRequest) GetDirector() string {
+	if x != nil {
+		return x.Director
+	}
+	return ""
+}
+
+func (x *ListMoviesRequest) GetGenre() string {
+	if x != nil {
+		return x.Genre
+	}
+	return ""
+}
+
+func (x *ListMoviesRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ListMoviesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListMoviesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListMoviesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Movies        []*RankedMovie         `protobuf:"bytes,1,rep,name=movies,proto3" json:"movies,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMoviesResponse) Reset() {
+	*x = ListMoviesResponse{}
+	mi := &file_movie_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMoviesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMoviesResponse) ProtoMessage() {}
+
+func (x *ListMoviesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMoviesResponse.ProtoReflect.Descriptor instead.
+func (*ListMoviesResponse) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ListMoviesResponse) GetMovies() []*RankedMovie {
+	if x != nil {
+		return x.Movies
+	}
+	return nil
+}
+
+func (x *ListMoviesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// RateMovieRequest rates a movie as the user of the auth token sent in the
+// authorization metadata of the call.
+type RateMovieRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MovieId       string                 `protobuf:"bytes,1,opt,name=movie_id,json=movieId,proto3" json:"movie_id,omitempty"`
+	RatingValue   int32                  `protobuf:"varint,2,opt,name=rating_value,json=ratingValue,proto3" json:"rating_value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateMovieRequest) Reset() {
+	*x = RateMovieRequest{}
+	mi := &file_movie_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateMovieRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateMovieRequest) ProtoMessage() {}
+
+func (x *RateMovieRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateMovieRequest.ProtoReflect.Descriptor instead.
+func (*RateMovieRequest) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *RateMovieRequest) GetMovieId() string {
+	if x != nil {
+		return x.MovieId
+	}
+	return ""
+}
+
+func (x *RateMovieRequest) GetRatingValue() int32 {
+	if x != nil {
+		return x.RatingValue
+	}
+	return 0
+}
+
+type RateMovieResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateMovieResponse) Reset() {
+	*x = RateMovieResponse{}
+	mi := &file_movie_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateMovieResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateMovieResponse) ProtoMessage() {}
+
+func (x *RateMovieResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_movie_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateMovieResponse.ProtoReflect.Descriptor instead.
+func (*RateMovieResponse) Descriptor() ([]byte, []int) {
+	return file_movie_proto_rawDescGZIP(), []int{51}
+}
+
+var File_movie_proto protoreflect.FileDescriptor
+
+const file_movie_proto_rawDesc = "" +
 	"\n" +
-	"\vmovie.proto\"n\n" +
+	"\vmovie.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1egoogle/protobuf/wrappers.proto\"\x86\x01\n" +
 	"\bMetadata\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
 	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1a\n" +
-	"\bdirector\x18\x04 \x01(\tR\bdirector\"M\n" +
+	"\bdirector\x18\x04 \x01(\tR\bdirector\x12\x16\n" +
+	"\x06genres\x18\x05 \x03(\tR\x06genres\"\xbb\x01\n" +
 	"\fMovieDetails\x12\x16\n" +
 	"\x06rating\x18\x01 \x01(\x01R\x06rating\x12%\n" +
-	"\bmetadata\x18\x02 \x01(\v2\t.MetadataR\bmetadata\"/\n" +
+	"\bmetadata\x18\x02 \x01(\v2\t.MetadataR\bmetadata\x127\n" +
+	"\x0fmetadata_status\x18\x03 \x01(\x0e2\x0e.SectionStatusR\x0emetadataStatus\x123\n" +
+	"\rrating_status\x18\x04 \x01(\x0e2\x0e.SectionStatusR\fratingStatus\"/\n" +
 	"\x12GetMetadataRequest\x12\x19\n" +
 	"\bmovie_id\x18\x01 \x01(\tR\amovieId\"<\n" +
 	"\x13GetMetadataResponse\x12%\n" +
 	"\bmetadata\x18\x01 \x01(\v2\t.MetadataR\bmetadata\";\n" +
 	"\x12PutMetadataRequest\x12%\n" +
 	"\bmetadata\x18\x01 \x01(\v2\t.MetadataR\bmetadata\"\x15\n" +
-	"\x13PutMetadataResponse\"Z\n" +
+	"\x13PutMetadataResponse\"\x83\x01\n" +
+	"\x13ListMetadataRequest\x12\x1a\n" +
+	"\bdirector\x18\x01 \x01(\tR\bdirector\x12\x14\n" +
+	"\x05genre\x18\x02 \x01(\tR\x05genre\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\"e\n" +
+	"\x14ListMetadataResponse\x12%\n" +
+	"\bmetadata\x18\x01 \x03(\v2\t.MetadataR\bmetadata\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"6\n" +
+	"\x17GetMetadataBatchRequest\x12\x1b\n" +
+	"\tmovie_ids\x18\x01 \x03(\tR\bmovieIds\"A\n" +
+	"\x18GetMetadataBatchResponse\x12%\n" +
+	"\bmetadata\x18\x01 \x03(\v2\t.MetadataR\bmetadata\"\xa0\x01\n" +
 	"\x1aGetAggregatedRatingRequest\x12\x1b\n" +
 	"\trecord_id\x18\x01 \x01(\tR\brecordId\x12\x1f\n" +
 	"\vrecord_type\x18\x02 \x01(\tR\n" +
-	"recordType\"@\n" +
+	"recordType\x12+\n" +
+	"\x11include_providers\x18\x03 \x01(\bR\x10includeProviders\x12\x17\n" +
+	"\aroll_up\x18\x04 \x01(\bR\x06rollUp\"\x8e\x01\n" +
 	"\x1bGetAggregatedRatingResponse\x12!\n" +
-	"\frating_value\x18\x01 \x01(\x01R\vratingValue\"\x8c\x01\n" +
+	"\frating_value\x18\x01 \x01(\x01R\vratingValue\x12\x1d\n" +
+	"\n" +
+	"vote_count\x18\x02 \x01(\x05R\tvoteCount\x12-\n" +
+	"\tproviders\x18\x03 \x03(\v2\x0f.ProviderRatingR\tproviders\"]\n" +
+	"\x1bGetAggregatedRatingsRequest\x12\x1d\n" +
+	"\n" +
+	"record_ids\x18\x01 \x03(\tR\trecordIds\x12\x1f\n" +
+	"\vrecord_type\x18\x02 \x01(\tR\n" +
+	"recordType\"F\n" +
+	"\x1cGetAggregatedRatingsResponse\x12&\n" +
+	"\arecords\x18\x01 \x03(\v2\f.RatedRecordR\arecords\"\xa5\x01\n" +
+	"\x0eProviderRating\x12\x1f\n" +
+	"\vprovider_id\x18\x01 \x01(\tR\n" +
+	"providerId\x12!\n" +
+	"\frating_value\x18\x02 \x01(\x01R\vratingValue\x12\x1d\n" +
+	"\n" +
+	"vote_count\x18\x03 \x01(\x05R\tvoteCount\x12\x16\n" +
+	"\x06weight\x18\x04 \x01(\x01R\x06weight\x12\x18\n" +
+	"\aenabled\x18\x05 \x01(\bR\aenabled\"\xe8\x01\n" +
 	"\x10PutRatingRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
 	"\trecord_id\x18\x02 \x01(\tR\brecordId\x12\x1f\n" +
 	"\vrecord_type\x18\x03 \x01(\tR\n" +
 	"recordType\x12!\n" +
-	"\frating_value\x18\x04 \x01(\x05R\vratingValue\"\x13\n" +
-	"\x11PutRatingResponse\"3\n" +
+	"\frating_value\x18\x04 \x01(\x05R\vratingValue\x12\x1f\n" +
+	"\vprovider_id\x18\x05 \x01(\tR\n" +
+	"providerId\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x13\n" +
+	"\x11PutRatingResponse\"l\n" +
+	"\x13DeleteRatingRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\trecord_id\x18\x02 \x01(\tR\brecordId\x12\x1f\n" +
+	"\vrecord_type\x18\x03 \x01(\tR\n" +
+	"recordType\"\x16\n" +
+	"\x14DeleteRatingResponse\"q\n" +
+	"\x12GetTopRatedRequest\x12\x1f\n" +
+	"\vrecord_type\x18\x01 \x01(\tR\n" +
+	"recordType\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12$\n" +
+	"\x0emin_vote_count\x18\x03 \x01(\x05R\fminVoteCount\"\x8d\x01\n" +
+	"\vRatedRecord\x12\x1b\n" +
+	"\trecord_id\x18\x01 \x01(\tR\brecordId\x12\x1f\n" +
+	"\vrecord_type\x18\x02 \x01(\tR\n" +
+	"recordType\x12!\n" +
+	"\frating_value\x18\x03 \x01(\x01R\vratingValue\x12\x1d\n" +
+	"\n" +
+	"vote_count\x18\x04 \x01(\x05R\tvoteCount\"=\n" +
+	"\x13GetTopRatedResponse\x12&\n" +
+	"\arecords\x18\x01 \x03(\v2\f.RatedRecordR\arecords\"\xa0\x01\n" +
+	"\x1cWatchAggregatedRatingRequest\x12\x1b\n" +
+	"\trecord_id\x18\x01 \x01(\tR\brecordId\x12\x1f\n" +
+	"\vrecord_type\x18\x02 \x01(\tR\n" +
+	"recordType\x12&\n" +
+	"\x0fmin_interval_ms\x18\x03 \x01(\x03R\rminIntervalMs\x12\x1a\n" +
+	"\bcoalesce\x18\x04 \x01(\bR\bcoalesce\"B\n" +
+	"\x1dWatchAggregatedRatingResponse\x12!\n" +
+	"\frating_value\x18\x01 \x01(\x01R\vratingValue\"\xfe\x01\n" +
+	"\x06Review\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\trecord_id\x18\x02 \x01(\tR\brecordId\x12\x1f\n" +
+	"\vrecord_type\x18\x03 \x01(\tR\n" +
+	"recordType\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04text\x18\x05 \x01(\tR\x04text\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12#\n" +
+	"\rhelpful_votes\x18\a \x01(\x05R\fhelpfulVotes\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\x03R\tupdatedAt\"\x80\x01\n" +
+	"\x13CreateReviewRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\trecord_id\x18\x02 \x01(\tR\brecordId\x12\x1f\n" +
+	"\vrecord_type\x18\x03 \x01(\tR\n" +
+	"recordType\x12\x12\n" +
+	"\x04text\x18\x04 \x01(\tR\x04text\"7\n" +
+	"\x14CreateReviewResponse\x12\x1f\n" +
+	"\x06review\x18\x01 \x01(\v2\a.ReviewR\x06review\"]\n" +
+	"\x11EditReviewRequest\x12\x1b\n" +
+	"\treview_id\x18\x01 \x01(\tR\breviewId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\"5\n" +
+	"\x12EditReviewResponse\x12\x1f\n" +
+	"\x06review\x18\x01 \x01(\v2\a.ReviewR\x06review\"K\n" +
+	"\x13DeleteReviewRequest\x12\x1b\n" +
+	"\treview_id\x18\x01 \x01(\tR\breviewId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\x16\n" +
+	"\x14DeleteReviewResponse\"L\n" +
+	"\x15ModerateReviewRequest\x12\x1b\n" +
+	"\treview_id\x18\x01 \x01(\tR\breviewId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"9\n" +
+	"\x16ModerateReviewResponse\x12\x1f\n" +
+	"\x06review\x18\x01 \x01(\v2\a.ReviewR\x06review\"\x8e\x01\n" +
+	"\x12ListReviewsRequest\x12\x1b\n" +
+	"\trecord_id\x18\x01 \x01(\tR\brecordId\x12\x1f\n" +
+	"\vrecord_type\x18\x02 \x01(\tR\n" +
+	"recordType\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\"`\n" +
+	"\x13ListReviewsResponse\x12!\n" +
+	"\areviews\x18\x01 \x03(\v2\a.ReviewR\areviews\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"P\n" +
+	"\x18VoteReviewHelpfulRequest\x12\x1b\n" +
+	"\treview_id\x18\x01 \x01(\tR\breviewId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"@\n" +
+	"\x19VoteReviewHelpfulResponse\x12#\n" +
+	"\rhelpful_votes\x18\x01 \x01(\x05R\fhelpfulVotes\"\x82\x02\n" +
+	"\x14ExportRatingsRequest\x12\x1f\n" +
+	"\vrecord_type\x18\x01 \x01(\tR\n" +
+	"recordType\x129\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12?\n" +
+	"\rsnapshot_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\fsnapshotTime\x12\x16\n" +
+	"\x06cursor\x18\x05 \x01(\tR\x06cursor\"\xe6\x01\n" +
+	"\x0eExportedRating\x12\x1b\n" +
+	"\trecord_id\x18\x01 \x01(\tR\brecordId\x12\x1f\n" +
+	"\vrecord_type\x18\x02 \x01(\tR\n" +
+	"recordType\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12!\n" +
+	"\frating_value\x18\x04 \x01(\x05R\vratingValue\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1f\n" +
+	"\vprovider_id\x18\x06 \x01(\tR\n" +
+	"providerId\"\x9b\x01\n" +
+	"\x15ExportRatingsResponse\x12)\n" +
+	"\aratings\x18\x01 \x03(\v2\x0f.ExportedRatingR\aratings\x12\x16\n" +
+	"\x06cursor\x18\x02 \x01(\tR\x06cursor\x12?\n" +
+	"\rsnapshot_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\fsnapshotTime\"3\n" +
 	"\x16GetMovieDetailsRequest\x12\x19\n" +
 	"\bmovie_id\x18\x01 \x01(\tR\amovieId\"M\n" +
 	"\x17GetMovieDetailsResponse\x122\n" +
-	"\rmovie_details\x18\x01 \x01(\v2\r.MovieDetailsR\fmovieDetails2\x85\x01\n" +
+	"\rmovie_details\x18\x01 \x01(\v2\r.MovieDetailsR\fmovieDetails\"k\n" +
+	"\vRankedMovie\x12%\n" +
+	"\bmetadata\x18\x01 \x01(\v2\t.MetadataR\bmetadata\x12\x16\n" +
+	"\x06rating\x18\x02 \x01(\x01R\x06rating\x12\x1d\n" +
+	"\n" +
+	"vote_count\x18\x03 \x01(\x05R\tvoteCount\"V\n" +
+	"\x18GetTopRatedMoviesRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12$\n" +
+	"\x0emin_vote_count\x18\x02 \x01(\x05R\fminVoteCount\"A\n" +
+	"\x19GetTopRatedMoviesResponse\x12$\n" +
+	"\x06movies\x18\x01 \x03(\v2\f.RankedMovieR\x06movies\"\xca\x01\n" +
+	"\fRecordRating\x12\x1b\n" +
+	"\trecord_id\x18\x01 \x01(\tR\brecordId\x12\x1f\n" +
+	"\vrecord_type\x18\x02 \x01(\tR\n" +
+	"recordType\x124\n" +
+	"\x06rating\x18\x03 \x01(\v2\x1c.google.protobuf.DoubleValueR\x06rating\x12F\n" +
+	"\x10rolled_up_rating\x18\x04 \x01(\v2\x1c.google.protobuf.DoubleValueR\x0erolledUpRating\"V\n" +
+	"\x16GetRecordRatingRequest\x12\x1b\n" +
+	"\trecord_id\x18\x01 \x01(\tR\brecordId\x12\x1f\n" +
+	"\vrecord_type\x18\x02 \x01(\tR\n" +
+	"recordType\"M\n" +
+	"\x17GetRecordRatingResponse\x122\n" +
+	"\rrecord_rating\x18\x01 \x01(\v2\r.RecordRatingR\frecordRating\"\x9a\x01\n" +
+	"\x11ListMoviesRequest\x12\x1a\n" +
+	"\bdirector\x18\x01 \x01(\tR\bdirector\x12\x14\n" +
+	"\x05genre\x18\x02 \x01(\tR\x05genre\x12\x17\n" +
+	"\asort_by\x18\x03 \x01(\tR\x06sortBy\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\"b\n" +
+	"\x12ListMoviesResponse\x12$\n" +
+	"\x06movies\x18\x01 \x03(\v2\f.RankedMovieR\x06movies\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"P\n" +
+	"\x10RateMovieRequest\x12\x19\n" +
+	"\bmovie_id\x18\x01 \x01(\tR\amovieId\x12!\n" +
+	"\frating_value\x18\x02 \x01(\x05R\vratingValue\"\x13\n" +
+	"\x11RateMovieResponse*\x9e\x01\n" +
+	"\rSectionStatus\x12\x1e\n" +
+	"\x1aSECTION_STATUS_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11SECTION_STATUS_OK\x10\x01\x12\x1c\n" +
+	"\x18SECTION_STATUS_NOT_FOUND\x10\x02\x12\x1e\n" +
+	"\x1aSECTION_STATUS_UNAVAILABLE\x10\x03\x12\x18\n" +
+	"\x14SECTION_STATUS_STALE\x10\x042\x8b\x02\n" +
 	"\x0fMetadataService\x128\n" +
 	"\vGetMetadata\x12\x13.GetMetadataRequest\x1a\x14.GetMetadataResponse\x128\n" +
-	"\vPutMetadata\x12\x13.PutMetadataRequest\x1a\x14.PutMetadataResponse2\x95\x01\n" +
+	"\vPutMetadata\x12\x13.PutMetadataRequest\x1a\x14.PutMetadataResponse\x12;\n" +
+	"\fListMetadata\x12\x14.ListMetadataRequest\x1a\x15.ListMetadataResponse\x12G\n" +
+	"\x10GetMetadataBatch\x12\x18.GetMetadataBatchRequest\x1a\x19.GetMetadataBatchResponse2\xf7\x06\n" +
 	"\rRatingService\x12P\n" +
-	"\x13GetAggregatedRating\x12\x1b.GetAggregatedRatingRequest\x1a\x1c.GetAggregatedRatingResponse\x122\n" +
-	"\tPutRating\x12\x11.PutRatingRequest\x1a\x12.PutRatingResponse2T\n" +
+	"\x13GetAggregatedRating\x12\x1b.GetAggregatedRatingRequest\x1a\x1c.GetAggregatedRatingResponse\x12S\n" +
+	"\x14GetAggregatedRatings\x12\x1c.GetAggregatedRatingsRequest\x1a\x1d.GetAggregatedRatingsResponse\x122\n" +
+	"\tPutRating\x12\x11.PutRatingRequest\x1a\x12.PutRatingResponse\x12;\n" +
+	"\fDeleteRating\x12\x14.DeleteRatingRequest\x1a\x15.DeleteRatingResponse\x128\n" +
+	"\vGetTopRated\x12\x13.GetTopRatedRequest\x1a\x14.GetTopRatedResponse\x12X\n" +
+	"\x15WatchAggregatedRating\x12\x1d.WatchAggregatedRatingRequest\x1a\x1e.WatchAggregatedRatingResponse0\x01\x12;\n" +
+	"\fCreateReview\x12\x14.CreateReviewRequest\x1a\x15.CreateReviewResponse\x125\n" +
+	"\n" +
+	"EditReview\x12\x12.EditReviewRequest\x1a\x13.EditReviewResponse\x12;\n" +
+	"\fDeleteReview\x12\x14.DeleteReviewRequest\x1a\x15.DeleteReviewResponse\x12A\n" +
+	"\x0eModerateReview\x12\x16.ModerateReviewRequest\x1a\x17.ModerateReviewResponse\x128\n" +
+	"\vListReviews\x12\x13.ListReviewsRequest\x1a\x14.ListReviewsResponse\x12J\n" +
+	"\x11VoteReviewHelpful\x12\x19.VoteReviewHelpfulRequest\x1a\x1a.VoteReviewHelpfulResponse\x12@\n" +
+	"\rExportRatings\x12\x15.ExportRatingsRequest\x1a\x16.ExportRatingsResponse0\x012\xd1\x02\n" +
 	"\fMovieService\x12D\n" +
-	"\x0fGetMovieDetails\x12\x17.GetMovieDetailsRequest\x1a\x18.GetMovieDetailsResponseB\x06Z\x04/genb\x06proto3"
+	"\x0fGetMovieDetails\x12\x17.GetMovieDetailsRequest\x1a\x18.GetMovieDetailsResponse\x12J\n" +
+	"\x11GetTopRatedMovies\x12\x19.GetTopRatedMoviesRequest\x1a\x1a.GetTopRatedMoviesResponse\x12D\n" +
+	"\x0fGetRecordRating\x12\x17.GetRecordRatingRequest\x1a\x18.GetRecordRatingResponse\x125\n" +
+	"\n" +
+	"ListMovies\x12\x12.ListMoviesRequest\x1a\x13.ListMoviesResponse\x122\n" +
+	"\tRateMovie\x12\x11.RateMovieRequest\x1a\x12.RateMovieResponseB\x06Z\x04/genb\x06proto3"
 
 var (
 	file_movie_proto_rawDescOnce sync.Once
@@ -655,41 +3231,143 @@ func file_movie_proto_rawDescGZIP() []byte {
 	return file_movie_proto_rawDescData
 }
 
-var file_movie_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_movie_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_movie_proto_msgTypes = make([]protoimpl.MessageInfo, 52)
 var file_movie_proto_goTypes = []any{
-	(*Metadata)(nil),                    // 0: Metadata
-	(*MovieDetails)(nil),                // 1: MovieDetails
-	(*GetMetadataRequest)(nil),          // 2: GetMetadataRequest
-	(*GetMetadataResponse)(nil),         // 3: GetMetadataResponse
-	(*PutMetadataRequest)(nil),          // 4: PutMetadataRequest
-	(*PutMetadataResponse)(nil),         // 5: PutMetadataResponse
-	(*GetAggregatedRatingRequest)(nil),  // 6: GetAggregatedRatingRequest
-	(*GetAggregatedRatingResponse)(nil), // 7: GetAggregatedRatingResponse
-	(*PutRatingRequest)(nil),            // 8: PutRatingRequest
-	(*PutRatingResponse)(nil),           // 9: PutRatingResponse
-	(*GetMovieDetailsRequest)(nil),      // 10: GetMovieDetailsRequest
-	(*GetMovieDetailsResponse)(nil),     // 11: GetMovieDetailsResponse
+	(SectionStatus)(0),                    // 0: SectionStatus
+	(*Metadata)(nil),                      // 1: Metadata
+	(*MovieDetails)(nil),                  // 2: MovieDetails
+	(*GetMetadataRequest)(nil),            // 3: GetMetadataRequest
+	(*GetMetadataResponse)(nil),           // 4: GetMetadataResponse
+	(*PutMetadataRequest)(nil),            // 5: PutMetadataRequest
+	(*PutMetadataResponse)(nil),           // 6: PutMetadataResponse
+	(*ListMetadataRequest)(nil),           // 7: ListMetadataRequest
+	(*ListMetadataResponse)(nil),          // 8: ListMetadataResponse
+	(*GetMetadataBatchRequest)(nil),       // 9: GetMetadataBatchRequest
+	(*GetMetadataBatchResponse)(nil),      // 10: GetMetadataBatchResponse
+	(*GetAggregatedRatingRequest)(nil),    // 11: GetAggregatedRatingRequest
+	(*GetAggregatedRatingResponse)(nil),   // 12: GetAggregatedRatingResponse
+	(*GetAggregatedRatingsRequest)(nil),   // 13: GetAggregatedRatingsRequest
+	(*GetAggregatedRatingsResponse)(nil),  // 14: GetAggregatedRatingsResponse
+	(*ProviderRating)(nil),                // 15: ProviderRating
+	(*PutRatingRequest)(nil),              // 16: PutRatingRequest
+	(*PutRatingResponse)(nil),             // 17: PutRatingResponse
+	(*DeleteRatingRequest)(nil),           // 18: DeleteRatingRequest
+	(*DeleteRatingResponse)(nil),          // 19: DeleteRatingResponse
+	(*GetTopRatedRequest)(nil),            // 20: GetTopRatedRequest
+	(*RatedRecord)(nil),                   // 21: RatedRecord
+	(*GetTopRatedResponse)(nil),           // 22: GetTopRatedResponse
+	(*WatchAggregatedRatingRequest)(nil),  // 23: WatchAggregatedRatingRequest
+	(*WatchAggregatedRatingResponse)(nil), // 24: WatchAggregatedRatingResponse
+	(*Review)(nil),                        // 25: Review
+	(*CreateReviewRequest)(nil),           // 26: CreateReviewRequest
+	(*CreateReviewResponse)(nil),          // 27: CreateReviewResponse
+	(*EditReviewRequest)(nil),             // 28: EditReviewRequest
+	(*EditReviewResponse)(nil),            // 29: EditReviewResponse
+	(*DeleteReviewRequest)(nil),           // 30: DeleteReviewRequest
+	(*DeleteReviewResponse)(nil),          // 31: DeleteReviewResponse
+	(*ModerateReviewRequest)(nil),         // 32: ModerateReviewRequest
+	(*ModerateReviewResponse)(nil),        // 33: ModerateReviewResponse
+	(*ListReviewsRequest)(nil),            // 34: ListReviewsRequest
+	(*ListReviewsResponse)(nil),           // 35: ListReviewsResponse
+	(*VoteReviewHelpfulRequest)(nil),      // 36: VoteReviewHelpfulRequest
+	(*VoteReviewHelpfulResponse)(nil),     // 37: VoteReviewHelpfulResponse
+	(*ExportRatingsRequest)(nil),          // 38: ExportRatingsRequest
+	(*ExportedRating)(nil),                // 39: ExportedRating
+	(*ExportRatingsResponse)(nil),         // 40: ExportRatingsResponse
+	(*GetMovieDetailsRequest)(nil),        // 41: GetMovieDetailsRequest
+	(*GetMovieDetailsResponse)(nil),       // 42: GetMovieDetailsResponse
+	(*RankedMovie)(nil),                   // 43: RankedMovie
+	(*GetTopRatedMoviesRequest)(nil),      // 44: GetTopRatedMoviesRequest
+	(*GetTopRatedMoviesResponse)(nil),     // 45: GetTopRatedMoviesResponse
+	(*RecordRating)(nil),                  // 46: RecordRating
+	(*GetRecordRatingRequest)(nil),        // 47: GetRecordRatingRequest
+	(*GetRecordRatingResponse)(nil),       // 48: GetRecordRatingResponse
+	(*ListMoviesRequest)(nil),             // 49: ListMoviesRequest
+	(*ListMoviesResponse)(nil),            // 50: ListMoviesResponse
+	(*RateMovieRequest)(nil),              // 51: RateMovieRequest
+	(*RateMovieResponse)(nil),             // 52: RateMovieResponse
+	(*timestamppb.Timestamp)(nil),         // 53: google.protobuf.Timestamp
+	(*wrapperspb.DoubleValue)(nil),        // 54: google.protobuf.DoubleValue
 }
 var file_movie_proto_depIdxs = []int32{
-	0,  // 0: MovieDetails.metadata:type_name -> Metadata
-	0,  // 1: GetMetadataResponse.metadata:type_name -> Metadata
-	0,  // 2: PutMetadataRequest.metadata:type_name -> Metadata
-	1,  // 3: GetMovieDetailsResponse.movie_details:type_name -> MovieDetails
-	2,  // 4: MetadataService.GetMetadata:input_type -> GetMetadataRequest
-	4,  // 5: MetadataService.PutMetadata:input_type -> PutMetadataRequest
-	6,  // 6: RatingService.GetAggregatedRating:input_type -> GetAggregatedRatingRequest
-	8,  // 7: RatingService.PutRating:input_type -> PutRatingRequest
-	10, // 8: MovieService.GetMovieDetails:input_type -> GetMovieDetailsRequest
-	3,  // 9: MetadataService.GetMetadata:output_type -> GetMetadataResponse
-	5,  // 10: MetadataService.PutMetadata:output_type -> PutMetadataResponse
-	7,  // 11: RatingService.GetAggregatedRating:output_type -> GetAggregatedRatingResponse
-	9,  // 12: RatingService.PutRating:output_type -> PutRatingResponse
-	11, // 13: MovieService.GetMovieDetails:output_type -> GetMovieDetailsResponse
-	9,  // [9:14] is the sub-list for method output_type
-	4,  // [4:9] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	1,  // 0: MovieDetails.metadata:type_name -> Metadata
+	0,  // 1: MovieDetails.metadata_status:type_name -> SectionStatus
+	0,  // 2: MovieDetails.rating_status:type_name -> SectionStatus
+	1,  // 3: GetMetadataResponse.metadata:type_name -> Metadata
+	1,  // 4: PutMetadataRequest.metadata:type_name -> Metadata
+	1,  // 5: ListMetadataResponse.metadata:type_name -> Metadata
+	1,  // 6: GetMetadataBatchResponse.metadata:type_name -> Metadata
+	15, // 7: GetAggregatedRatingResponse.providers:type_name -> ProviderRating
+	21, // 8: GetAggregatedRatingsResponse.records:type_name -> RatedRecord
+	53, // 9: PutRatingRequest.updated_at:type_name -> google.protobuf.Timestamp
+	21, // 10: GetTopRatedResponse.records:type_name -> RatedRecord
+	25, // 11: CreateReviewResponse.review:type_name -> Review
+	25, // 12: EditReviewResponse.review:type_name -> Review
+	25, // 13: ModerateReviewResponse.review:type_name -> Review
+	25, // 14: ListReviewsResponse.reviews:type_name -> Review
+	53, // 15: ExportRatingsRequest.start_time:type_name -> google.protobuf.Timestamp
+	53, // 16: ExportRatingsRequest.end_time:type_name -> google.protobuf.Timestamp
+	53, // 17: ExportRatingsRequest.snapshot_time:type_name -> google.protobuf.Timestamp
+	53, // 18: ExportedRating.updated_at:type_name -> google.protobuf.Timestamp
+	39, // 19: ExportRatingsResponse.ratings:type_name -> ExportedRating
+	53, // 20: ExportRatingsResponse.snapshot_time:type_name -> google.protobuf.Timestamp
+	2,  // 21: GetMovieDetailsResponse.movie_details:type_name -> MovieDetails
+	1,  // 22: RankedMovie.metadata:type_name -> Metadata
+	43, // 23: GetTopRatedMoviesResponse.movies:type_name -> RankedMovie
+	54, // 24: RecordRating.rating:type_name -> google.protobuf.DoubleValue
+	54, // 25: RecordRating.rolled_up_rating:type_name -> google.protobuf.DoubleValue
+	46, // 26: GetRecordRatingResponse.record_rating:type_name -> RecordRating
+	43, // 27: ListMoviesResponse.movies:type_name -> RankedMovie
+	3,  // 28: MetadataService.GetMetadata:input_type -> GetMetadataRequest
+	5,  // 29: MetadataService.PutMetadata:input_type -> PutMetadataRequest
+	7,  // 30: MetadataService.ListMetadata:input_type -> ListMetadataRequest
+	9,  // 31: MetadataService.GetMetadataBatch:input_type -> GetMetadataBatchRequest
+	11, // 32: RatingService.GetAggregatedRating:input_type -> GetAggregatedRatingRequest
+	13, // 33: RatingService.GetAggregatedRatings:input_type -> GetAggregatedRatingsRequest
+	16, // 34: RatingService.PutRating:input_type -> PutRatingRequest
+	18, // 35: RatingService.DeleteRating:input_type -> DeleteRatingRequest
+	20, // 36: RatingService.GetTopRated:input_type -> GetTopRatedRequest
+	23, // 37: RatingService.WatchAggregatedRating:input_type -> WatchAggregatedRatingRequest
+	26, // 38: RatingService.CreateReview:input_type -> CreateReviewRequest
+	28, // 39: RatingService.EditReview:input_type -> EditReviewRequest
+	30, // 40: RatingService.DeleteReview:input_type -> DeleteReviewRequest
+	32, // 41: RatingService.ModerateReview:input_type -> ModerateReviewRequest
+	34, // 42: RatingService.ListReviews:input_type -> ListReviewsRequest
+	36, // 43: RatingService.VoteReviewHelpful:input_type -> VoteReviewHelpfulRequest
+	38, // 44: RatingService.ExportRatings:input_type -> ExportRatingsRequest
+	41, // 45: MovieService.GetMovieDetails:input_type -> GetMovieDetailsRequest
+	44, // 46: MovieService.GetTopRatedMovies:input_type -> GetTopRatedMoviesRequest
+	47, // 47: MovieService.GetRecordRating:input_type -> GetRecordRatingRequest
+	49, // 48: MovieService.ListMovies:input_type -> ListMoviesRequest
+	51, // 49: MovieService.RateMovie:input_type -> RateMovieRequest
+	4,  // 50: MetadataService.GetMetadata:output_type -> GetMetadataResponse
+	6,  // 51: MetadataService.PutMetadata:output_type -> PutMetadataResponse
+	8,  // 52: MetadataService.ListMetadata:output_type -> ListMetadataResponse
+	10, // 53: MetadataService.GetMetadataBatch:output_type -> GetMetadataBatchResponse
+	12, // 54: RatingService.GetAggregatedRating:output_type -> GetAggregatedRatingResponse
+	14, // 55: RatingService.GetAggregatedRatings:output_type -> GetAggregatedRatingsResponse
+	17, // 56: RatingService.PutRating:output_type -> PutRatingResponse
+	19, // 57: RatingService.DeleteRating:output_type -> DeleteRatingResponse
+	22, // 58: RatingService.GetTopRated:output_type -> GetTopRatedResponse
+	24, // 59: RatingService.WatchAggregatedRating:output_type -> WatchAggregatedRatingResponse
+	27, // 60: RatingService.CreateReview:output_type -> CreateReviewResponse
+	29, // 61: RatingService.EditReview:output_type -> EditReviewResponse
+	31, // 62: RatingService.DeleteReview:output_type -> DeleteReviewResponse
+	33, // 63: RatingService.ModerateReview:output_type -> ModerateReviewResponse
+	35, // 64: RatingService.ListReviews:output_type -> ListReviewsResponse
+	37, // 65: RatingService.VoteReviewHelpful:output_type -> VoteReviewHelpfulResponse
+	40, // 66: RatingService.ExportRatings:output_type -> ExportRatingsResponse
+	42, // 67: MovieService.GetMovieDetails:output_type -> GetMovieDetailsResponse
+	45, // 68: MovieService.GetTopRatedMovies:output_type -> GetTopRatedMoviesResponse
+	48, // 69: MovieService.GetRecordRating:output_type -> GetRecordRatingResponse
+	50, // 70: MovieService.ListMovies:output_type -> ListMoviesResponse
+	52, // 71: MovieService.RateMovie:output_type -> RateMovieResponse
+	50, // [50:72] is the sub-list for method output_type
+	28, // [28:50] is the sub-list for method input_type
+	28, // [28:28] is the sub-list for extension type_name
+	28, // [28:28] is the sub-list for extension extendee
+	0,  // [0:28] is the sub-list for field type_name
 }
 
 func init() { file_movie_proto_init() }
@@ -702,13 +3380,14 @@ func file_movie_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_movie_proto_rawDesc), len(file_movie_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   12,
+			NumEnums:      1,
+			NumMessages:   52,
 			NumExtensions: 0,
 			NumServices:   3,
 		},
 		GoTypes:           file_movie_proto_goTypes,
 		DependencyIndexes: file_movie_proto_depIdxs,
+		EnumInfos:         file_movie_proto_enumTypes,
 		MessageInfos:      file_movie_proto_msgTypes,
 	}.Build()
 	File_movie_proto = out.File
@@ -19,8 +19,10 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	MetadataService_GetMetadata_FullMethodName = "/MetadataService/GetMetadata"
-	MetadataService_PutMetadata_FullMethodName = "/MetadataService/PutMetadata"
+	MetadataService_GetMetadata_FullMethodName      = "/MetadataService/GetMetadata"
+	MetadataService_PutMetadata_FullMethodName      = "/MetadataService/PutMetadata"
+	MetadataService_ListMetadata_FullMethodName     = "/MetadataService/ListMetadata"
+	MetadataService_GetMetadataBatch_FullMethodName = "/MetadataService/GetMetadataBatch"
 )
 
 // MetadataServiceClient is the client API for MetadataService service.
@@ -29,6 +31,8 @@ const (
 type MetadataServiceClient interface {
 	GetMetadata(ctx context.Context, in *GetMetadataRequest, opts ...grpc.CallOption) (*GetMetadataResponse, error)
 	PutMetadata(ctx context.Context, in *PutMetadataRequest, opts ...grpc.CallOption) (*PutMetadataResponse, error)
+	ListMetadata(ctx context.Context, in *ListMetadataRequest, opts ...grpc.CallOption) (*ListMetadataResponse, error)
+	GetMetadataBatch(ctx context.Context, in *GetMetadataBatchRequest, opts ...grpc.CallOption) (*GetMetadataBatchResponse, error)
 }
 
 type metadataServiceClient struct {
@@ -59,12 +63,34 @@ func (c *metadataServiceClient) PutMetadata(ctx context.Context, in *PutMetadata
 	return out, nil
 }
 
+func (c *metadataServiceClient) ListMetadata(ctx context.Context, in *ListMetadataRequest, opts ...grpc.CallOption) (*ListMetadataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMetadataResponse)
+	err := c.cc.Invoke(ctx, MetadataService_ListMetadata_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metadataServiceClient) GetMetadataBatch(ctx context.Context, in *GetMetadataBatchRequest, opts ...grpc.CallOption) (*GetMetadataBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMetadataBatchResponse)
+	err := c.cc.Invoke(ctx, MetadataService_GetMetadataBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MetadataServiceServer is the server API for MetadataService service.
 // All implementations must embed UnimplementedMetadataServiceServer
 // for forward compatibility.
 type MetadataServiceServer interface {
 	GetMetadata(context.Context, *GetMetadataRequest) (*GetMetadataResponse, error)
 	PutMetadata(context.Context, *PutMetadataRequest) (*PutMetadataResponse, error)
+	ListMetadata(context.Context, *ListMetadataRequest) (*ListMetadataResponse, error)
+	GetMetadataBatch(context.Context, *GetMetadataBatchRequest) (*GetMetadataBatchResponse, error)
 	mustEmbedUnimplementedMetadataServiceServer()
 }
 
@@ -81,6 +107,12 @@ func (UnimplementedMetadataServiceServer) GetMetadata(context.Context, *GetMetad
 func (UnimplementedMetadataServiceServer) PutMetadata(context.Context, *PutMetadataRequest) (*PutMetadataResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PutMetadata not implemented")
 }
+func (UnimplementedMetadataServiceServer) ListMetadata(context.Context, *ListMetadataRequest) (*ListMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMetadata not implemented")
+}
+func (UnimplementedMetadataServiceServer) GetMetadataBatch(context.Context, *GetMetadataBatchRequest) (*GetMetadataBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMetadataBatch not implemented")
+}
 func (UnimplementedMetadataServiceServer) mustEmbedUnimplementedMetadataServiceServer() {}
 func (UnimplementedMetadataServiceServer) testEmbeddedByValue()                         {}
 
@@ -138,6 +170,42 @@ func _MetadataService_PutMetadata_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MetadataService_ListMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataServiceServer).ListMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetadataService_ListMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataServiceServer).ListMetadata(ctx, req.(*ListMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetadataService_GetMetadataBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMetadataBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataServiceServer).GetMetadataBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetadataService_GetMetadataBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataServiceServer).GetMetadataBatch(ctx, req.(*GetMetadataBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MetadataService_ServiceDesc is the grpc.ServiceDesc for MetadataService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,14 +221,33 @@ var MetadataService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "PutMetadata",
 			Handler:    _MetadataService_PutMetadata_Handler,
 		},
+		{
+			MethodName: "ListMetadata",
+			Handler:    _MetadataService_ListMetadata_Handler,
+		},
+		{
+			MethodName: "GetMetadataBatch",
+			Handler:    _MetadataService_GetMetadataBatch_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "movie.proto",
 }
 
 const (
-	RatingService_GetAggregatedRating_FullMethodName = "/RatingService/GetAggregatedRating"
-	RatingService_PutRating_FullMethodName           = "/RatingService/PutRating"
+	RatingService_GetAggregatedRating_FullMethodName   = "/RatingService/GetAggregatedRating"
+	RatingService_GetAggregatedRatings_FullMethodName  = "/RatingService/GetAggregatedRatings"
+	RatingService_PutRating_FullMethodName             = "/RatingService/PutRating"
+	RatingService_DeleteRating_FullMethodName          = "/RatingService/DeleteRating"
+	RatingService_GetTopRated_FullMethodName           = "/RatingService/GetTopRated"
+	RatingService_WatchAggregatedRating_FullMethodName = "/RatingService/WatchAggregatedRating"
+	RatingService_CreateReview_FullMethodName          = "/RatingService/CreateReview"
+	RatingService_EditReview_FullMethodName            = "/RatingService/EditReview"
+	RatingService_DeleteReview_FullMethodName          = "/RatingService/DeleteReview"
+	RatingService_ModerateReview_FullMethodName        = "/RatingService/ModerateReview"
+	RatingService_ListReviews_FullMethodName           = "/RatingService/ListReviews"
+	RatingService_VoteReviewHelpful_FullMethodName     = "/RatingService/VoteReviewHelpful"
+	RatingService_ExportRatings_FullMethodName         = "/RatingService/ExportRatings"
 )
 
 // RatingServiceClient is the client API for RatingService service.
@@ -168,7 +255,21 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type RatingServiceClient interface {
 	GetAggregatedRating(ctx context.Context, in *GetAggregatedRatingRequest, opts ...grpc.CallOption) (*GetAggregatedRatingResponse, error)
+	GetAggregatedRatings(ctx context.Context, in *GetAggregatedRatingsRequest, opts ...grpc.CallOption) (*GetAggregatedRatingsResponse, error)
 	PutRating(ctx context.Context, in *PutRatingRequest, opts ...grpc.CallOption) (*PutRatingResponse, error)
+	DeleteRating(ctx context.Context, in *DeleteRatingRequest, opts ...grpc.CallOption) (*DeleteRatingResponse, error)
+	GetTopRated(ctx context.Context, in *GetTopRatedRequest, opts ...grpc.CallOption) (*GetTopRatedResponse, error)
+	WatchAggregatedRating(ctx context.Context, in *WatchAggregatedRatingRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchAggregatedRatingResponse], error)
+	// Review writes are made as the user of the auth token sent in the
+	// authorization metadata of the call. The user id of a request, if set,
+	// must be that user. Only moderators can moderate reviews.
+	CreateReview(ctx context.Context, in *CreateReviewRequest, opts ...grpc.CallOption) (*CreateReviewResponse, error)
+	EditReview(ctx context.Context, in *EditReviewRequest, opts ...grpc.CallOption) (*EditReviewResponse, error)
+	DeleteReview(ctx context.Context, in *DeleteReviewRequest, opts ...grpc.CallOption) (*DeleteReviewResponse, error)
+	ModerateReview(ctx context.Context, in *ModerateReviewRequest, opts ...grpc.CallOption) (*ModerateReviewResponse, error)
+	ListReviews(ctx context.Context, in *ListReviewsRequest, opts ...grpc.CallOption) (*ListReviewsResponse, error)
+	VoteReviewHelpful(ctx context.Context, in *VoteReviewHelpfulRequest, opts ...grpc.CallOption) (*VoteReviewHelpfulResponse, error)
+	ExportRatings(ctx context.Context, in *ExportRatingsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportRatingsResponse], error)
 }
 
 type ratingServiceClient struct {
@@ -189,6 +290,16 @@ func (c *ratingServiceClient) GetAggregatedRating(ctx context.Context, in *GetAg
 	return out, nil
 }
 
+func (c *ratingServiceClient) GetAggregatedRatings(ctx context.Context, in *GetAggregatedRatingsRequest, opts ...grpc.CallOption) (*GetAggregatedRatingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAggregatedRatingsResponse)
+	err := c.cc.Invoke(ctx, RatingService_GetAggregatedRatings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *ratingServiceClient) PutRating(ctx context.Context, in *PutRatingRequest, opts ...grpc.CallOption) (*PutRatingResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(PutRatingResponse)
@@ -199,12 +310,144 @@ func (c *ratingServiceClient) PutRating(ctx context.Context, in *PutRatingReques
 	return out, nil
 }
 
+func (c *ratingServiceClient) DeleteRating(ctx context.Context, in *DeleteRatingRequest, opts ...grpc.CallOption) (*DeleteRatingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteRatingResponse)
+	err := c.cc.Invoke(ctx, RatingService_DeleteRating_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ratingServiceClient) GetTopRated(ctx context.Context, in *GetTopRatedRequest, opts ...grpc.CallOption) (*GetTopRatedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTopRatedResponse)
+	err := c.cc.Invoke(ctx, RatingService_GetTopRated_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ratingServiceClient) WatchAggregatedRating(ctx context.Context, in *WatchAggregatedRatingRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchAggregatedRatingResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RatingService_ServiceDesc.Streams[0], RatingService_WatchAggregatedRating_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchAggregatedRatingRequest, WatchAggregatedRatingResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RatingService_WatchAggregatedRatingClient = grpc.ServerStreamingClient[WatchAggregatedRatingResponse]
+
+func (c *ratingServiceClient) CreateReview(ctx context.Context, in *CreateReviewRequest, opts ...grpc.CallOption) (*CreateReviewResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateReviewResponse)
+	err := c.cc.Invoke(ctx, RatingService_CreateReview_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ratingServiceClient) EditReview(ctx context.Context, in *EditReviewRequest, opts ...grpc.CallOption) (*EditReviewResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EditReviewResponse)
+	err := c.cc.Invoke(ctx, RatingService_EditReview_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ratingServiceClient) DeleteReview(ctx context.Context, in *DeleteReviewRequest, opts ...grpc.CallOption) (*DeleteReviewResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteReviewResponse)
+	err := c.cc.Invoke(ctx, RatingService_DeleteReview_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ratingServiceClient) ModerateReview(ctx context.Context, in *ModerateReviewRequest, opts ...grpc.CallOption) (*ModerateReviewResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ModerateReviewResponse)
+	err := c.cc.Invoke(ctx, RatingService_ModerateReview_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ratingServiceClient) ListReviews(ctx context.Context, in *ListReviewsRequest, opts ...grpc.CallOption) (*ListReviewsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListReviewsResponse)
+	err := c.cc.Invoke(ctx, RatingService_ListReviews_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ratingServiceClient) VoteReviewHelpful(ctx context.Context, in *VoteReviewHelpfulRequest, opts ...grpc.CallOption) (*VoteReviewHelpfulResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VoteReviewHelpfulResponse)
+	err := c.cc.Invoke(ctx, RatingService_VoteReviewHelpful_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ratingServiceClient) ExportRatings(ctx context.Context, in *ExportRatingsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportRatingsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RatingService_ServiceDesc.Streams[1], RatingService_ExportRatings_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportRatingsRequest, ExportRatingsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RatingService_ExportRatingsClient = grpc.ServerStreamingClient[ExportRatingsResponse]
+
 // RatingServiceServer is the server API for RatingService service.
 // All implementations must embed UnimplementedRatingServiceServer
 // for forward compatibility.
 type RatingServiceServer interface {
 	GetAggregatedRating(context.Context, *GetAggregatedRatingRequest) (*GetAggregatedRatingResponse, error)
+	GetAggregatedRatings(context.Context, *GetAggregatedRatingsRequest) (*GetAggregatedRatingsResponse, error)
 	PutRating(context.Context, *PutRatingRequest) (*PutRatingResponse, error)
+	DeleteRating(context.Context, *DeleteRatingRequest) (*DeleteRatingResponse, error)
+	GetTopRated(context.Context, *GetTopRatedRequest) (*GetTopRatedResponse, error)
+	WatchAggregatedRating(*WatchAggregatedRatingRequest, grpc.ServerStreamingServer[WatchAggregatedRatingResponse]) error
+	// Review writes are made as the user of the auth token sent in the
+	// authorization metadata of the call. The user id of a request, if set,
+	// must be that user. Only moderators can moderate reviews.
+	CreateReview(context.Context, *CreateReviewRequest) (*CreateReviewResponse, error)
+	EditReview(context.Context, *EditReviewRequest) (*EditReviewResponse, error)
+	DeleteReview(context.Context, *DeleteReviewRequest) (*DeleteReviewResponse, error)
+	ModerateReview(context.Context, *ModerateReviewRequest) (*ModerateReviewResponse, error)
+	ListReviews(context.Context, *ListReviewsRequest) (*ListReviewsResponse, error)
+	VoteReviewHelpful(context.Context, *VoteReviewHelpfulRequest) (*VoteReviewHelpfulResponse, error)
+	ExportRatings(*ExportRatingsRequest, grpc.ServerStreamingServer[ExportRatingsResponse]) error
 	mustEmbedUnimplementedRatingServiceServer()
 }
 
@@ -218,9 +461,42 @@ type UnimplementedRatingServiceServer struct{}
 func (UnimplementedRatingServiceServer) GetAggregatedRating(context.Context, *GetAggregatedRatingRequest) (*GetAggregatedRatingResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAggregatedRating not implemented")
 }
+func (UnimplementedRatingServiceServer) GetAggregatedRatings(context.Context, *GetAggregatedRatingsRequest) (*GetAggregatedRatingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAggregatedRatings not implemented")
+}
 func (UnimplementedRatingServiceServer) PutRating(context.Context, *PutRatingRequest) (*PutRatingResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PutRating not implemented")
 }
+func (UnimplementedRatingServiceServer) DeleteRating(context.Context, *DeleteRatingRequest) (*DeleteRatingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRating not implemented")
+}
+func (UnimplementedRatingServiceServer) GetTopRated(context.Context, *GetTopRatedRequest) (*GetTopRatedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTopRated not implemented")
+}
+func (UnimplementedRatingServiceServer) WatchAggregatedRating(*WatchAggregatedRatingRequest, grpc.ServerStreamingServer[WatchAggregatedRatingResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchAggregatedRating not implemented")
+}
+func (UnimplementedRatingServiceServer) CreateReview(context.Context, *CreateReviewRequest) (*CreateReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateReview not implemented")
+}
+func (UnimplementedRatingServiceServer) EditReview(context.Context, *EditReviewRequest) (*EditReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EditReview not implemented")
+}
+func (UnimplementedRatingServiceServer) DeleteReview(context.Context, *DeleteReviewRequest) (*DeleteReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteReview not implemented")
+}
+func (UnimplementedRatingServiceServer) ModerateReview(context.Context, *ModerateReviewRequest) (*ModerateReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModerateReview not implemented")
+}
+func (UnimplementedRatingServiceServer) ListReviews(context.Context, *ListReviewsRequest) (*ListReviewsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReviews not implemented")
+}
+func (UnimplementedRatingServiceServer) VoteReviewHelpful(context.Context, *VoteReviewHelpfulRequest) (*VoteReviewHelpfulResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VoteReviewHelpful not implemented")
+}
+func (UnimplementedRatingServiceServer) ExportRatings(*ExportRatingsRequest, grpc.ServerStreamingServer[ExportRatingsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportRatings not implemented")
+}
 func (UnimplementedRatingServiceServer) mustEmbedUnimplementedRatingServiceServer() {}
 func (UnimplementedRatingServiceServer) testEmbeddedByValue()                       {}
 
@@ -260,6 +536,24 @@ func _RatingService_GetAggregatedRating_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RatingService_GetAggregatedRatings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAggregatedRatingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingServiceServer).GetAggregatedRatings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RatingService_GetAggregatedRatings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingServiceServer).GetAggregatedRatings(ctx, req.(*GetAggregatedRatingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _RatingService_PutRating_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PutRatingRequest)
 	if err := dec(in); err != nil {
@@ -278,6 +572,172 @@ func _RatingService_PutRating_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RatingService_DeleteRating_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRatingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingServiceServer).DeleteRating(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RatingService_DeleteRating_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingServiceServer).DeleteRating(ctx, req.(*DeleteRatingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RatingService_GetTopRated_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTopRatedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingServiceServer).GetTopRated(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RatingService_GetTopRated_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingServiceServer).GetTopRated(ctx, req.(*GetTopRatedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RatingService_WatchAggregatedRating_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchAggregatedRatingRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RatingServiceServer).WatchAggregatedRating(m, &grpc.GenericServerStream[WatchAggregatedRatingRequest, WatchAggregatedRatingResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RatingService_WatchAggregatedRatingServer = grpc.ServerStreamingServer[WatchAggregatedRatingResponse]
+
+func _RatingService_CreateReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingServiceServer).CreateReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RatingService_CreateReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingServiceServer).CreateReview(ctx, req.(*CreateReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RatingService_EditReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingServiceServer).EditReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RatingService_EditReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingServiceServer).EditReview(ctx, req.(*EditReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RatingService_DeleteReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingServiceServer).DeleteReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RatingService_DeleteReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingServiceServer).DeleteReview(ctx, req.(*DeleteReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RatingService_ModerateReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModerateReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingServiceServer).ModerateReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RatingService_ModerateReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingServiceServer).ModerateReview(ctx, req.(*ModerateReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RatingService_ListReviews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReviewsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingServiceServer).ListReviews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RatingService_ListReviews_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingServiceServer).ListReviews(ctx, req.(*ListReviewsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RatingService_VoteReviewHelpful_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VoteReviewHelpfulRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RatingServiceServer).VoteReviewHelpful(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RatingService_VoteReviewHelpful_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RatingServiceServer).VoteReviewHelpful(ctx, req.(*VoteReviewHelpfulRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RatingService_ExportRatings_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportRatingsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RatingServiceServer).ExportRatings(m, &grpc.GenericServerStream[ExportRatingsRequest, ExportRatingsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RatingService_ExportRatingsServer = grpc.ServerStreamingServer[ExportRatingsResponse]
+
 // RatingService_ServiceDesc is the grpc.ServiceDesc for RatingService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -289,17 +749,68 @@ var RatingService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAggregatedRating",
 			Handler:    _RatingService_GetAggregatedRating_Handler,
 		},
+		{
+			MethodName: "GetAggregatedRatings",
+			Handler:    _RatingService_GetAggregatedRatings_Handler,
+		},
 		{
 			MethodName: "PutRating",
 			Handler:    _RatingService_PutRating_Handler,
 		},
+		{
+			MethodName: "DeleteRating",
+			Handler:    _RatingService_DeleteRating_Handler,
+		},
+		{
+			MethodName: "GetTopRated",
+			Handler:    _RatingService_GetTopRated_Handler,
+		},
+		{
+			MethodName: "CreateReview",
+			Handler:    _RatingService_CreateReview_Handler,
+		},
+		{
+			MethodName: "EditReview",
+			Handler:    _RatingService_EditReview_Handler,
+		},
+		{
+			MethodName: "DeleteReview",
+			Handler:    _RatingService_DeleteReview_Handler,
+		},
+		{
+			MethodName: "ModerateReview",
+			Handler:    _RatingService_ModerateReview_Handler,
+		},
+		{
+			MethodName: "ListReviews",
+			Handler:    _RatingService_ListReviews_Handler,
+		},
+		{
+			MethodName: "VoteReviewHelpful",
+			Handler:    _RatingService_VoteReviewHelpful_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchAggregatedRating",
+			Handler:       _RatingService_WatchAggregatedRating_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportRatings",
+			Handler:       _RatingService_ExportRatings_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "movie.proto",
 }
 
 const (
-	MovieService_GetMovieDetails_FullMethodName = "/MovieService/GetMovieDetails"
+	MovieService_GetMovieDetails_FullMethodName   = "/MovieService/GetMovieDetails"
+	MovieService_GetTopRatedMovies_FullMethodName = "/MovieService/GetTopRatedMovies"
+	MovieService_GetRecordRating_FullMethodName   = "/MovieService/GetRecordRating"
+	MovieService_ListMovies_FullMethodName        = "/MovieService/ListMovies"
+	MovieService_RateMovie_FullMethodName         = "/MovieService/RateMovie"
 )
 
 // MovieServiceClient is the client API for MovieService service.
@@ -307,6 +818,10 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type MovieServiceClient interface {
 	GetMovieDetails(ctx context.Context, in *GetMovieDetailsRequest, opts ...grpc.CallOption) (*GetMovieDetailsResponse, error)
+	GetTopRatedMovies(ctx context.Context, in *GetTopRatedMoviesRequest, opts ...grpc.CallOption) (*GetTopRatedMoviesResponse, error)
+	GetRecordRating(ctx context.Context, in *GetRecordRatingRequest, opts ...grpc.CallOption) (*GetRecordRatingResponse, error)
+	ListMovies(ctx context.Context, in *ListMoviesRequest, opts ...grpc.CallOption) (*ListMoviesResponse, error)
+	RateMovie(ctx context.Context, in *RateMovieRequest, opts ...grpc.CallOption) (*RateMovieResponse, error)
 }
 
 type movieServiceClient struct {
@@ -327,11 +842,55 @@ func (c *movieServiceClient) GetMovieDetails(ctx context.Context, in *GetMovieDe
 	return out, nil
 }
 
+func (c *movieServiceClient) GetTopRatedMovies(ctx context.Context, in *GetTopRatedMoviesRequest, opts ...grpc.CallOption) (*GetTopRatedMoviesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTopRatedMoviesResponse)
+	err := c.cc.Invoke(ctx, MovieService_GetTopRatedMovies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *movieServiceClient) GetRecordRating(ctx context.Context, in *GetRecordRatingRequest, opts ...grpc.CallOption) (*GetRecordRatingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRecordRatingResponse)
+	err := c.cc.Invoke(ctx, MovieService_GetRecordRating_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *movieServiceClient) ListMovies(ctx context.Context, in *ListMoviesRequest, opts ...grpc.CallOption) (*ListMoviesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMoviesResponse)
+	err := c.cc.Invoke(ctx, MovieService_ListMovies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *movieServiceClient) RateMovie(ctx context.Context, in *RateMovieRequest, opts ...grpc.CallOption) (*RateMovieResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RateMovieResponse)
+	err := c.cc.Invoke(ctx, MovieService_RateMovie_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MovieServiceServer is the server API for MovieService service.
 // All implementations must embed UnimplementedMovieServiceServer
 // for forward compatibility.
 type MovieServiceServer interface {
 	GetMovieDetails(context.Context, *GetMovieDetailsRequest) (*GetMovieDetailsResponse, error)
+	GetTopRatedMovies(context.Context, *GetTopRatedMoviesRequest) (*GetTopRatedMoviesResponse, error)
+	GetRecordRating(context.Context, *GetRecordRatingRequest) (*GetRecordRatingResponse, error)
+	ListMovies(context.Context, *ListMoviesRequest) (*ListMoviesResponse, error)
+	RateMovie(context.Context, *RateMovieRequest) (*RateMovieResponse, error)
 	mustEmbedUnimplementedMovieServiceServer()
 }
 
@@ -345,6 +904,18 @@ type UnimplementedMovieServiceServer struct{}
 func (UnimplementedMovieServiceServer) GetMovieDetails(context.Context, *GetMovieDetailsRequest) (*GetMovieDetailsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMovieDetails not implemented")
 }
+func (UnimplementedMovieServiceServer) GetTopRatedMovies(context.Context, *GetTopRatedMoviesRequest) (*GetTopRatedMoviesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTopRatedMovies not implemented")
+}
+func (UnimplementedMovieServiceServer) GetRecordRating(context.Context, *GetRecordRatingRequest) (*GetRecordRatingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecordRating not implemented")
+}
+func (UnimplementedMovieServiceServer) ListMovies(context.Context, *ListMoviesRequest) (*ListMoviesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMovies not implemented")
+}
+func (UnimplementedMovieServiceServer) RateMovie(context.Context, *RateMovieRequest) (*RateMovieResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RateMovie not implemented")
+}
 func (UnimplementedMovieServiceServer) mustEmbedUnimplementedMovieServiceServer() {}
 func (UnimplementedMovieServiceServer) testEmbeddedByValue()                      {}
 
@@ -384,6 +955,78 @@ func _MovieService_GetMovieDetails_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MovieService_GetTopRatedMovies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTopRatedMoviesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieServiceServer).GetTopRatedMovies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieService_GetTopRatedMovies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieServiceServer).GetTopRatedMovies(ctx, req.(*GetTopRatedMoviesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MovieService_GetRecordRating_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRecordRatingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieServiceServer).GetRecordRating(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieService_GetRecordRating_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieServiceServer).GetRecordRating(ctx, req.(*GetRecordRatingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MovieService_ListMovies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMoviesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieServiceServer).ListMovies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieService_ListMovies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieServiceServer).ListMovies(ctx, req.(*ListMoviesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MovieService_RateMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RateMovieRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieServiceServer).RateMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieService_RateMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieServiceServer).RateMovie(ctx, req.(*RateMovieRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MovieService_ServiceDesc is the grpc.ServiceDesc for MovieService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -395,6 +1038,22 @@ var MovieService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetMovieDetails",
 			Handler:    _MovieService_GetMovieDetails_Handler,
 		},
+		{
+			MethodName: "GetTopRatedMovies",
+			Handler:    _MovieService_GetTopRatedMovies_Handler,
+		},
+		{
+			MethodName: "GetRecordRating",
+			Handler:    _MovieService_GetRecordRating_Handler,
+		},
+		{
+			MethodName: "ListMovies",
+			Handler:    _MovieService_ListMovies_Handler,
+		},
+		{
+			MethodName: "RateMovie",
+			Handler:    _MovieService_RateMovie_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "movie.proto",
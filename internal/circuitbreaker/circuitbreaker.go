@@ -0,0 +1,290 @@
+// Package circuitbreaker stops calling a failing dependency for a while, so
+// that callers fail fast instead of waiting on it.
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned for the calls rejected by an open circuit breaker.
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State is the state of a circuit breaker.
+type State int
+
+const (
+	// Closed breakers allow every call and open when too many calls fail or
+	// are slow.
+	Closed State = iota
+	// HalfOpen breakers allow a few probe calls, and close if they all
+	// succeed or open again if one fails.
+	HalfOpen
+	// Open breakers reject every call until their open timeout expires.
+	Open
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case HalfOpen:
+		return "half-open"
+	case Open:
+		return "open"
+	}
+	return "unknown"
+}
+
+// MarshalText encodes the state as its name.
+func (s State) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Result is the outcome of a call allowed by a breaker.
+type Result int
+
+const (
+	Success Result = iota
+	Failure
+	// Ignored calls, such as the calls canceled by their callers, are not
+	// counted.
+	Ignored
+)
+
+// Default options.
+const (
+	DefaultWindow           = 10 * time.Second
+	DefaultMinRequests      = 20
+	DefaultFailureRate      = 0.5
+	DefaultSlowCallRate     = 0.5
+	DefaultOpenTimeout      = 30 * time.Second
+	DefaultHalfOpenRequests = 1
+)
+
+// windowBuckets is the number of buckets the window of a breaker is split
+// into. Calls leave the window one bucket at a time.
+const windowBuckets = 10
+
+// Options configures a circuit breaker. Zero fields take their default value.
+type Options struct {
+	// Window is the time over which the failure and slow call rates are
+	// computed.
+	Window time.Duration
+	// MinRequests is the number of calls in the window below which the
+	// breaker does not open.
+	MinRequests int
+	// FailureRate is the fraction of failed calls in the window that opens
+	// the breaker.
+	FailureRate float64
+	// SlowCallDuration is the latency above which calls are slow. Slow calls
+	// do not open the breaker if it is zero.
+	SlowCallDuration time.Duration
+	// SlowCallRate is the fraction of slow calls in the window that opens
+	// the breaker.
+	SlowCallRate float64
+	// OpenTimeout is the time an open breaker rejects calls for before it
+	// becomes half-open.
+	OpenTimeout time.Duration
+	// HalfOpenRequests is the number of probe calls of a half-open breaker.
+	HalfOpenRequests int
+	// OnStateChange is called with the breaker lock held when the state
+	// changes, and must not call the breaker.
+	OnStateChange func(from, to State)
+}
+
+// Stats describes the state of a breaker and the calls of its window.
+type Stats struct {
+	State     State `json:"state" xml:"state"`
+	Requests  int   `json:"requests" xml:"requests"`
+	Failures  int   `json:"failures" xml:"failures"`
+	SlowCalls int   `json:"slowCalls" xml:"slowCalls"`
+	// OpenedAt is the time the breaker last opened, if it is not closed.
+	OpenedAt *time.Time `json:"openedAt,omitempty" xml:"openedAt,omitempty"`
+}
+
+// Breaker is a circuit breaker. It is safe for concurrent use.
+type Breaker struct {
+	opts Options
+	now  func() time.Time
+
+	mu    sync.Mutex
+	state State
+	// generation changes with the state, so that the results of the calls
+	// allowed in a previous state are not counted.
+	generation uint64
+	buckets    [windowBuckets]bucket
+	openedAt   time.Time
+	// probes and probeSuccesses count the allowed and successful calls of a
+	// half-open breaker.
+	probes         int
+	probeSuccesses int
+}
+
+// bucket counts the calls of a slice of the window.
+type bucket struct {
+	epoch     int64
+	calls     int
+	failures  int
+	slowCalls int
+}
+
+// New creates a closed circuit breaker.
+func New(opts Options) *Breaker {
+	if opts.Window <= 0 {
+		opts.Window = DefaultWindow
+	}
+	if opts.MinRequests <= 0 {
+		opts.MinRequests = DefaultMinRequests
+	}
+	if opts.FailureRate <= 0 {
+		opts.FailureRate = DefaultFailureRate
+	}
+	if opts.SlowCallRate <= 0 {
+		opts.SlowCallRate = DefaultSlowCallRate
+	}
+	if opts.OpenTimeout <= 0 {
+		opts.OpenTimeout = DefaultOpenTimeout
+	}
+	if opts.HalfOpenRequests <= 0 {
+		opts.HalfOpenRequests = DefaultHalfOpenRequests
+	}
+	return &Breaker{opts: opts, now: time.Now}
+}
+
+// Allow reports whether a call may proceed. It returns ErrOpen if the call
+// is rejected, or a function that must be called with the result and
+// latency of the allowed call.
+func (b *Breaker) Allow() (func(result Result, latency time.Duration), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	if b.state == Open && now.Sub(b.openedAt) >= b.opts.OpenTimeout {
+		b.setState(HalfOpen, now)
+	}
+	switch b.state {
+	case Open:
+		return nil, ErrOpen
+	case HalfOpen:
+		if b.probes >= b.opts.HalfOpenRequests {
+			return nil, ErrOpen
+		}
+		b.probes++
+	}
+	generation := b.generation
+	var once sync.Once
+	return func(result Result, latency time.Duration) {
+		once.Do(func() { b.done(generation, result, latency) })
+	}, nil
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() State {
+	return b.Stats().State
+}
+
+// Stats returns the state of the breaker and the calls of its window.
+func (b *Breaker) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	s := Stats{State: b.state}
+	if b.state == Open && now.Sub(b.openedAt) >= b.opts.OpenTimeout {
+		// The breaker becomes half-open on the next call.
+		s.State = HalfOpen
+	}
+	if b.state != Closed {
+		openedAt := b.openedAt
+		s.OpenedAt = &openedAt
+	}
+	s.Requests, s.Failures, s.SlowCalls = b.window(now)
+	return s
+}
+
+func (b *Breaker) done(generation uint64, result Result, latency time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if generation != b.generation || result == Ignored {
+		if generation == b.generation && b.state == HalfOpen {
+			// The probe is given back to the next call.
+			b.probes--
+		}
+		return
+	}
+	now := b.now()
+	slow := b.opts.SlowCallDuration > 0 && latency > b.opts.SlowCallDuration
+	switch b.state {
+	case Closed:
+		b.record(now, result == Failure, slow)
+		calls, failures, slowCalls := b.window(now)
+		if calls < b.opts.MinRequests {
+			return
+		}
+		if float64(failures) >= b.opts.FailureRate*float64(calls) ||
+			(b.opts.SlowCallDuration > 0 && float64(slowCalls) >= b.opts.SlowCallRate*float64(calls)) {
+			b.setState(Open, now)
+		}
+	case HalfOpen:
+		if result == Failure || slow {
+			b.setState(Open, now)
+			return
+		}
+		b.probeSuccesses++
+		if b.probeSuccesses >= b.opts.HalfOpenRequests {
+			b.setState(Closed, now)
+		}
+	}
+}
+
+// setState moves the breaker to a state. b.mu must be held.
+func (b *Breaker) setState(state State, now time.Time) {
+	from := b.state
+	b.state = state
+	b.generation++
+	b.probes = 0
+	b.probeSuccesses = 0
+	switch state {
+	case Open:
+		b.openedAt = now
+	case Closed:
+		b.buckets = [windowBuckets]bucket{}
+	}
+	if b.opts.OnStateChange != nil {
+		b.opts.OnStateChange(from, state)
+	}
+}
+
+// record counts a call in the bucket of the current time. b.mu must be held.
+func (b *Breaker) record(now time.Time, failed bool, slow bool) {
+	epoch := b.epoch(now)
+	bk := &b.buckets[epoch%windowBuckets]
+	if bk.epoch != epoch {
+		*bk = bucket{epoch: epoch}
+	}
+	bk.calls++
+	if failed {
+		bk.failures++
+	}
+	if slow {
+		bk.slowCalls++
+	}
+}
+
+// window returns the counts of the calls in the window. b.mu must be held.
+func (b *Breaker) window(now time.Time) (calls, failures, slowCalls int) {
+	epoch := b.epoch(now)
+	for _, bk := range b.buckets {
+		if bk.epoch > epoch-windowBuckets {
+			calls += bk.calls
+			failures += bk.failures
+			slowCalls += bk.slowCalls
+		}
+	}
+	return calls, failures, slowCalls
+}
+
+// epoch returns the index of the bucket of a time since the Unix epoch.
+func (b *Breaker) epoch(t time.Time) int64 {
+	return t.UnixNano() / int64(max(b.opts.Window/windowBuckets, 1))
+}
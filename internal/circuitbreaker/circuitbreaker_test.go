@@ -0,0 +1,102 @@
+package circuitbreaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBreaker returns a breaker whose clock is advanced by the returned function.
+func testBreaker(opts Options) (*Breaker, func(time.Duration)) {
+	now := time.Unix(1700000000, 0)
+	b := New(opts)
+	b.now = func() time.Time { return now }
+	return b, func(d time.Duration) { now = now.Add(d) }
+}
+
+func call(t *testing.T, b *Breaker, result Result, latency time.Duration) {
+	t.Helper()
+	done, err := b.Allow()
+	require.NoError(t, err)
+	done(result, latency)
+}
+
+func TestBreaker(t *testing.T) {
+	var transitions []string
+	b, advance := testBreaker(Options{
+		MinRequests:      4,
+		OpenTimeout:      time.Minute,
+		HalfOpenRequests: 2,
+		OnStateChange: func(from, to State) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	})
+
+	call(t, b, Success, 0)
+	call(t, b, Failure, 0)
+	call(t, b, Success, 0)
+	assert.Equal(t, Closed, b.State(), "below the minimum number of requests")
+	call(t, b, Failure, 0)
+	assert.Equal(t, Open, b.State(), "half of the calls failed")
+
+	_, err := b.Allow()
+	assert.ErrorIs(t, err, ErrOpen)
+
+	advance(time.Minute)
+	probe1, err := b.Allow()
+	require.NoError(t, err)
+	probe2, err := b.Allow()
+	require.NoError(t, err)
+	_, err = b.Allow()
+	assert.ErrorIs(t, err, ErrOpen, "probes are limited")
+	probe1(Ignored, 0)
+	probe3, err := b.Allow()
+	require.NoError(t, err, "ignored probes are given back")
+	probe2(Success, 0)
+	probe3(Failure, 0)
+	assert.Equal(t, Open, b.State(), "a failed probe opens the breaker")
+
+	advance(time.Minute)
+	call(t, b, Success, 0)
+	call(t, b, Success, 0)
+	assert.Equal(t, Closed, b.State())
+	assert.Equal(t, Stats{State: Closed}, b.Stats(), "the window is reset")
+
+	assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}, transitions)
+}
+
+func TestBreakerSlowCalls(t *testing.T) {
+	b, _ := testBreaker(Options{MinRequests: 2, SlowCallDuration: 100 * time.Millisecond, SlowCallRate: 1})
+	call(t, b, Success, 200*time.Millisecond)
+	call(t, b, Success, 50*time.Millisecond)
+	assert.Equal(t, Closed, b.State())
+	call(t, b, Success, 200*time.Millisecond)
+	call(t, b, Success, 200*time.Millisecond)
+	assert.Equal(t, Closed, b.State(), "3 of 4 calls are slow")
+
+	b, _ = testBreaker(Options{MinRequests: 2, SlowCallDuration: 100 * time.Millisecond})
+	call(t, b, Success, 200*time.Millisecond)
+	call(t, b, Success, 50*time.Millisecond)
+	assert.Equal(t, Open, b.State(), "half of the calls are slow")
+}
+
+func TestBreakerWindow(t *testing.T) {
+	b, advance := testBreaker(Options{Window: 10 * time.Second, MinRequests: 2})
+	call(t, b, Failure, 0)
+	advance(10 * time.Second)
+	call(t, b, Success, 0)
+	call(t, b, Success, 0)
+	assert.Equal(t, Closed, b.State(), "the failure left the window")
+	assert.Equal(t, 2, b.Stats().Requests)
+
+	// Calls allowed before the breaker opens are not counted after it.
+	done, err := b.Allow()
+	require.NoError(t, err)
+	call(t, b, Failure, 0)
+	call(t, b, Failure, 0)
+	require.Equal(t, Open, b.State())
+	done(Success, 0)
+	assert.Equal(t, 4, b.Stats().Requests)
+}
@@ -0,0 +1,93 @@
+// Package dataloader batches and caches the lookups of values by key made
+// while serving a single request.
+package dataloader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Loader collects the keys loaded during a short wait, and then fetches all
+// of them with a single call. Values are cached, so a Loader should only live
+// as long as the request it serves.
+type Loader[K comparable, V any] struct {
+	fetch    func(ctx context.Context, keys []K) (map[K]V, error)
+	maxBatch int
+	wait     time.Duration
+
+	mu      sync.Mutex
+	pending *batch[K, V]
+	batches map[K]*batch[K, V]
+}
+
+// batch is a set of keys fetched with a single call.
+type batch[K comparable, V any] struct {
+	ctx    context.Context
+	keys   []K
+	once   sync.Once
+	done   chan struct{}
+	values map[K]V
+	err    error
+}
+
+// New creates a loader fetching the keys loaded within wait of the first key
+// of a batch with a single call to fetch. A batch is fetched as soon as it
+// has maxBatch keys, or is only limited by the wait if maxBatch is zero. Keys
+// missing from the values returned by fetch get the zero value.
+func New[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error), maxBatch int, wait time.Duration) *Loader[K, V] {
+	return &Loader[K, V]{fetch: fetch, maxBatch: maxBatch, wait: wait, batches: map[K]*batch[K, V]{}}
+}
+
+// Load adds a key to the pending batch and returns a function waiting for its
+// value. The batch is fetched with the context of its first key.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) func() (V, error) {
+	l.mu.Lock()
+	b, ok := l.batches[key]
+	if !ok {
+		if l.pending == nil {
+			pending := &batch[K, V]{ctx: ctx, done: make(chan struct{})}
+			l.pending = pending
+			time.AfterFunc(l.wait, func() { l.dispatch(pending) })
+		}
+		b = l.pending
+		b.keys = append(b.keys, key)
+		l.batches[key] = b
+		if l.maxBatch > 0 && len(b.keys) >= l.maxBatch {
+			l.pending = nil
+			go l.dispatch(b)
+		}
+	}
+	l.mu.Unlock()
+	return func() (V, error) {
+		<-b.done
+		return b.values[key], b.err
+	}
+}
+
+// dispatch fetches a batch once, when it is full or its wait is over.
+func (l *Loader[K, V]) dispatch(b *batch[K, V]) {
+	b.once.Do(func() {
+		l.mu.Lock()
+		if l.pending == b {
+			l.pending = nil
+		}
+		keys := b.keys
+		l.mu.Unlock()
+		b.values, b.err = l.fetch(b.ctx, keys)
+		close(b.done)
+	})
+}
+
+// Prime caches the value of a key, such as a value fetched with a list, so
+// that loading it does not fetch it again.
+func (l *Loader[K, V]) Prime(key K, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.batches[key]; ok {
+		return
+	}
+	b := &batch[K, V]{keys: []K{key}, done: make(chan struct{}), values: map[K]V{key: value}}
+	b.once.Do(func() { close(b.done) })
+	l.batches[key] = b
+}
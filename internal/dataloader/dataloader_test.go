@@ -0,0 +1,85 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder fetches the length of keys and records the batches.
+type recorder struct {
+	mu      sync.Mutex
+	batches [][]string
+	err     error
+}
+
+func (r *recorder) fetch(_ context.Context, keys []string) (map[string]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, keys)
+	if r.err != nil {
+		return nil, r.err
+	}
+	res := map[string]int{}
+	for _, k := range keys {
+		if k != "missing" {
+			res[k] = len(k)
+		}
+	}
+	return res, nil
+}
+
+func TestLoad(t *testing.T) {
+	ctx := context.Background()
+	r := &recorder{}
+	l := New(r.fetch, 0, 10*time.Millisecond)
+	l.Prime("primed", 42)
+
+	// Keys loaded concurrently are fetched with a single batch.
+	keys := []string{"a", "bb", "missing", "a", "primed"}
+	values := make([]int, len(keys))
+	var wg sync.WaitGroup
+	for i, k := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := l.Load(ctx, k)()
+			assert.NoError(t, err)
+			values[i] = v
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, []int{1, 2, 0, 1, 42}, values)
+	assert.Len(t, r.batches, 1)
+	assert.ElementsMatch(t, []string{"a", "bb", "missing"}, r.batches[0])
+
+	// Values are cached.
+	v, err := l.Load(ctx, "bb")()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, v)
+	assert.Len(t, r.batches, 1)
+}
+
+func TestLoadMaxBatch(t *testing.T) {
+	ctx := context.Background()
+	r := &recorder{}
+	// Full batches are fetched without waiting.
+	l := New(r.fetch, 2, time.Hour)
+	a, b := l.Load(ctx, "a"), l.Load(ctx, "b")
+	va, _ := a()
+	vb, _ := b()
+	assert.Equal(t, []int{1, 1}, []int{va, vb})
+	assert.Equal(t, [][]string{{"a", "b"}}, r.batches)
+}
+
+func TestLoadError(t *testing.T) {
+	errFetch := errors.New("fetch failed")
+	r := &recorder{err: errFetch}
+	l := New(r.fetch, 0, time.Millisecond)
+	_, err := l.Load(context.Background(), "a")()
+	assert.ErrorIs(t, err, errFetch)
+}
@@ -2,23 +2,219 @@ package grpcutil
 
 import (
 	"context"
-	"math/rand"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/abhishek622/movieapp/pkg/discovery"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/resolver"
 )
 
-func ServiceConnection(ctx context.Context, serviceName string, registry discovery.Registry, creds credentials.TransportCredentials) (*grpc.ClientConn, error) {
-	addrs, err := registry.ServiceAddresses(ctx, serviceName)
-	if err != nil {
-		return nil, err
+const (
+	// DefaultResolveInterval is the interval at which the addresses of a
+	// service are refreshed from the registry when no interval is set.
+	DefaultResolveInterval = 10 * time.Second
+	// DefaultKeepaliveTime is the time after which an idle connection is
+	// pinged when no keepalive time is set.
+	DefaultKeepaliveTime = 30 * time.Second
+	// MinKeepaliveTime is the shortest keepalive time of pooled connections,
+	// as permitted by the servers using KeepaliveEnforcementPolicy.
+	MinKeepaliveTime = 10 * time.Second
+	// DefaultKeepaliveTimeout is the time a keepalive ping waits for an
+	// acknowledgement before the connection is closed when no timeout is set.
+	DefaultKeepaliveTimeout = 10 * time.Second
+
+	// registryScheme is the resolver scheme of the targets of pooled connections.
+	registryScheme = "registry"
+)
+
+// PoolOptions configures the connections of a Pool.
+type PoolOptions struct {
+	// ResolveInterval is the interval at which service addresses are
+	// refreshed from the registry.
+	ResolveInterval time.Duration
+	// KeepaliveTime and KeepaliveTimeout configure the keepalive pings of
+	// the connections. KeepaliveTime is raised to MinKeepaliveTime if it is
+	// shorter.
+	KeepaliveTime    time.Duration
+	KeepaliveTimeout time.Duration
+	// Authority is the authority of the calls, against which the
+	// certificates of the services are verified. It defaults to the name of
+	// the service, so it must be set if the certificates do not name it.
+	Authority string
+}
+
+// Pool shares long-lived client connections to the services of a registry.
+// Every service has a single connection balancing calls over all the
+// instances of the service, whose addresses are refreshed from the registry
+// in the background.
+type Pool struct {
+	registry discovery.Registry
+	creds    credentials.TransportCredentials
+	opts     PoolOptions
+
+	mu    sync.Mutex
+	conns map[string]*grpc.ClientConn
+}
+
+// NewPool creates a connection pool for the services of a registry.
+func NewPool(registry discovery.Registry, creds credentials.TransportCredentials, opts PoolOptions) *Pool {
+	if opts.ResolveInterval <= 0 {
+		opts.ResolveInterval = DefaultResolveInterval
+	}
+	if opts.KeepaliveTime <= 0 {
+		opts.KeepaliveTime = DefaultKeepaliveTime
 	}
+	opts.KeepaliveTime = max(opts.KeepaliveTime, MinKeepaliveTime)
+	if opts.KeepaliveTimeout <= 0 {
+		opts.KeepaliveTimeout = DefaultKeepaliveTimeout
+	}
+	return &Pool{registry: registry, creds: creds, opts: opts, conns: map[string]*grpc.ClientConn{}}
+}
 
-	return grpc.NewClient(
-		addrs[rand.Intn(len(addrs))],
-		grpc.WithTransportCredentials(creds),
+// Conn returns the connection to a service, creating it on first use. The
+// connection must not be closed by the caller.
+func (p *Pool) Conn(serviceName string) (*grpc.ClientConn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if conn, ok := p.conns[serviceName]; ok {
+		return conn, nil
+	}
+	opts := []grpc.DialOption{
+		grpc.WithResolvers(&registryBuilder{registry: p.registry, interval: p.opts.ResolveInterval}),
+		grpc.WithDefaultServiceConfig(`{"loadBalancingConfig": [{"round_robin": {}}]}`),
+		grpc.WithTransportCredentials(p.creds),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                p.opts.KeepaliveTime,
+			Timeout:             p.opts.KeepaliveTimeout,
+			PermitWithoutStream: true,
+		}),
 		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
-	)
+	}
+	if p.opts.Authority != "" {
+		opts = append(opts, grpc.WithAuthority(p.opts.Authority))
+	}
+	conn, err := grpc.NewClient(registryScheme+":///"+serviceName, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("connect to %s: %w", serviceName, err)
+	}
+	p.conns[serviceName] = conn
+	return conn, nil
+}
+
+// Close closes the connections of the pool.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var firstErr error
+	for name, conn := range p.conns {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(p.conns, name)
+	}
+	return firstErr
+}
+
+// KeepaliveEnforcementPolicy returns the server option permitting the
+// keepalive pings of pooled connections. Servers reject pings more frequent
+// than every 5 minutes by default.
+func KeepaliveEnforcementPolicy() grpc.ServerOption {
+	return grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{MinTime: MinKeepaliveTime, PermitWithoutStream: true})
+}
+
+// registryBuilder builds resolvers of service addresses from a registry.
+type registryBuilder struct {
+	registry discovery.Registry
+	interval time.Duration
+}
+
+func (b *registryBuilder) Scheme() string {
+	return registryScheme
+}
+
+func (b *registryBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &registryResolver{
+		registry: b.registry,
+		service:  target.Endpoint(),
+		interval: b.interval,
+		cc:       cc,
+		cancel:   cancel,
+		now:      make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	go r.watch(ctx)
+	return r, nil
+}
+
+// registryResolver polls the addresses of the gRPC endpoints of a service and
+// updates the connection when they change. Endpoints advertising another
+// protocol are skipped. The last addresses are kept while the
+// registry fails or has no address for the service.
+type registryResolver struct {
+	registry discovery.Registry
+	service  string
+	interval time.Duration
+	cc       resolver.ClientConn
+	cancel   context.CancelFunc
+	now      chan struct{}
+	done     chan struct{}
+}
+
+func (r *registryResolver) watch(ctx context.Context) {
+	defer close(r.done)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	var current []string
+	for {
+		endpoints, err := r.registry.ServiceEndpoints(ctx, r.service, discovery.EndpointGRPC)
+		var addrs []string
+		for _, e := range endpoints {
+			// Instances that did not advertise a protocol are assumed to serve gRPC.
+			if e.Protocol == "" || e.Protocol == "grpc" {
+				addrs = append(addrs, e.HostPort)
+			}
+		}
+		if err == nil && len(addrs) == 0 {
+			err = discovery.ErrNotFound
+		}
+		if err != nil && current == nil && ctx.Err() == nil {
+			r.cc.ReportError(fmt.Errorf("resolve %s: %w", r.service, err))
+		} else if err == nil {
+			slices.Sort(addrs)
+			if !slices.Equal(addrs, current) {
+				current = addrs
+				state := resolver.State{}
+				for _, addr := range addrs {
+					state.Addresses = append(state.Addresses, resolver.Address{Addr: addr})
+				}
+				r.cc.UpdateState(state)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.now:
+		}
+	}
+}
+
+// ResolveNow refreshes the addresses without waiting for the next poll.
+func (r *registryResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.now <- struct{}{}:
+	default:
+	}
+}
+
+func (r *registryResolver) Close() {
+	r.cancel()
+	<-r.done
 }
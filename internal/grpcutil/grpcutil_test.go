@@ -0,0 +1,140 @@
+package grpcutil
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/abhishek622/movieapp/pkg/discovery"
+	"github.com/abhishek622/movieapp/pkg/discovery/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// startServer starts a health server reporting a status for the "test"
+// service, and returns its address.
+func startServer(t *testing.T, status healthpb.HealthCheckResponse_ServingStatus, opts ...grpc.ServerOption) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer(append(opts, KeepaliveEnforcementPolicy())...)
+	h := health.NewServer()
+	h.SetServingStatus("test", status)
+	healthpb.RegisterHealthServer(srv, h)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+func TestPoolRefreshesAddresses(t *testing.T) {
+	ctx := context.Background()
+	registry := memory.NewRegistry()
+	require.NoError(t, registry.Register(ctx, "test-1", "test", []discovery.Endpoint{{Name: discovery.EndpointGRPC, Protocol: "grpc", HostPort: startServer(t, healthpb.HealthCheckResponse_SERVING)}}))
+
+	pool := NewPool(registry, insecure.NewCredentials(), PoolOptions{ResolveInterval: 10 * time.Millisecond})
+	defer pool.Close()
+	conn, err := pool.Conn("test")
+	require.NoError(t, err)
+	same, err := pool.Conn("test")
+	require.NoError(t, err)
+	assert.Same(t, conn, same, "connections are shared")
+
+	check := func() healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: "test"})
+		if err != nil {
+			return healthpb.HealthCheckResponse_UNKNOWN
+		}
+		return resp.Status
+	}
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, check())
+
+	require.NoError(t, registry.Deregister(ctx, "test-1", "test"))
+	require.NoError(t, registry.Register(ctx, "test-2", "test", []discovery.Endpoint{{Name: discovery.EndpointGRPC, Protocol: "grpc", HostPort: startServer(t, healthpb.HealthCheckResponse_NOT_SERVING)}}))
+	assert.Eventually(t, func() bool {
+		return check() == healthpb.HealthCheckResponse_NOT_SERVING
+	}, 5*time.Second, 10*time.Millisecond, "calls are sent to the new address")
+}
+
+// newCertificate returns a certificate for localhost and 127.0.0.1, as
+// generated with san.cnf, signed by a new CA, and a pool of the CA.
+func newCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, ca, ca, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err = x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pool
+}
+
+func TestPoolVerifiesAuthority(t *testing.T) {
+	ctx := context.Background()
+	cert, certPool := newCertificate(t)
+	addr := startServer(t, healthpb.HealthCheckResponse_SERVING, grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    certPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS13,
+	})))
+	registry := memory.NewRegistry()
+	require.NoError(t, registry.Register(ctx, "test-1", "test", []discovery.Endpoint{{Name: discovery.EndpointGRPC, Protocol: "grpc", HostPort: addr}}))
+	creds := credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      certPool,
+		MinVersion:   tls.VersionTLS13,
+	})
+	check := func(opts PoolOptions) error {
+		pool := NewPool(registry, creds, opts)
+		defer pool.Close()
+		conn, err := pool.Conn("test")
+		require.NoError(t, err)
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: "test"})
+		return err
+	}
+
+	// The certificate does not name the service, so the handshake only
+	// succeeds with the authority of the certificate.
+	assert.Error(t, check(PoolOptions{}))
+	assert.NoError(t, check(PoolOptions{Authority: "localhost"}))
+}
@@ -0,0 +1,246 @@
+// Package httputil writes HTTP API responses in the format negotiated with
+// the Accept header, gzip-compressed if the client accepts it, and errors as
+// RFC 7807 problem details.
+package httputil
+
+import (
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Format is a response format that can be negotiated.
+type Format int
+
+const (
+	// FormatJSON is the JSON encoding of the service models.
+	FormatJSON Format = iota
+	// FormatProtobuf is the protobuf binary encoding of the API messages.
+	FormatProtobuf
+	// FormatProtoJSON is the protobuf JSON encoding of the API messages.
+	FormatProtoJSON
+	// FormatXML is the XML encoding of the service models.
+	FormatXML
+)
+
+// Media types of the response formats. The first media type of every format
+// is the one set in the Content-Type header, the others are accepted aliases.
+var mediaTypes = map[Format][]string{
+	FormatJSON:      {"application/json"},
+	FormatProtobuf:  {"application/x-protobuf", "application/protobuf", "application/vnd.google.protobuf"},
+	FormatProtoJSON: {"application/x-protobuf+json", "application/protobuf+json"},
+	FormatXML:       {"application/xml", "text/xml"},
+}
+
+// ContentType returns the Content-Type of a format.
+func (f Format) ContentType() string {
+	return mediaTypes[f][0]
+}
+
+// minGzipSize is the size below which responses are not worth compressing.
+const minGzipSize = 512
+
+// ErrNotAcceptable is returned when none of the accepted media types can be produced.
+var ErrNotAcceptable = errors.New("none of the accepted media types can be produced")
+
+// Negotiate returns the format of the response to a request from its Accept
+// header. Media types are tried by decreasing quality and then in order.
+// Wildcards and a missing header select JSON.
+func Negotiate(req *http.Request) (Format, error) {
+	accept := req.Header.Values("Accept")
+	if len(accept) == 0 {
+		return FormatJSON, nil
+	}
+	for _, r := range parseAccept(strings.Join(accept, ",")) {
+		switch r.value {
+		case "*/*", "application/*":
+			return FormatJSON, nil
+		case "text/*":
+			return FormatXML, nil
+		}
+		for _, f := range []Format{FormatJSON, FormatProtobuf, FormatProtoJSON, FormatXML} {
+			if slices.Contains(mediaTypes[f], r.value) {
+				return f, nil
+			}
+		}
+	}
+	return 0, ErrNotAcceptable
+}
+
+// Respond writes v in the format negotiated for the request with status 200.
+// JSON and XML responses encode v, protobuf responses encode msg. A nil msg
+// means that the response has no protobuf representation. XML responses have
+// a root element named root. The elements of slices are wrapped in it and
+// named by the part of root after ">", as in "movies>movie".
+func Respond(w http.ResponseWriter, req *http.Request, root string, v any, msg proto.Message) {
+	format, err := Negotiate(req)
+	if err != nil {
+		Error(w, req, http.StatusNotAcceptable, err.Error())
+		return
+	}
+	var body []byte
+	switch format {
+	case FormatJSON:
+		body, err = json.Marshal(v)
+	case FormatXML:
+		body, err = marshalXML(root, v)
+	case FormatProtobuf, FormatProtoJSON:
+		if msg == nil {
+			Error(w, req, http.StatusNotAcceptable, "the response has no protobuf representation")
+			return
+		}
+		if format == FormatProtobuf {
+			body, err = proto.Marshal(msg)
+		} else {
+			body, err = protojson.Marshal(msg)
+		}
+	}
+	if err != nil {
+		log.Printf("Response encode error: %v\n", err)
+		Error(w, req, http.StatusInternalServerError, "the response cannot be encoded")
+		return
+	}
+	write(w, req, http.StatusOK, format.ContentType(), body)
+}
+
+// marshalXML encodes v as an XML document with a root element named root.
+func marshalXML(root string, v any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	root, item, isList := strings.Cut(root, ">")
+	start := xml.StartElement{Name: xml.Name{Local: root}}
+	if rv := reflect.Indirect(reflect.ValueOf(v)); rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		if !isList {
+			return nil, fmt.Errorf("no element name for the items of XML root %q", root)
+		}
+		if err := enc.EncodeToken(start); err != nil {
+			return nil, err
+		}
+		for i := range rv.Len() {
+			if err := enc.EncodeElement(rv.Index(i).Interface(), xml.StartElement{Name: xml.Name{Local: item}}); err != nil {
+				return nil, err
+			}
+		}
+		if err := enc.EncodeToken(start.End()); err != nil {
+			return nil, err
+		}
+	} else if err := enc.EncodeElement(v, start); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// write writes a response body, compressed with gzip if the client accepts it
+// and the body is large enough.
+func write(w http.ResponseWriter, req *http.Request, status int, contentType string, body []byte) {
+	h := w.Header()
+	h.Set("Content-Type", contentType)
+	h.Add("Vary", "Accept")
+	h.Add("Vary", "Accept-Encoding")
+	if len(body) >= minGzipSize && acceptsGzip(req) {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(body); err == nil && zw.Close() == nil {
+			h.Set("Content-Encoding", "gzip")
+			body = buf.Bytes()
+		}
+	}
+	h.Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(status)
+	if _, err := w.Write(body); err != nil {
+		log.Printf("Response write error: %v\n", err)
+	}
+}
+
+// acceptsGzip reports whether the Accept-Encoding header of a request accepts gzip.
+func acceptsGzip(req *http.Request) bool {
+	for _, r := range parseAccept(strings.Join(req.Header.Values("Accept-Encoding"), ",")) {
+		if r.value == "gzip" || r.value == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptRange is a media range or content coding of an Accept or
+// Accept-Encoding header with its quality.
+type acceptRange struct {
+	value   string
+	quality float64
+}
+
+// parseAccept returns the ranges of an Accept or Accept-Encoding header by
+// decreasing quality, without the ranges of quality 0.
+func parseAccept(header string) []acceptRange {
+	var res []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		value, params, _ := strings.Cut(part, ";")
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		r := acceptRange{value: value, quality: 1}
+		for _, p := range strings.Split(params, ";") {
+			k, v, _ := strings.Cut(strings.TrimSpace(p), "=")
+			if strings.EqualFold(k, "q") {
+				if q, err := strconv.ParseFloat(v, 64); err == nil {
+					r.quality = q
+				}
+			}
+		}
+		if r.quality > 0 {
+			res = append(res, r)
+		}
+	}
+	slices.SortStableFunc(res, func(a, b acceptRange) int { return cmp.Compare(b.quality, a.quality) })
+	return res
+}
+
+// Problem is an RFC 7807 problem details object.
+type Problem struct {
+	XMLName xml.Name `json:"-" xml:"urn:ietf:rfc:7807 problem"`
+	// Type is a URI reference identifying the problem type. It is
+	// "about:blank" for problems described by their status code only.
+	Type     string `json:"type" xml:"type"`
+	Title    string `json:"title" xml:"title"`
+	Status   int    `json:"status" xml:"status"`
+	Detail   string `json:"detail,omitempty" xml:"detail,omitempty"`
+	Instance string `json:"instance,omitempty" xml:"instance,omitempty"`
+}
+
+// Error writes an RFC 7807 problem details response. Problems are written as
+// XML to clients that negotiate XML and as JSON to all other clients.
+func Error(w http.ResponseWriter, req *http.Request, status int, detail string) {
+	p := Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: req.URL.Path,
+	}
+	// Problems only hold strings and an int, so encoding cannot fail.
+	if format, _ := Negotiate(req); format == FormatXML {
+		body, _ := xml.Marshal(p)
+		write(w, req, status, "application/problem+xml", append([]byte(xml.Header), body...))
+		return
+	}
+	body, _ := json.Marshal(p)
+	write(w, req, status, "application/problem+json", body)
+}
@@ -0,0 +1,104 @@
+package httputil
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/abhishek622/movieapp/gen"
+	"github.com/abhishek622/movieapp/metadata/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept  string
+		want    Format
+		wantErr error
+	}{
+		{accept: "", want: FormatJSON},
+		{accept: "*/*", want: FormatJSON},
+		{accept: "application/x-protobuf", want: FormatProtobuf},
+		{accept: "application/protobuf+json", want: FormatProtoJSON},
+		{accept: "text/html, application/xml;q=0.9, */*;q=0.8", want: FormatXML},
+		{accept: "application/json;q=0.5, application/vnd.google.protobuf", want: FormatProtobuf},
+		{accept: "application/xml;q=0, application/json", want: FormatJSON},
+		{accept: "text/html", wantErr: ErrNotAcceptable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metadata", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			got, err := Negotiate(req)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRespond(t *testing.T) {
+	m := &model.Metadata{ID: "1", Title: strings.Repeat("title ", 100), Genres: []string{"drama"}}
+	respond := func(header http.Header) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, "/metadata", nil)
+		req.Header = header
+		w := httptest.NewRecorder()
+		Respond(w, req, "metadata", m, model.MetadataToProto(m))
+		return w.Result()
+	}
+
+	resp := respond(http.Header{"Accept": {"application/x-protobuf"}})
+	assert.Equal(t, "application/x-protobuf", resp.Header.Get("Content-Type"))
+	body, _ := io.ReadAll(resp.Body)
+	var p gen.Metadata
+	require.NoError(t, proto.Unmarshal(body, &p))
+	assert.Equal(t, m.Title, p.Title)
+
+	resp = respond(http.Header{"Accept": {"application/xml"}})
+	body, _ = io.ReadAll(resp.Body)
+	assert.Contains(t, string(body), "<metadata><id>1</id>")
+	assert.Contains(t, string(body), "<genres><genre>drama</genre></genres>")
+
+	// Large responses are compressed for clients that accept gzip.
+	resp = respond(http.Header{"Accept-Encoding": {"gzip"}})
+	assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+	zr, err := gzip.NewReader(resp.Body)
+	require.NoError(t, err)
+	var got model.Metadata
+	require.NoError(t, json.NewDecoder(zr).Decode(&got))
+	assert.Equal(t, *m, got)
+
+	// Responses without a protobuf representation are not acceptable to protobuf clients.
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.Header.Set("Accept", "application/x-protobuf")
+	w := httptest.NewRecorder()
+	Respond(w, req, "values>value", []int{1}, nil)
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	var problem Problem
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&problem))
+	assert.Equal(t, Problem{Type: "about:blank", Title: "Not Acceptable", Status: http.StatusNotAcceptable, Detail: "the response has no protobuf representation", Instance: "/admin"}, problem)
+}
+
+func TestRespondXMLList(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/metadata/list", nil)
+	req.Header.Set("Accept", "application/xml")
+	w := httptest.NewRecorder()
+	Respond(w, req, "movies>movie", []model.Metadata{{ID: "1"}, {ID: "2"}}, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "<movies><movie><id>1</id>")
+	assert.Contains(t, w.Body.String(), "<movie><id>2</id>")
+	assert.True(t, strings.HasSuffix(w.Body.String(), "</movie></movies>"))
+
+	// Lists need an element name for their items.
+	w = httptest.NewRecorder()
+	Respond(w, req, "movies", []model.Metadata{{ID: "1"}}, nil)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
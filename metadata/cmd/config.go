@@ -8,7 +8,9 @@ type config struct {
 }
 
 type apiConfig struct {
-	Port int `yaml:"port"`
+	// Port and HTTPPort are the ports of the gRPC and HTTP APIs.
+	Port     int `yaml:"port"`
+	HTTPPort int `yaml:"httpPort"`
 }
 
 type serviceDiscoveryConfig struct {
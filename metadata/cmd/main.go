@@ -16,6 +16,7 @@ import (
 	_ "net/http/pprof"
 
 	"github.com/abhishek622/movieapp/gen"
+	"github.com/abhishek622/movieapp/internal/grpcutil"
 	"github.com/abhishek622/movieapp/metadata/internal/controller/metadata"
 	grpchandler "github.com/abhishek622/movieapp/metadata/internal/handler/grpc"
 	httphandler "github.com/abhishek622/movieapp/metadata/internal/handler/http"
@@ -127,7 +128,14 @@ func main() {
 
 	// --- Service registration / health heartbeat ---
 	instanceID := discovery.GenerateInstanceID(serviceName)
-	if err := registry.Register(ctx, instanceID, serviceName, fmt.Sprintf("localhost:%d", port)); err != nil {
+	endpoints := []discovery.Endpoint{
+		{Name: discovery.EndpointGRPC, Protocol: "grpc", HostPort: fmt.Sprintf("localhost:%d", port)},
+		{Name: discovery.EndpointHTTP, Protocol: "http", HostPort: fmt.Sprintf("localhost:%d", cfg.API.HTTPPort)},
+	}
+	if cfg.Prometheus.MetricsPort != 0 {
+		endpoints = append(endpoints, discovery.Endpoint{Name: discovery.EndpointMetrics, Protocol: "http", HostPort: fmt.Sprintf("localhost:%d", cfg.Prometheus.MetricsPort)})
+	}
+	if err := registry.Register(ctx, instanceID, serviceName, endpoints); err != nil {
 		logger.Fatal("Failed to register service", zap.Error(err))
 	}
 	go func() {
@@ -168,8 +176,10 @@ func main() {
 	go func() {
 		httpMux := http.NewServeMux()
 		httpMux.HandleFunc("/metadata", httpHandler.GetMetadata)
+		httpMux.HandleFunc("/metadata/list", httpHandler.ListMetadata)
+		httpMux.HandleFunc("/metadata/batch", httpHandler.GetMetadataBatch)
 		httpServer := &http.Server{
-			Addr:    fmt.Sprintf("localhost:%d", port+1000), // HTTP on port+1000
+			Addr:    fmt.Sprintf("localhost:%d", cfg.API.HTTPPort),
 			Handler: httpMux,
 		}
 		logger.Info("Starting HTTP server", zap.String("addr", httpServer.Addr))
@@ -185,6 +195,7 @@ func main() {
 	srv := grpc.NewServer(
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 		grpc.Creds(credentials.NewTLS(tlsConfig)),
+		grpcutil.KeepaliveEnforcementPolicy(),
 	)
 	reflection.Register(srv)
 	gen.RegisterMetadataServiceServer(srv, h)
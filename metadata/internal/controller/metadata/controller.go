@@ -2,18 +2,39 @@ package metadata
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/abhishek622/movieapp/metadata/internal/repository"
 	"github.com/abhishek622/movieapp/metadata/pkg/model"
 )
 
-// ErrNotFound is returned when a requested record is not found.
-var ErrNotFound = errors.New("not found")
+var (
+	// ErrNotFound is returned when a requested record is not found.
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidPageToken is returned when a page token cannot be decoded.
+	ErrInvalidPageToken = errors.New("invalid page token")
+	// ErrBatchTooLarge is returned when more than MaxBatchSize entries are
+	// requested at once.
+	ErrBatchTooLarge = errors.New("batch too large")
+)
+
+const (
+	// DefaultPageSize is the number of entries returned by List when no page size is set.
+	DefaultPageSize = 20
+	// MaxPageSize is the maximum number of entries returned by List.
+	MaxPageSize = 100
+	// MaxBatchSize is the maximum number of entries returned by GetBatch.
+	MaxBatchSize = 100
+)
 
 type metadataRepository interface {
 	Get(ctx context.Context, id string) (*model.Metadata, error)
+	GetBatch(ctx context.Context, ids []string) ([]*model.Metadata, error)
 	Put(ctx context.Context, id string, m *model.Metadata) error
+	List(ctx context.Context, filter model.Filter, after *model.TitleCursor, limit int) ([]*model.Metadata, error)
 }
 
 // Controller defines a metadata service controller.
@@ -35,11 +56,68 @@ func (c *Controller) Get(ctx context.Context, id string) (*model.Metadata, error
 	return res, err
 }
 
+// GetBatch returns the metadata of several movies by id with a single
+// repository lookup, in the order of ids. Movies that are not found are
+// skipped. At most MaxBatchSize movies can be requested at once.
+func (c *Controller) GetBatch(ctx context.Context, ids []string) ([]*model.Metadata, error) {
+	if len(ids) > MaxBatchSize {
+		return nil, fmt.Errorf("%w: more than %d movies requested", ErrBatchTooLarge, MaxBatchSize)
+	}
+	return c.repo.GetBatch(ctx, ids)
+}
+
 // Put writes movie metadata to repository.
 func (c *Controller) Put(ctx context.Context, m *model.Metadata) error {
 	return c.repo.Put(ctx, m.ID, m)
 }
 
+// List returns a page of movie metadata selected by the filter, ordered by
+// title, and the token of the next page if there is one.
+func (c *Controller) List(ctx context.Context, filter model.Filter, pageSize int, pageToken string) ([]*model.Metadata, string, error) {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	} else if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+	var after *model.TitleCursor
+	if pageToken != "" {
+		cursor, err := decodePageToken(pageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		after = cursor
+	}
+	// Fetch one extra entry to find out whether there is a next page.
+	res, err := c.repo.List(ctx, filter, after, pageSize+1)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(res) <= pageSize {
+		return res, "", nil
+	}
+	res = res[:pageSize]
+	last := res[len(res)-1]
+	return res, encodePageToken(model.TitleCursor{Title: last.Title, ID: last.ID}), nil
+}
+
+// encodePageToken returns an opaque token for a metadata list position.
+func encodePageToken(c model.TitleCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodePageToken(token string) (*model.TitleCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	var c model.TitleCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == "" {
+		return nil, ErrInvalidPageToken
+	}
+	return &c, nil
+}
+
 // func (c *Controller) Get(ctx context.Context, id string) (*model.Metadata, error) {
 // 	cacheRes, err := c.cache.Get(ctx, id)
 // 	if err != nil {
@@ -51,3 +51,72 @@ func TestController(t *testing.T) {
 		})
 	}
 }
+
+func TestList(t *testing.T) {
+	ctx := context.Background()
+	filter := model.Filter{Genre: "comedy"}
+	movies := []*model.Metadata{{ID: "1", Title: "a"}, {ID: "2", Title: "b"}, {ID: "3", Title: "c"}}
+	tests := []struct {
+		name      string
+		pageSize  int
+		pageToken string
+		wantAfter *model.TitleCursor
+		wantLimit int
+		repoRes   []*model.Metadata
+		wantRes   []*model.Metadata
+		wantNext  bool
+		wantErr   error
+	}{
+		{name: "default page size", wantLimit: DefaultPageSize + 1, repoRes: movies, wantRes: movies},
+		{name: "max page size", pageSize: MaxPageSize + 1, wantLimit: MaxPageSize + 1, repoRes: movies, wantRes: movies},
+		{name: "next page", pageSize: 2, wantLimit: 3, repoRes: movies, wantRes: movies[:2], wantNext: true},
+		{
+			name:      "page token",
+			pageSize:  2,
+			pageToken: encodePageToken(model.TitleCursor{Title: "b", ID: "2"}),
+			wantAfter: &model.TitleCursor{Title: "b", ID: "2"},
+			wantLimit: 3,
+			repoRes:   movies[2:],
+			wantRes:   movies[2:],
+		},
+		{name: "invalid page token", pageToken: "!", wantErr: ErrInvalidPageToken},
+		{name: "page token without id", pageToken: encodePageToken(model.TitleCursor{Title: "b"}), wantErr: ErrInvalidPageToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repoMock := gen.NewMockmetadataRepository(ctrl)
+			c := New(repoMock)
+			if tt.wantErr == nil {
+				repoMock.EXPECT().List(ctx, filter, tt.wantAfter, tt.wantLimit).Return(tt.repoRes, nil)
+			}
+			res, next, err := c.List(ctx, filter, tt.pageSize, tt.pageToken)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantRes, res)
+			if !tt.wantNext {
+				assert.Empty(t, next)
+				return
+			}
+			cursor, err := decodePageToken(next)
+			assert.NoError(t, err)
+			assert.Equal(t, &model.TitleCursor{Title: "b", ID: "2"}, cursor)
+		})
+	}
+}
+
+func TestGetBatch(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	repoMock := gen.NewMockmetadataRepository(ctrl)
+	c := New(repoMock)
+
+	ids := []string{"1", "2"}
+	want := []*model.Metadata{{ID: "2"}}
+	repoMock.EXPECT().GetBatch(ctx, ids).Return(want, nil)
+	res, err := c.GetBatch(ctx, ids)
+	assert.NoError(t, err)
+	assert.Equal(t, want, res)
+
+	_, err = c.GetBatch(ctx, make([]string, MaxBatchSize+1))
+	assert.ErrorIs(t, err, ErrBatchTooLarge)
+}
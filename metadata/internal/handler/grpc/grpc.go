@@ -15,14 +15,22 @@ import (
 // Handler defines a movie metadata gRPC handler.
 type Handler struct {
 	gen.UnimplementedMetadataServiceServer
-	ctrl               *metadata.Controller
-	getMetadataMetrics *EndpointMetrics
-	putMetadataMetrics *EndpointMetrics
+	ctrl                *metadata.Controller
+	getMetadataMetrics  *EndpointMetrics
+	putMetadataMetrics  *EndpointMetrics
+	listMetadataMetrics *EndpointMetrics
+	getBatchMetrics     *EndpointMetrics
 }
 
 // New creates a new movie metadata gRPC handler.
 func New(ctrl *metadata.Controller, scope tally.Scope) *Handler {
-	return &Handler{ctrl: ctrl, getMetadataMetrics: newEndpointMetrics(scope, "GetMetadata"), putMetadataMetrics: newEndpointMetrics(scope, "PutMetadata")}
+	return &Handler{
+		ctrl:                ctrl,
+		getMetadataMetrics:  newEndpointMetrics(scope, "GetMetadata"),
+		putMetadataMetrics:  newEndpointMetrics(scope, "PutMetadata"),
+		listMetadataMetrics: newEndpointMetrics(scope, "ListMetadata"),
+		getBatchMetrics:     newEndpointMetrics(scope, "GetMetadataBatch"),
+	}
 }
 
 type EndpointMetrics struct {
@@ -73,9 +81,57 @@ func (h *Handler) PutMetadata(ctx context.Context, req *gen.PutMetadataRequest)
 	}
 	if err := h.ctrl.Put(ctx, model.MetadataFromProto(req.Metadata)); err != nil {
 		h.getMetadataMetrics.invalidArgumentErrors.Inc(1)
-		return nil, status.Errorf(codes.Internal, err.Error())
+		return nil, status.Errorf(codes.Internal, "%s", err.Error())
 	}
 
 	h.getMetadataMetrics.successes.Inc(1)
 	return &gen.PutMetadataResponse{}, nil
 }
+
+// ListMetadata returns a page of movie metadata filtered by director and genre.
+func (h *Handler) ListMetadata(ctx context.Context, req *gen.ListMetadataRequest) (*gen.ListMetadataResponse, error) {
+	h.listMetadataMetrics.calls.Inc(1)
+	if req == nil || req.PageSize < 0 {
+		h.listMetadataMetrics.invalidArgumentErrors.Inc(1)
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or negative page size")
+	}
+	filter := model.Filter{Director: req.Director, Genre: req.Genre}
+	res, next, err := h.ctrl.List(ctx, filter, int(req.PageSize), req.PageToken)
+	if err != nil && errors.Is(err, metadata.ErrInvalidPageToken) {
+		h.listMetadataMetrics.invalidArgumentErrors.Inc(1)
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err.Error())
+	} else if err != nil {
+		h.listMetadataMetrics.internalErrors.Inc(1)
+		return nil, status.Errorf(codes.Internal, "%s", err.Error())
+	}
+	resp := &gen.ListMetadataResponse{NextPageToken: next}
+	for _, m := range res {
+		resp.Metadata = append(resp.Metadata, model.MetadataToProto(m))
+	}
+	h.listMetadataMetrics.successes.Inc(1)
+	return resp, nil
+}
+
+// GetMetadataBatch returns the metadata of several movies. Movies that are
+// not found are skipped.
+func (h *Handler) GetMetadataBatch(ctx context.Context, req *gen.GetMetadataBatchRequest) (*gen.GetMetadataBatchResponse, error) {
+	h.getBatchMetrics.calls.Inc(1)
+	if req == nil {
+		h.getBatchMetrics.invalidArgumentErrors.Inc(1)
+		return nil, status.Errorf(codes.InvalidArgument, "nil req")
+	}
+	res, err := h.ctrl.GetBatch(ctx, req.MovieIds)
+	if err != nil && errors.Is(err, metadata.ErrBatchTooLarge) {
+		h.getBatchMetrics.invalidArgumentErrors.Inc(1)
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err.Error())
+	} else if err != nil {
+		h.getBatchMetrics.internalErrors.Inc(1)
+		return nil, status.Errorf(codes.Internal, "%s", err.Error())
+	}
+	resp := &gen.GetMetadataBatchResponse{}
+	for _, m := range res {
+		resp.Metadata = append(resp.Metadata, model.MetadataToProto(m))
+	}
+	h.getBatchMetrics.successes.Inc(1)
+	return resp, nil
+}
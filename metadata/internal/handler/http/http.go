@@ -1,12 +1,15 @@
 package http
 
 import (
-	"encoding/json"
 	"errors"
 	"log"
 	"net/http"
+	"strconv"
 
+	"github.com/abhishek622/movieapp/gen"
+	"github.com/abhishek622/movieapp/internal/httputil"
 	"github.com/abhishek622/movieapp/metadata/internal/controller/metadata"
+	"github.com/abhishek622/movieapp/metadata/pkg/model"
 )
 
 // Handler defines a movie metadata HTTP handler.
@@ -23,21 +26,93 @@ func New(ctrl *metadata.Controller) *Handler {
 func (h *Handler) GetMetadata(w http.ResponseWriter, req *http.Request) {
 	id := req.FormValue("id")
 	if id == "" {
-		w.WriteHeader(http.StatusBadRequest)
+		httputil.Error(w, req, http.StatusBadRequest, "empty id")
 		return
 	}
 	ctx := req.Context()
 	m, err := h.ctrl.Get(ctx, id)
 	if err != nil && errors.Is(err, metadata.ErrNotFound) {
-		w.WriteHeader(http.StatusNotFound)
+		httputil.Error(w, req, http.StatusNotFound, err.Error())
 		return
 	} else if err != nil {
 		log.Printf("Repository get error: %v\n", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		httputil.Error(w, req, http.StatusInternalServerError, "")
 		return
 	}
-	if err := json.NewEncoder(w).Encode(m); err != nil {
-		log.Printf("Response encode error: %v\n", err)
-		w.WriteHeader(http.StatusInternalServerError)
+	httputil.Respond(w, req, "metadata", m, model.MetadataToProto(m))
+}
+
+// metadataList is a page of metadata returned by ListMetadata.
+type metadataList struct {
+	Metadata      []*model.Metadata `json:"metadata" xml:"metadata"`
+	NextPageToken string            `json:"nextPageToken,omitempty" xml:"nextPageToken,omitempty"`
+}
+
+// ListMetadata handles GET /metadata/list requests. The director and genre
+// parameters filter the metadata, pageSize and pageToken select a page.
+func (h *Handler) ListMetadata(w http.ResponseWriter, req *http.Request) {
+	pageSize := 0
+	if v := req.FormValue("pageSize"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			httputil.Error(w, req, http.StatusBadRequest, "invalid pageSize")
+			return
+		}
+		pageSize = n
+	}
+	filter := model.Filter{Director: req.FormValue("director"), Genre: req.FormValue("genre")}
+	res, next, err := h.ctrl.List(req.Context(), filter, pageSize, req.FormValue("pageToken"))
+	if err != nil && errors.Is(err, metadata.ErrInvalidPageToken) {
+		httputil.Error(w, req, http.StatusBadRequest, err.Error())
+		return
+	} else if err != nil {
+		log.Printf("Repository list error: %v\n", err)
+		httputil.Error(w, req, http.StatusInternalServerError, "")
+		return
+	}
+	if res == nil {
+		res = []*model.Metadata{}
+	}
+	msg := &gen.ListMetadataResponse{NextPageToken: next}
+	for _, m := range res {
+		msg.Metadata = append(msg.Metadata, model.MetadataToProto(m))
+	}
+	httputil.Respond(w, req, "metadataList", metadataList{Metadata: res, NextPageToken: next}, msg)
+}
+
+// metadataBatch is the metadata returned by GetMetadataBatch.
+type metadataBatch struct {
+	Metadata []*model.Metadata `json:"metadata" xml:"metadata"`
+}
+
+// GetMetadataBatch handles GET /metadata/batch requests. It returns the
+// metadata of the movies whose ids are set by repeated id parameters. Movies
+// that are not found are not returned.
+func (h *Handler) GetMetadataBatch(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		httputil.Error(w, req, http.StatusMethodNotAllowed, "")
+		return
+	}
+	if err := req.ParseForm(); err != nil {
+		httputil.Error(w, req, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.ctrl.GetBatch(req.Context(), req.Form["id"])
+	if err != nil && errors.Is(err, metadata.ErrBatchTooLarge) {
+		httputil.Error(w, req, http.StatusBadRequest, err.Error())
+		return
+	} else if err != nil {
+		log.Printf("Repository get error: %v\n", err)
+		httputil.Error(w, req, http.StatusInternalServerError, "")
+		return
+	}
+	if res == nil {
+		res = []*model.Metadata{}
+	}
+	msg := &gen.GetMetadataBatchResponse{}
+	for _, m := range res {
+		msg.Metadata = append(msg.Metadata, model.MetadataToProto(m))
 	}
+	httputil.Respond(w, req, "metadataBatch", metadataBatch{Metadata: res}, msg)
 }
@@ -2,6 +2,8 @@ package memory
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/abhishek622/movieapp/metadata/internal/repository"
@@ -48,3 +50,49 @@ func (r *Repository) Put(ctx context.Context, id string, metadata *model.Metadat
 	r.data[id] = metadata
 	return nil
 }
+
+// GetBatch retrieves the metadata of several movies by movie id, in the order
+// of ids. Movies that are not found are skipped.
+func (r *Repository) GetBatch(ctx context.Context, ids []string) ([]*model.Metadata, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	_, span := otel.Tracer(tracerID).Start(ctx, "Repository/GetBatch")
+	defer span.End()
+
+	var res []*model.Metadata
+	for _, id := range ids {
+		if m, ok := r.data[id]; ok {
+			res = append(res, m)
+		}
+	}
+	return res, nil
+}
+
+// List returns up to limit metadata entries selected by the filter, ordered
+// by title and id and starting after the cursor if set.
+func (r *Repository) List(ctx context.Context, filter model.Filter, after *model.TitleCursor, limit int) ([]*model.Metadata, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	_, span := otel.Tracer(tracerID).Start(ctx, "Repository/List")
+	defer span.End()
+
+	var res []*model.Metadata
+	for _, m := range r.data {
+		if !filter.Match(m) || (after != nil && !after.Before(m)) {
+			continue
+		}
+		res = append(res, m)
+	}
+	slices.SortFunc(res, func(a, b *model.Metadata) int {
+		if c := strings.Compare(a.Title, b.Title); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/abhishek622/movieapp/metadata/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestList(t *testing.T) {
+	ctx := context.Background()
+	r := New()
+	for _, m := range []*model.Metadata{
+		{ID: "3", Title: "b", Director: "Nolan", Genres: []string{"drama"}},
+		{ID: "1", Title: "c", Director: "Nolan", Genres: []string{"Comedy", "drama"}},
+		{ID: "2", Title: "b", Director: "Lynch", Genres: []string{"comedy"}},
+		{ID: "4", Title: "a", Director: "nolan"},
+	} {
+		require.NoError(t, r.Put(ctx, m.ID, m))
+	}
+	ids := func(res []*model.Metadata) []string {
+		var ids []string
+		for _, m := range res {
+			ids = append(ids, m.ID)
+		}
+		return ids
+	}
+	tests := []struct {
+		name   string
+		filter model.Filter
+		after  *model.TitleCursor
+		limit  int
+		want   []string
+	}{
+		{name: "all by title and id", limit: 10, want: []string{"4", "2", "3", "1"}},
+		{name: "limit", limit: 2, want: []string{"4", "2"}},
+		{name: "after cursor", after: &model.TitleCursor{Title: "b", ID: "2"}, limit: 10, want: []string{"3", "1"}},
+		{name: "director", filter: model.Filter{Director: "NOLAN"}, limit: 10, want: []string{"4", "3", "1"}},
+		{name: "genre", filter: model.Filter{Genre: "comedy"}, limit: 10, want: []string{"2", "1"}},
+		{name: "director and genre", filter: model.Filter{Director: "nolan", Genre: "drama"}, after: &model.TitleCursor{Title: "b", ID: "3"}, limit: 10, want: []string{"1"}},
+		{name: "no match", filter: model.Filter{Genre: "horror"}, limit: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := r.List(ctx, tt.filter, tt.after, tt.limit)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ids(res))
+		})
+	}
+}
+
+func TestGetBatch(t *testing.T) {
+	ctx := context.Background()
+	r := New()
+	for _, id := range []string{"1", "2", "3"} {
+		require.NoError(t, r.Put(ctx, id, &model.Metadata{ID: id}))
+	}
+	res, err := r.GetBatch(ctx, []string{"3", "4", "1"})
+	require.NoError(t, err)
+	assert.Equal(t, []*model.Metadata{{ID: "3"}, {ID: "1"}}, res, "in the order of the ids, without the missing ones")
+}
@@ -3,6 +3,7 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"strings"
 
 	"github.com/abhishek622/movieapp/metadata/internal/repository"
 	"github.com/abhishek622/movieapp/metadata/pkg/model"
@@ -25,9 +26,9 @@ func New() (*Repository, error) {
 
 // Get retrieves movie metadata for by movie id.
 func (r *Repository) Get(ctx context.Context, id string) (*model.Metadata, error) {
-	var title, description, director string
-	row := r.db.QueryRowContext(ctx, "SELECT title, description, director FROM movies WHERE id = ?", id)
-	if err := row.Scan(&title, &description, &director); err != nil {
+	var title, description, director, genres string
+	row := r.db.QueryRowContext(ctx, "SELECT title, description, director, genres FROM movies WHERE id = ?", id)
+	if err := row.Scan(&title, &description, &director, &genres); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, repository.ErrNotFound
 		}
@@ -38,12 +39,96 @@ func (r *Repository) Get(ctx context.Context, id string) (*model.Metadata, error
 		Title:       title,
 		Description: description,
 		Director:    director,
+		Genres:      splitGenres(genres),
 	}, nil
 }
 
 // Put adds movie metadata for a given movie id.
 func (r *Repository) Put(ctx context.Context, id string, metadata *model.Metadata) error {
-	_, err := r.db.ExecContext(ctx, "INSERT INTO movies (id, title, description, director) VALUES (?, ?, ?, ?)",
-		id, metadata.Title, metadata.Description, metadata.Director)
+	_, err := r.db.ExecContext(ctx, "INSERT INTO movies (id, title, description, director, genres) VALUES (?, ?, ?, ?, ?)",
+		id, metadata.Title, metadata.Description, metadata.Director, strings.Join(metadata.Genres, ","))
 	return err
 }
+
+// GetBatch retrieves the metadata of several movies by movie id, in the order
+// of ids. Movies that are not found are skipped.
+func (r *Repository) GetBatch(ctx context.Context, ids []string) ([]*model.Metadata, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	query := "SELECT id, title, description, director, genres FROM movies WHERE id IN (?" + strings.Repeat(", ?", len(ids)-1) + ")"
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	byID := map[string]*model.Metadata{}
+	for rows.Next() {
+		var m model.Metadata
+		var genres string
+		if err := rows.Scan(&m.ID, &m.Title, &m.Description, &m.Director, &genres); err != nil {
+			return nil, err
+		}
+		m.Genres = splitGenres(genres)
+		byID[m.ID] = &m
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	var res []*model.Metadata
+	for _, id := range ids {
+		if m, ok := byID[id]; ok {
+			res = append(res, m)
+		}
+	}
+	return res, nil
+}
+
+// List returns up to limit metadata entries selected by the filter, ordered
+// by title and id and starting after the cursor if set.
+func (r *Repository) List(ctx context.Context, filter model.Filter, after *model.TitleCursor, limit int) ([]*model.Metadata, error) {
+	query := "SELECT id, title, description, director, genres FROM movies WHERE 1 = 1"
+	var args []any
+	if filter.Director != "" {
+		query += " AND director = ?"
+		args = append(args, filter.Director)
+	}
+	if filter.Genre != "" {
+		query += " AND FIND_IN_SET(?, genres) > 0"
+		args = append(args, filter.Genre)
+	}
+	if after != nil {
+		query += " AND (title > ? OR (title = ? AND id > ?))"
+		args = append(args, after.Title, after.Title, after.ID)
+	}
+	query += " ORDER BY title, id LIMIT ?"
+	args = append(args, limit)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []*model.Metadata
+	for rows.Next() {
+		var m model.Metadata
+		var genres string
+		if err := rows.Scan(&m.ID, &m.Title, &m.Description, &m.Director, &genres); err != nil {
+			return nil, err
+		}
+		m.Genres = splitGenres(genres)
+		res = append(res, &m)
+	}
+	return res, rows.Err()
+}
+
+// splitGenres splits the comma-separated genres of a movie.
+func splitGenres(genres string) []string {
+	if genres == "" {
+		return nil
+	}
+	return strings.Split(genres, ",")
+}
@@ -9,6 +9,7 @@ func MetadataToProto(m *Metadata) *gen.Metadata {
 		Title:       m.Title,
 		Description: m.Description,
 		Director:    m.Director,
+		Genres:      m.Genres,
 	}
 }
 
@@ -19,5 +20,6 @@ func MetadataFromProto(m *gen.Metadata) *Metadata {
 		Title:       m.Title,
 		Description: m.Description,
 		Director:    m.Director,
+		Genres:      m.Genres,
 	}
 }
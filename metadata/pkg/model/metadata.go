@@ -1,8 +1,45 @@
 package model
 
+import "strings"
+
 type Metadata struct {
-	ID          string `json:"id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Director    string `json:"director"`
+	ID          string   `json:"id" xml:"id"`
+	Title       string   `json:"title" xml:"title"`
+	Description string   `json:"description" xml:"description"`
+	Director    string   `json:"director" xml:"director"`
+	Genres      []string `json:"genres,omitempty" xml:"genres>genre,omitempty"`
+}
+
+// Filter selects metadata by director and genre. Empty fields match any
+// metadata. Values are compared case-insensitively.
+type Filter struct {
+	Director string
+	Genre    string
+}
+
+// Match reports whether metadata is selected by the filter.
+func (f Filter) Match(m *Metadata) bool {
+	if f.Director != "" && !strings.EqualFold(f.Director, m.Director) {
+		return false
+	}
+	if f.Genre == "" {
+		return true
+	}
+	for _, g := range m.Genres {
+		if strings.EqualFold(f.Genre, g) {
+			return true
+		}
+	}
+	return false
+}
+
+// TitleCursor identifies a position in a list of metadata ordered by title and id.
+type TitleCursor struct {
+	Title string `json:"title"`
+	ID    string `json:"id"`
+}
+
+// Before reports whether the cursor position is before metadata m.
+func (c TitleCursor) Before(m *Metadata) bool {
+	return c.Title < m.Title || (c.Title == m.Title && c.ID < m.ID)
 }
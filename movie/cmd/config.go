@@ -1,14 +1,28 @@
 package main
 
+import (
+	"time"
+
+	"github.com/abhishek622/movieapp/internal/circuitbreaker"
+)
+
 type config struct {
 	API              apiConfig              `yaml:"api"`
 	ServiceDiscovery serviceDiscoveryConfig `yaml:"serviceDiscovery"`
+	Auth             authConfig             `yaml:"auth"`
 	Jaeger           jaegerConfig           `yaml:"jaeger"`
 	Prometheus       prometheusConfig       `yaml:"prometheus"`
+	Admin            adminConfig            `yaml:"admin"`
+	Dependencies     dependenciesConfig     `yaml:"dependencies"`
+	Cache            cacheConfig            `yaml:"cache"`
+	GraphQL          graphqlConfig          `yaml:"graphql"`
+	GRPCClient       grpcClientConfig       `yaml:"grpcClient"`
 }
 
 type apiConfig struct {
-	Port int `yaml:"port"`
+	// Port and HTTPPort are the ports of the gRPC and HTTP APIs.
+	Port     int `yaml:"port"`
+	HTTPPort int `yaml:"httpPort"`
 }
 
 type serviceDiscoveryConfig struct {
@@ -19,6 +33,12 @@ type consulConfig struct {
 	Address string `yaml:"address"`
 }
 
+type authConfig struct {
+	// Address is the address of the auth service, which is not registered in
+	// service discovery.
+	Address string `yaml:"address"`
+}
+
 type jaegerConfig struct {
 	Host string `yaml:"host"`
 	Port string `yaml:"port"`
@@ -27,3 +47,91 @@ type jaegerConfig struct {
 type prometheusConfig struct {
 	MetricsPort int `yaml:"metricsPort"`
 }
+
+type adminConfig struct {
+	// Address is the address of the admin endpoints, which are not served if
+	// it is unset. It should not be reachable by the clients of the service.
+	Address string `yaml:"address"`
+}
+
+type dependenciesConfig struct {
+	Metadata dependencyConfig `yaml:"metadata"`
+	Rating   dependencyConfig `yaml:"rating"`
+	Auth     dependencyConfig `yaml:"auth"`
+}
+
+type dependencyConfig struct {
+	// Transport is the protocol of the calls to the dependency, http or grpc.
+	// It defaults to grpc and is ignored for the auth service, which only
+	// serves gRPC.
+	Transport string `yaml:"transport"`
+	// Timeout limits every call to the dependency. Calls have no timeout if it is unset.
+	Timeout time.Duration `yaml:"timeout"`
+	// CircuitBreaker configures the circuit breaker of the metadata and
+	// rating services.
+	CircuitBreaker circuitBreakerConfig `yaml:"circuitBreaker"`
+}
+
+type circuitBreakerConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Window is the time over which the failure and slow call rates are computed.
+	Window time.Duration `yaml:"window"`
+	// MinRequests is the number of calls in the window below which the breaker does not open.
+	MinRequests int `yaml:"minRequests"`
+	// FailureRate and SlowCallRate are the fractions of failed calls and of
+	// calls slower than SlowCallDuration that open the breaker.
+	FailureRate      float64       `yaml:"failureRate"`
+	SlowCallDuration time.Duration `yaml:"slowCallDuration"`
+	SlowCallRate     float64       `yaml:"slowCallRate"`
+	// OpenTimeout is the time an open breaker rejects calls for before
+	// HalfOpenRequests probe calls are allowed.
+	OpenTimeout      time.Duration `yaml:"openTimeout"`
+	HalfOpenRequests int           `yaml:"halfOpenRequests"`
+}
+
+// options returns the circuit breaker options, or nil if the breaker is disabled.
+func (c circuitBreakerConfig) options() *circuitbreaker.Options {
+	if !c.Enabled {
+		return nil
+	}
+	return &circuitbreaker.Options{
+		Window:           c.Window,
+		MinRequests:      c.MinRequests,
+		FailureRate:      c.FailureRate,
+		SlowCallDuration: c.SlowCallDuration,
+		SlowCallRate:     c.SlowCallRate,
+		OpenTimeout:      c.OpenTimeout,
+		HalfOpenRequests: c.HalfOpenRequests,
+	}
+}
+
+type cacheConfig struct {
+	// TTL is the time movie details are cached for. Caching is disabled if it is unset.
+	TTL time.Duration `yaml:"ttl"`
+	// MaxStale is the time expired movie details are still served for while
+	// they are refreshed, or while a dependency is unavailable.
+	MaxStale time.Duration `yaml:"maxStale"`
+	// Size is the maximum number of cached movie details.
+	Size int `yaml:"size"`
+}
+
+type graphqlConfig struct {
+	// MaxDepth and MaxComplexity reject the GraphQL queries that are nested
+	// deeper or cost more. Queries are not limited if they are unset.
+	MaxDepth      int `yaml:"maxDepth"`
+	MaxComplexity int `yaml:"maxComplexity"`
+}
+
+type grpcClientConfig struct {
+	// ResolveInterval is the interval at which the addresses of the gRPC
+	// dependencies are refreshed from service discovery.
+	ResolveInterval time.Duration `yaml:"resolveInterval"`
+	// KeepaliveTime and KeepaliveTimeout configure the pings of idle gRPC
+	// connections. The keepalive time cannot be shorter than 10s.
+	KeepaliveTime    time.Duration `yaml:"keepaliveTime"`
+	KeepaliveTimeout time.Duration `yaml:"keepaliveTimeout"`
+	// Authority is the server name that the certificates of the gRPC
+	// dependencies are verified against. It defaults to the name of the
+	// dependency, which the certificates of san.cnf do not cover.
+	Authority string `yaml:"authority"`
+}
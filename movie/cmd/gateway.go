@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/abhishek622/movieapp/internal/grpcutil"
+	"github.com/abhishek622/movieapp/movie/internal/controller/movie"
+	metadatagrpc "github.com/abhishek622/movieapp/movie/internal/gateway/metadata/grpc"
+	metadatahttp "github.com/abhishek622/movieapp/movie/internal/gateway/metadata/http"
+	ratinggrpc "github.com/abhishek622/movieapp/movie/internal/gateway/rating/grpc"
+	ratinghttp "github.com/abhishek622/movieapp/movie/internal/gateway/rating/http"
+	"github.com/abhishek622/movieapp/pkg/discovery"
+)
+
+const (
+	transportHTTP = "http"
+	transportGRPC = "grpc"
+)
+
+// newMetadataGateway creates the metadata gateway of a transport. gRPC is
+// used if the transport is unset.
+func newMetadataGateway(transport string, registry discovery.Registry, pool *grpcutil.Pool) (movie.MetadataGateway, error) {
+	switch transport {
+	case transportHTTP:
+		return metadatahttp.New(registry), nil
+	case "", transportGRPC:
+		return metadatagrpc.New(pool), nil
+	}
+	return nil, fmt.Errorf("unknown metadata transport %q", transport)
+}
+
+// newRatingGateway creates the rating gateway of a transport. gRPC is used if
+// the transport is unset.
+func newRatingGateway(transport string, registry discovery.Registry, pool *grpcutil.Pool) (movie.RatingGateway, error) {
+	switch transport {
+	case transportHTTP:
+		return ratinghttp.New(registry), nil
+	case "", transportGRPC:
+		return ratinggrpc.New(pool), nil
+	}
+	return nil, fmt.Errorf("unknown rating transport %q", transport)
+}
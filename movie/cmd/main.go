@@ -14,10 +14,12 @@ import (
 	"time"
 
 	"github.com/abhishek622/movieapp/gen"
+	"github.com/abhishek622/movieapp/internal/grpcutil"
 	"github.com/abhishek622/movieapp/movie/internal/controller/movie"
-	metadatagateway "github.com/abhishek622/movieapp/movie/internal/gateway/metadata/http"
-	ratinggateway "github.com/abhishek622/movieapp/movie/internal/gateway/rating/http"
+	authgateway "github.com/abhishek622/movieapp/movie/internal/gateway/auth/grpc"
+	graphqlhandler "github.com/abhishek622/movieapp/movie/internal/handler/graphql"
 	grpchandler "github.com/abhishek622/movieapp/movie/internal/handler/grpc"
+	httphandler "github.com/abhishek622/movieapp/movie/internal/handler/http"
 	"github.com/abhishek622/movieapp/pkg/discovery"
 	"github.com/abhishek622/movieapp/pkg/discovery/consul"
 	"github.com/abhishek622/movieapp/pkg/tracing"
@@ -114,15 +116,18 @@ func main() {
 
 	// --- Service registration ---
 	instanceID := discovery.GenerateInstanceID(serviceName)
-	if err := registry.Register(ctx, instanceID, serviceName, fmt.Sprintf("localhost:%d", port)); err != nil {
+	endpoints := []discovery.Endpoint{
+		{Name: discovery.EndpointGRPC, Protocol: "grpc", HostPort: fmt.Sprintf("localhost:%d", port)},
+		{Name: discovery.EndpointHTTP, Protocol: "http", HostPort: fmt.Sprintf("localhost:%d", cfg.API.HTTPPort)},
+	}
+	if cfg.Prometheus.MetricsPort != 0 {
+		endpoints = append(endpoints, discovery.Endpoint{Name: discovery.EndpointMetrics, Protocol: "http", HostPort: fmt.Sprintf("localhost:%d", cfg.Prometheus.MetricsPort)})
+	}
+	if err := registry.Register(ctx, instanceID, serviceName, endpoints); err != nil {
 		logger.Fatal("Failed to register service", zap.Error(err))
 	}
 	defer registry.Deregister(ctx, instanceID, serviceName)
 
-	metadataGateway := metadatagateway.New(registry)
-	ratingGateway := ratinggateway.New(registry)
-	ctrl := movie.New(ratingGateway, metadataGateway)
-	h := grpchandler.New(ctrl)
 	serverCert, err := tls.LoadX509KeyPair("configs/movie-cert.pem", "configs/movie-key.pem")
 	if err != nil {
 		logger.Fatal("Failed to load server certificate and key", zap.Error(err))
@@ -141,6 +146,77 @@ func main() {
 		ClientAuth:   tls.RequireAndVerifyClientCert,
 		MinVersion:   tls.VersionTLS13,
 	}
+	// Connections to the gRPC dependencies are shared by their gateways and
+	// authenticated with the service certificate.
+	pool := grpcutil.NewPool(registry, credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		RootCAs:      certPool,
+		MinVersion:   tls.VersionTLS13,
+	}), grpcutil.PoolOptions{
+		ResolveInterval:  cfg.GRPCClient.ResolveInterval,
+		KeepaliveTime:    cfg.GRPCClient.KeepaliveTime,
+		KeepaliveTimeout: cfg.GRPCClient.KeepaliveTimeout,
+		Authority:        cfg.GRPCClient.Authority,
+	})
+	defer pool.Close()
+	metadataGateway, err := newMetadataGateway(cfg.Dependencies.Metadata.Transport, registry, pool)
+	if err != nil {
+		logger.Fatal("Failed to create metadata gateway", zap.Error(err))
+	}
+	ratingGateway, err := newRatingGateway(cfg.Dependencies.Rating.Transport, registry, pool)
+	if err != nil {
+		logger.Fatal("Failed to create rating gateway", zap.Error(err))
+	}
+	authGateway := authgateway.New(cfg.Auth.Address, credentials.NewTLS(&tls.Config{RootCAs: certPool, MinVersion: tls.VersionTLS13}))
+	ctrl := movie.New(ratingGateway, metadataGateway, authGateway, movie.Options{
+		MetadataTimeout:        cfg.Dependencies.Metadata.Timeout,
+		RatingTimeout:          cfg.Dependencies.Rating.Timeout,
+		AuthTimeout:            cfg.Dependencies.Auth.Timeout,
+		MetadataCircuitBreaker: cfg.Dependencies.Metadata.CircuitBreaker.options(),
+		RatingCircuitBreaker:   cfg.Dependencies.Rating.CircuitBreaker.options(),
+		CacheTTL:               cfg.Cache.TTL,
+		CacheMaxStale:          cfg.Cache.MaxStale,
+		CacheSize:              cfg.Cache.Size,
+		Scope:                  scope,
+	})
+	h := grpchandler.New(ctrl)
+	httpHandler := httphandler.New(ctrl)
+	// The circuit breakers are inspected on the admin address, which only
+	// listens on localhost unless configured otherwise.
+	if cfg.Admin.Address != "" {
+		adminMux := http.NewServeMux()
+		adminMux.HandleFunc("/admin/circuitbreakers", httpHandler.CircuitBreakers)
+		go func() {
+			logger.Info("Starting admin server", zap.String("addr", cfg.Admin.Address))
+			if err := http.ListenAndServe(cfg.Admin.Address, adminMux); err != nil {
+				logger.Error("Admin server error", zap.Error(err))
+			}
+		}()
+	}
+	graphqlHandler := graphqlhandler.New(ctrl, graphqlhandler.Limits{
+		MaxDepth:      cfg.GraphQL.MaxDepth,
+		MaxComplexity: cfg.GraphQL.MaxComplexity,
+	})
+	// Start HTTP server
+	go func() {
+		httpMux := http.NewServeMux()
+		httpMux.HandleFunc("/movie", httpHandler.GetMovieDetails)
+		httpMux.HandleFunc("/movie/rating", httpHandler.RateMovie)
+		httpMux.HandleFunc("/movies", httpHandler.ListMovies)
+		httpMux.HandleFunc("/movies/top", httpHandler.GetTopRatedMovies)
+		httpMux.HandleFunc("/rating", httpHandler.GetRecordRating)
+		httpMux.HandleFunc("/graphql", graphqlHandler.Query)
+		httpMux.HandleFunc("/graphql/schema", graphqlHandler.Schema)
+		httpServer := &http.Server{
+			Addr:    fmt.Sprintf("localhost:%d", cfg.API.HTTPPort),
+			Handler: httpMux,
+		}
+		logger.Info("Starting HTTP server", zap.String("addr", httpServer.Addr))
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Error("HTTP server error", zap.Error(err))
+		}
+	}()
+
 	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", port))
 	if err != nil {
 		logger.Fatal("Failed to listen", zap.Error(err))
@@ -155,6 +231,7 @@ func main() {
 		grpc.Creds(credentials.NewTLS(tlsConfig)),
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 		grpc.UnaryInterceptor(ratelimit.UnaryServerInterceptor(l)),
+		grpcutil.KeepaliveEnforcementPolicy(),
 	)
 
 	reflection.Register(srv)
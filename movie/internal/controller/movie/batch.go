@@ -0,0 +1,68 @@
+package movie
+
+import (
+	"context"
+	"slices"
+
+	metadatamodel "github.com/abhishek622/movieapp/metadata/pkg/model"
+	ratingmodel "github.com/abhishek622/movieapp/rating/pkg/model"
+	"go.opentelemetry.io/otel"
+)
+
+// metadataBatchSize is the number of movies whose metadata is fetched with a
+// single call to the metadata service.
+const metadataBatchSize = 100
+
+// GetRatingBatch returns the aggregated ratings of movies by movie id,
+// fetched in batches of ratingBatchSize movies. Movies without ratings are
+// left out of the result.
+func (c *Controller) GetRatingBatch(ctx context.Context, ids []string) (map[string]ratingmodel.RatedRecord, error) {
+	ctx, span := otel.Tracer(tracerID).Start(ctx, "Controller/GetRatingBatch")
+	defer span.End()
+
+	recordIDs := make([]ratingmodel.RecordID, len(ids))
+	for i, id := range ids {
+		recordIDs[i] = ratingmodel.RecordID(id)
+	}
+	res := make(map[string]ratingmodel.RatedRecord, len(ids))
+	for batch := range slices.Chunk(recordIDs, ratingBatchSize) {
+		var records []ratingmodel.RatedRecord
+		err := c.rating.call(ctx, "GetAggregatedRatings", func(ctx context.Context) (err error) {
+			records, err = c.ratingGateway.GetAggregatedRatings(ctx, batch, ratingmodel.RecordTypeMovie)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			if r.VoteCount > 0 {
+				res[string(r.RecordID)] = r
+			}
+		}
+	}
+	return res, nil
+}
+
+// GetMetadataBatch returns the metadata of movies by movie id, fetched in
+// batches of metadataBatchSize movies. Movies without metadata are left out
+// of the result.
+func (c *Controller) GetMetadataBatch(ctx context.Context, ids []string) (map[string]*metadatamodel.Metadata, error) {
+	ctx, span := otel.Tracer(tracerID).Start(ctx, "Controller/GetMetadataBatch")
+	defer span.End()
+
+	res := make(map[string]*metadatamodel.Metadata, len(ids))
+	for batch := range slices.Chunk(ids, metadataBatchSize) {
+		var metadata []*metadatamodel.Metadata
+		err := c.metadata.call(ctx, "GetBatch", func(ctx context.Context) (err error) {
+			metadata, err = c.metadataGateway.GetBatch(ctx, batch)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range metadata {
+			res[m.ID] = m
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,272 @@
+package movie
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/abhishek622/movieapp/movie/pkg/model"
+	"github.com/uber-go/tally/v4"
+)
+
+// DefaultCacheSize is the number of cached movie details when no cache size is set.
+const DefaultCacheSize = 10000
+
+type cacheMetrics struct {
+	hits         tally.Counter
+	staleHits    tally.Counter
+	misses       tally.Counter
+	staleOnError tally.Counter
+	refreshes    tally.Counter
+	refreshFails tally.Counter
+	collapsed    tally.Counter
+	evictions    tally.Counter
+	size         tally.Gauge
+}
+
+func newCacheMetrics(scope tally.Scope) *cacheMetrics {
+	scope = scope.Tagged(map[string]string{"component": "cache"})
+	return &cacheMetrics{
+		hits:         scope.Counter("hit"),
+		staleHits:    scope.Counter("stale_hit"),
+		misses:       scope.Counter("miss"),
+		staleOnError: scope.Counter("stale_on_error"),
+		refreshes:    scope.Counter("refresh"),
+		refreshFails: scope.Counter("refresh_error"),
+		collapsed:    scope.Counter("collapsed"),
+		evictions:    scope.Counter("eviction"),
+		size:         scope.Gauge("size"),
+	}
+}
+
+// detailsCache caches movie details with stale-while-revalidate semantics.
+// Entries are fresh for ttl. Stale entries are served for another maxStale
+// while they are refreshed in the background, and also when a dependency is
+// unavailable. Concurrent loads of the same movie are collapsed into one.
+type detailsCache struct {
+	ttl      time.Duration
+	maxStale time.Duration
+	size     int
+	load     func(ctx context.Context, id string) (*model.MovieDetails, error)
+	metrics  *cacheMetrics
+	now      func() time.Time
+
+	mu       sync.Mutex
+	entries  map[string]*list.Element
+	lru      *list.List
+	inflight map[string]*cacheCall
+}
+
+type cacheEntry struct {
+	id        string
+	details   *model.MovieDetails
+	fetchedAt time.Time
+	// refreshFailed tells that the last refresh of the entry failed.
+	refreshFailed bool
+}
+
+// cacheCall is a load of movie details shared by concurrent callers.
+type cacheCall struct {
+	done    chan struct{}
+	details *model.MovieDetails
+	err     error
+}
+
+func newDetailsCache(ttl time.Duration, maxStale time.Duration, size int, scope tally.Scope, load func(ctx context.Context, id string) (*model.MovieDetails, error)) *detailsCache {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+	return &detailsCache{
+		ttl:      ttl,
+		maxStale: maxStale,
+		size:     size,
+		load:     load,
+		metrics:  newCacheMetrics(scope),
+		now:      time.Now,
+		entries:  map[string]*list.Element{},
+		lru:      list.New(),
+		inflight: map[string]*cacheCall{},
+	}
+}
+
+// get returns the cached details of a movie, or loads them on a miss.
+func (c *detailsCache) get(ctx context.Context, id string) (*model.MovieDetails, error) {
+	now := c.now()
+	c.mu.Lock()
+	e := c.lookup(id)
+	switch {
+	case e != nil && now.Sub(e.fetchedAt) < c.ttl && !e.refreshFailed && !degraded(e.details):
+		details := copyDetails(e.details)
+		c.mu.Unlock()
+		c.metrics.hits.Inc(1)
+		return details, nil
+	case e != nil && now.Sub(e.fetchedAt) < c.ttl+c.maxStale:
+		details := copyDetails(e.details)
+		if e.refreshFailed {
+			details = markStale(e.details)
+		}
+		call, started := c.start(id)
+		c.mu.Unlock()
+		c.metrics.staleHits.Inc(1)
+		if started {
+			c.metrics.refreshes.Inc(1)
+			// The refresh outlives the request that triggered it.
+			go c.run(context.WithoutCancel(ctx), id, call)
+		}
+		c.countStale(details)
+		return details, nil
+	}
+	call, started := c.start(id)
+	c.mu.Unlock()
+	c.metrics.misses.Inc(1)
+	if started {
+		// Callers waiting for the same load do not fail if this one gives up.
+		go c.run(context.WithoutCancel(ctx), id, call)
+	} else {
+		c.metrics.collapsed.Inc(1)
+	}
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if call.err != nil {
+		return nil, call.err
+	}
+	c.countStale(call.details)
+	return copyDetails(call.details), nil
+}
+
+// start returns the running load of a movie, or registers a new one and
+// reports that the caller has to run it. c.mu must be held.
+func (c *detailsCache) start(id string) (*cacheCall, bool) {
+	if call, ok := c.inflight[id]; ok {
+		return call, false
+	}
+	call := &cacheCall{done: make(chan struct{})}
+	c.inflight[id] = call
+	return call, true
+}
+
+// run loads the details of a movie and stores them. If the load fails, the
+// cached details are kept and served as stale. If only the rating cannot be
+// loaded, the cached rating is kept and marked stale.
+func (c *detailsCache) run(ctx context.Context, id string, call *cacheCall) {
+	details, err := c.load(ctx, id)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// A load started before the movie was invalidated may be outdated, so it
+	// is returned to its callers but not stored.
+	invalidated := c.inflight[id] != call
+	if !invalidated {
+		delete(c.inflight, id)
+	}
+	defer close(call.done)
+	e := c.lookup(id)
+	if e != nil && c.now().Sub(e.fetchedAt) >= c.ttl+c.maxStale {
+		e = nil
+	}
+	switch {
+	case errors.Is(err, ErrNotFound):
+		c.remove(id)
+		call.err = err
+	case err != nil && e != nil:
+		c.metrics.refreshFails.Inc(1)
+		e.refreshFailed = true
+		call.details = markStale(e.details)
+	case err != nil:
+		c.metrics.refreshFails.Inc(1)
+		call.err = err
+	case invalidated:
+		call.details = details
+	case details.Status.Rating == model.SectionStatusUnavailable && e != nil && e.details.Rating != nil:
+		c.metrics.refreshFails.Inc(1)
+		details.Rating = e.details.Rating
+		details.Status.Rating = model.SectionStatusStale
+		// The entry keeps its age so that the rating is not served for longer than maxStale.
+		e.details = details
+		e.refreshFailed = false
+		call.details = details
+	default:
+		call.details = details
+		c.put(id, details)
+	}
+}
+
+// invalidate drops the cached details of a movie, so that they are loaded
+// again on the next get.
+func (c *detailsCache) invalidate(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remove(id)
+	delete(c.inflight, id)
+}
+
+// countStale counts the responses that contain stale sections.
+func (c *detailsCache) countStale(d *model.MovieDetails) {
+	if d.Status.Metadata == model.SectionStatusStale || d.Status.Rating == model.SectionStatusStale {
+		c.metrics.staleOnError.Inc(1)
+	}
+}
+
+// lookup returns the entry of a movie and marks it as recently used. c.mu must be held.
+func (c *detailsCache) lookup(id string) *cacheEntry {
+	el, ok := c.entries[id]
+	if !ok {
+		return nil
+	}
+	c.lru.MoveToFront(el)
+	return el.Value.(*cacheEntry)
+}
+
+// put stores the details of a movie and evicts the least recently used
+// entries beyond the cache size. c.mu must be held.
+func (c *detailsCache) put(id string, details *model.MovieDetails) {
+	if el, ok := c.entries[id]; ok {
+		el.Value = &cacheEntry{id: id, details: details, fetchedAt: c.now()}
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[id] = c.lru.PushFront(&cacheEntry{id: id, details: details, fetchedAt: c.now()})
+	for c.lru.Len() > c.size {
+		c.remove(c.lru.Back().Value.(*cacheEntry).id)
+		c.metrics.evictions.Inc(1)
+	}
+	c.metrics.size.Update(float64(c.lru.Len()))
+}
+
+// remove drops the entry of a movie. c.mu must be held.
+func (c *detailsCache) remove(id string) {
+	if el, ok := c.entries[id]; ok {
+		c.lru.Remove(el)
+		delete(c.entries, id)
+		c.metrics.size.Update(float64(c.lru.Len()))
+	}
+}
+
+// degraded reports whether the rating of the details could not be fetched.
+func degraded(d *model.MovieDetails) bool {
+	return d.Status.Rating == model.SectionStatusUnavailable || d.Status.Rating == model.SectionStatusStale
+}
+
+func copyDetails(d *model.MovieDetails) *model.MovieDetails {
+	res := *d
+	if d.Rating != nil {
+		rating := *d.Rating
+		res.Rating = &rating
+	}
+	return &res
+}
+
+// markStale returns a copy of cached details with the fetched sections marked stale.
+func markStale(d *model.MovieDetails) *model.MovieDetails {
+	res := copyDetails(d)
+	res.Status.Metadata = model.SectionStatusStale
+	if res.Status.Rating == model.SectionStatusOK {
+		res.Status.Rating = model.SectionStatusStale
+	}
+	return res
+}
@@ -3,51 +3,234 @@ package movie
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	metadatamodel "github.com/abhishek622/movieapp/metadata/pkg/model"
 	"github.com/abhishek622/movieapp/movie/internal/gateway"
 	"github.com/abhishek622/movieapp/movie/pkg/model"
 	ratingmodel "github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/abhishek622/movieapp/rating/pkg/recordtype"
+	"github.com/uber-go/tally/v4"
+	"go.opentelemetry.io/otel"
 )
 
-// ErrNotFound is returned when the movie metadata is not found.
-var ErrNotFound = errors.New("movie metadata not found")
+var (
+	// ErrNotFound is returned when the movie metadata is not found.
+	ErrNotFound = errors.New("movie metadata not found")
+	// ErrInvalidRecord is returned when a record type is not registered or a
+	// record id is not valid for its type.
+	ErrInvalidRecord = errors.New("invalid record")
+	// ErrUnavailable is returned when a dependency is not called because its
+	// circuit breaker is open.
+	ErrUnavailable = errors.New("dependency unavailable")
+)
 
-type ratingGateway interface {
+// RatingGateway, MetadataGateway and AuthGateway call the rating, metadata
+// and auth services.
+type RatingGateway interface {
 	GetAggregatedRating(ctx context.Context, recordID ratingmodel.RecordID, recordType ratingmodel.RecordType) (float64, error)
+	GetRolledUpRating(ctx context.Context, recordID ratingmodel.RecordID, recordType ratingmodel.RecordType) (float64, error)
+	GetTopRated(ctx context.Context, recordType ratingmodel.RecordType, limit int, minVoteCount int) ([]ratingmodel.RatedRecord, error)
+	GetAggregatedRatings(ctx context.Context, recordIDs []ratingmodel.RecordID, recordType ratingmodel.RecordType) ([]ratingmodel.RatedRecord, error)
+	PutRating(ctx context.Context, recordID ratingmodel.RecordID, recordType ratingmodel.RecordType, rating *ratingmodel.Rating) error
 }
 
-type metadataGateway interface {
+type MetadataGateway interface {
 	Get(ctx context.Context, id string) (*metadatamodel.Metadata, error)
+	GetBatch(ctx context.Context, ids []string) ([]*metadatamodel.Metadata, error)
+	List(ctx context.Context, filter metadatamodel.Filter, pageSize int, pageToken string) ([]*metadatamodel.Metadata, string, error)
+}
+
+type AuthGateway interface {
+	ValidateToken(ctx context.Context, token string) (string, error)
 }
 
+const (
+	// defaultTopRatedLimit and maxTopRatedLimit are the default and maximum
+	// number of movies returned by GetTopRated, as in the rating service.
+	defaultTopRatedLimit = 10
+	maxTopRatedLimit     = 100
+)
+
 // Controller defines a movie service controller.
 type Controller struct {
-	ratingGateway   ratingGateway
-	metadataGateway metadataGateway
+	ratingGateway   RatingGateway
+	metadataGateway MetadataGateway
+	authGateway     AuthGateway
+	rating          *dependency
+	metadata        *dependency
+	auth            *dependency
+	// cache caches movie details, or is nil if caching is disabled.
+	cache *detailsCache
+	// rankings keeps the rankings of movie lists sorted by rating or vote count.
+	rankings *rankingCache
 }
 
 // New creates a new movie service controller.
-func New(ratingGateway ratingGateway, metadataGateway metadataGateway) *Controller {
-	return &Controller{ratingGateway, metadataGateway}
+func New(ratingGateway RatingGateway, metadataGateway MetadataGateway, authGateway AuthGateway, opts Options) *Controller {
+	if opts.Scope == nil {
+		opts.Scope = tally.NoopScope
+	}
+	c := &Controller{
+		ratingGateway:   ratingGateway,
+		metadataGateway: metadataGateway,
+		authGateway:     authGateway,
+		rating:          newDependency(opts.Scope, "rating", opts.RatingTimeout, opts.RatingCircuitBreaker),
+		metadata:        newDependency(opts.Scope, "metadata", opts.MetadataTimeout, opts.MetadataCircuitBreaker),
+		auth:            newDependency(opts.Scope, "auth", opts.AuthTimeout, nil),
+		rankings:        newRankingCache(),
+	}
+	if opts.CacheTTL > 0 {
+		c.cache = newDetailsCache(opts.CacheTTL, opts.CacheMaxStale, opts.CacheSize, opts.Scope, c.get)
+	}
+	return c
 }
 
-// Get returns the movie details including the aggregated rating and movie metadata.
+// Get returns the movie details including the aggregated rating and movie
+// metadata. Metadata and rating are fetched concurrently, and the rating call
+// is canceled if the movie metadata is not found. Details are returned without
+// rating and with an unavailable rating status if the rating cannot be fetched.
+// Details are served from the cache if it is enabled.
 func (c *Controller) Get(ctx context.Context, id string) (*model.MovieDetails, error) {
-	metadata, err := c.metadataGateway.Get(ctx, id)
+	if c.cache != nil {
+		return c.cache.get(ctx, id)
+	}
+	return c.get(ctx, id)
+}
+
+func (c *Controller) get(ctx context.Context, id string) (*model.MovieDetails, error) {
+	ctx, span := otel.Tracer(tracerID).Start(ctx, "Controller/Get")
+	defer span.End()
+
+	type ratingResult struct {
+		rating float64
+		err    error
+	}
+	ratingCtx, cancelRating := context.WithCancel(ctx)
+	defer cancelRating()
+	ratingCh := make(chan ratingResult, 1)
+	go func() {
+		rating, err := c.getAggregatedRating(ratingCtx, ratingmodel.RecordID(id), ratingmodel.RecordTypeMovie)
+		ratingCh <- ratingResult{rating, err}
+	}()
+
+	metadata, err := c.getMetadata(ctx, id)
 	if err != nil && errors.Is(err, gateway.ErrNotFound) {
 		return nil, ErrNotFound
 	} else if err != nil {
 		return nil, err
 	}
-	details := &model.MovieDetails{Metadata: *metadata}
-	rating, err := c.ratingGateway.GetAggregatedRating(ctx, ratingmodel.RecordID(id), ratingmodel.RecordTypeMovie)
-	if err != nil && !errors.Is(err, gateway.ErrNotFound) {
-		// Just proceed in this case, it's ok not to have ratings yet.
-	} else if err != nil {
-		return nil, err
+	details := &model.MovieDetails{Metadata: *metadata, Status: model.DetailsStatus{Metadata: model.SectionStatusOK}}
+	r := <-ratingCh
+	if r.err != nil && errors.Is(r.err, gateway.ErrNotFound) {
+		// It's ok not to have ratings yet.
+		details.Status.Rating = model.SectionStatusNotFound
+	} else if r.err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	} else if r.err != nil {
+		details.Status.Rating = model.SectionStatusUnavailable
 	} else {
-		details.Rating = &rating
+		details.Rating = &r.rating
+		details.Status.Rating = model.SectionStatusOK
 	}
 	return details, nil
 }
+
+// GetRating returns the aggregated rating of a record of any registered type,
+// such as an episode or a series. Records with child records also get the
+// rolled up rating of their children, so a series gets the rating of its
+// episodes. Ratings that do not exist yet are left unset.
+func (c *Controller) GetRating(ctx context.Context, recordID ratingmodel.RecordID, recordType ratingmodel.RecordType) (*model.RecordRating, error) {
+	if err := recordtype.Validate(recordType, recordID); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecord, err)
+	}
+	res := &model.RecordRating{RecordID: recordID, RecordType: recordType}
+	rating, err := c.getAggregatedRating(ctx, recordID, recordType)
+	if err != nil && !errors.Is(err, gateway.ErrNotFound) {
+		return nil, err
+	} else if err == nil {
+		res.Rating = &rating
+	}
+	if len(recordtype.Children(recordType)) == 0 {
+		return res, nil
+	}
+	var rolledUp float64
+	err = c.rating.call(ctx, "GetRolledUpRating", func(ctx context.Context) (err error) {
+		rolledUp, err = c.ratingGateway.GetRolledUpRating(ctx, recordID, recordType)
+		return err
+	})
+	if err != nil && !errors.Is(err, gateway.ErrNotFound) {
+		return nil, err
+	} else if err == nil {
+		res.RolledUpRating = &rolledUp
+	}
+	return res, nil
+}
+
+// GetTopRated returns the highest rated movies together with their metadata.
+// Movies without metadata are left out of the result, and replaced by the
+// next rated movies so that the result has up to limit movies.
+func (c *Controller) GetTopRated(ctx context.Context, limit int, minVoteCount int) ([]model.RankedMovie, error) {
+	if limit <= 0 {
+		limit = defaultTopRatedLimit
+	}
+	limit = min(limit, maxTopRatedLimit)
+	metadata := map[string]*metadatamodel.Metadata{}
+	checked := map[string]bool{}
+	// Twice the limit is fetched first to leave room for movies without metadata.
+	fetch := min(2*limit, maxTopRatedLimit)
+	for {
+		var records []ratingmodel.RatedRecord
+		err := c.rating.call(ctx, "GetTopRated", func(ctx context.Context) (err error) {
+			records, err = c.ratingGateway.GetTopRated(ctx, ratingmodel.RecordTypeMovie, fetch, minVoteCount)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		var ids []string
+		for _, r := range records {
+			if !checked[string(r.RecordID)] {
+				ids = append(ids, string(r.RecordID))
+			}
+		}
+		found, err := c.GetMetadataBatch(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			checked[id] = true
+			if m, ok := found[id]; ok {
+				metadata[id] = m
+			}
+		}
+		res := make([]model.RankedMovie, 0, limit)
+		for _, r := range records {
+			if m, ok := metadata[string(r.RecordID)]; ok && len(res) < limit {
+				res = append(res, model.RankedMovie{Rating: r.Rating, VoteCount: r.VoteCount, Metadata: *m})
+			}
+		}
+		if len(res) == limit || len(records) < fetch || fetch == maxTopRatedLimit {
+			return res, nil
+		}
+		fetch = min(2*fetch, maxTopRatedLimit)
+	}
+}
+
+func (c *Controller) getMetadata(ctx context.Context, id string) (*metadatamodel.Metadata, error) {
+	var metadata *metadatamodel.Metadata
+	err := c.metadata.call(ctx, "Get", func(ctx context.Context) (err error) {
+		metadata, err = c.metadataGateway.Get(ctx, id)
+		return err
+	})
+	return metadata, err
+}
+
+func (c *Controller) getAggregatedRating(ctx context.Context, recordID ratingmodel.RecordID, recordType ratingmodel.RecordType) (float64, error) {
+	var rating float64
+	err := c.rating.call(ctx, "GetAggregatedRating", func(ctx context.Context) (err error) {
+		rating, err = c.ratingGateway.GetAggregatedRating(ctx, recordID, recordType)
+		return err
+	})
+	return rating, err
+}
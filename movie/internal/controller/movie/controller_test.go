@@ -0,0 +1,521 @@
+package movie
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abhishek622/movieapp/internal/circuitbreaker"
+	metadatamodel "github.com/abhishek622/movieapp/metadata/pkg/model"
+	"github.com/abhishek622/movieapp/movie/internal/gateway"
+	"github.com/abhishek622/movieapp/movie/pkg/model"
+	ratingmodel "github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally/v4"
+)
+
+// block signals the start of a call on started and waits for release, if
+// they are set, before waiting for delay.
+func block(ctx context.Context, delay time.Duration, started chan<- struct{}, release <-chan struct{}) error {
+	if started != nil {
+		started <- struct{}{}
+	}
+	if release != nil {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-release:
+		}
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+type fakeRatingGateway struct {
+	delay  time.Duration
+	rating float64
+	err    error
+	// started receives a value when a call starts, which then waits for
+	// release to be closed, if they are set.
+	started chan<- struct{}
+	release <-chan struct{}
+	// canceled receives the error of calls canceled before the delay.
+	canceled chan error
+	// fail makes calls fail with errUnavailable.
+	fail atomic.Bool
+	// records are returned by batch calls, which are counted by batches, and
+	// in order by top rated calls, whose limits are recorded.
+	records   []ratingmodel.RatedRecord
+	batches   atomic.Int64
+	topLimits []int
+	// puts records the written ratings by record id.
+	puts map[ratingmodel.RecordID]ratingmodel.Rating
+}
+
+var errUnavailable = errors.New("unavailable")
+
+func (g *fakeRatingGateway) GetAggregatedRating(ctx context.Context, recordID ratingmodel.RecordID, recordType ratingmodel.RecordType) (float64, error) {
+	if err := block(ctx, g.delay, g.started, g.release); err != nil {
+		if g.canceled != nil {
+			g.canceled <- err
+		}
+		return 0, err
+	}
+	if g.fail.Load() {
+		return 0, errUnavailable
+	}
+	return g.rating, g.err
+}
+
+func (g *fakeRatingGateway) GetRolledUpRating(ctx context.Context, recordID ratingmodel.RecordID, recordType ratingmodel.RecordType) (float64, error) {
+	return g.GetAggregatedRating(ctx, recordID, recordType)
+}
+
+func (g *fakeRatingGateway) GetTopRated(ctx context.Context, recordType ratingmodel.RecordType, limit int, minVoteCount int) ([]ratingmodel.RatedRecord, error) {
+	g.topLimits = append(g.topLimits, limit)
+	var res []ratingmodel.RatedRecord
+	for _, r := range g.records {
+		if r.VoteCount >= minVoteCount && len(res) < limit {
+			res = append(res, r)
+		}
+	}
+	return res, nil
+}
+
+func (g *fakeRatingGateway) GetAggregatedRatings(ctx context.Context, recordIDs []ratingmodel.RecordID, recordType ratingmodel.RecordType) ([]ratingmodel.RatedRecord, error) {
+	g.batches.Add(1)
+	var res []ratingmodel.RatedRecord
+	for _, r := range g.records {
+		for _, id := range recordIDs {
+			if r.RecordID == id {
+				res = append(res, r)
+			}
+		}
+	}
+	return res, nil
+}
+
+func (g *fakeRatingGateway) PutRating(ctx context.Context, recordID ratingmodel.RecordID, recordType ratingmodel.RecordType, rating *ratingmodel.Rating) error {
+	if g.puts == nil {
+		g.puts = map[ratingmodel.RecordID]ratingmodel.Rating{}
+	}
+	g.puts[recordID] = *rating
+	return g.err
+}
+
+type fakeMetadataGateway struct {
+	delay time.Duration
+	err   error
+	// started receives a value when a Get call starts, which then waits for
+	// release to be closed, if they are set.
+	started chan<- struct{}
+	release <-chan struct{}
+	// fail makes calls fail with errUnavailable.
+	fail atomic.Bool
+	// calls counts the Get calls and batches the GetBatch calls.
+	calls   atomic.Int64
+	batches atomic.Int64
+	// movies are listed in order, with the offset of the next page as page
+	// token. Only movies are found by Get if it is set.
+	movies []*metadatamodel.Metadata
+}
+
+func (g *fakeMetadataGateway) List(ctx context.Context, filter metadatamodel.Filter, pageSize int, pageToken string) ([]*metadatamodel.Metadata, string, error) {
+	var matched []*metadatamodel.Metadata
+	for _, m := range g.movies {
+		if filter.Match(m) {
+			matched = append(matched, m)
+		}
+	}
+	offset, _ := strconv.Atoi(pageToken)
+	if offset+pageSize >= len(matched) {
+		return matched[offset:], "", nil
+	}
+	return matched[offset : offset+pageSize], strconv.Itoa(offset + pageSize), nil
+}
+
+func (g *fakeMetadataGateway) Get(ctx context.Context, id string) (*metadatamodel.Metadata, error) {
+	g.calls.Add(1)
+	return g.get(ctx, id)
+}
+
+func (g *fakeMetadataGateway) get(ctx context.Context, id string) (*metadatamodel.Metadata, error) {
+	if err := block(ctx, g.delay, g.started, g.release); err != nil {
+		return nil, err
+	}
+	if g.fail.Load() {
+		return nil, errUnavailable
+	}
+	if g.err != nil {
+		return nil, g.err
+	}
+	if g.movies == nil {
+		return &metadatamodel.Metadata{ID: id, Title: "title"}, nil
+	}
+	for _, m := range g.movies {
+		if m.ID == id {
+			return m, nil
+		}
+	}
+	return nil, gateway.ErrNotFound
+}
+
+// GetBatch returns the movies found by Get, counted as a single call.
+func (g *fakeMetadataGateway) GetBatch(ctx context.Context, ids []string) ([]*metadatamodel.Metadata, error) {
+	g.batches.Add(1)
+	var res []*metadatamodel.Metadata
+	for _, id := range ids {
+		m, err := g.get(ctx, id)
+		if errors.Is(err, gateway.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		res = append(res, m)
+	}
+	return res, nil
+}
+
+// fakeAuthGateway accepts the tokens of the form "token-<username>".
+type fakeAuthGateway struct{}
+
+func (fakeAuthGateway) ValidateToken(ctx context.Context, token string) (string, error) {
+	username, ok := strings.CutPrefix(token, "token-")
+	if !ok {
+		return "", gateway.ErrUnauthenticated
+	}
+	return username, nil
+}
+
+func TestGet(t *testing.T) {
+	ctx := context.Background()
+
+	// Both dependencies are called before either of them returns, so Get
+	// blocks forever if they are not called concurrently.
+	scope := tally.NewTestScope("", nil)
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	c := New(&fakeRatingGateway{rating: 4, started: started, release: release}, &fakeMetadataGateway{started: started, release: release}, &fakeAuthGateway{}, Options{Scope: scope})
+	type result struct {
+		details *model.MovieDetails
+		err     error
+	}
+	done := make(chan result)
+	go func() {
+		details, err := c.Get(ctx, "1")
+		done <- result{details, err}
+	}()
+	<-started
+	<-started
+	close(release)
+	res := <-done
+	details, err := res.details, res.err
+	require.NoError(t, err)
+	assert.Equal(t, "title", details.Metadata.Title)
+	require.NotNil(t, details.Rating)
+	assert.Equal(t, float64(4), *details.Rating)
+	assert.Equal(t, model.DetailsStatus{Metadata: model.SectionStatusOK, Rating: model.SectionStatusOK}, details.Status)
+	assert.Len(t, scope.Snapshot().Histograms(), 3, "one latency histogram per dependency")
+
+	// Movies without ratings and movies whose rating cannot be fetched are
+	// told apart by the rating status.
+	c = New(&fakeRatingGateway{err: gateway.ErrNotFound}, &fakeMetadataGateway{}, &fakeAuthGateway{}, Options{})
+	details, err = c.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Nil(t, details.Rating)
+	assert.Equal(t, model.SectionStatusNotFound, details.Status.Rating)
+	c = New(&fakeRatingGateway{err: errUnavailable}, &fakeMetadataGateway{}, &fakeAuthGateway{}, Options{})
+	details, err = c.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Nil(t, details.Rating)
+	assert.Equal(t, model.SectionStatusUnavailable, details.Status.Rating)
+
+	// The rating call is canceled as soon as the metadata is not found.
+	canceled := make(chan error, 1)
+	c = New(&fakeRatingGateway{delay: time.Hour, canceled: canceled}, &fakeMetadataGateway{err: gateway.ErrNotFound}, &fakeAuthGateway{}, Options{})
+	_, err = c.Get(ctx, "1")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, <-canceled, context.Canceled)
+
+	// Every dependency has its own timeout.
+	c = New(&fakeRatingGateway{delay: time.Hour}, &fakeMetadataGateway{}, &fakeAuthGateway{}, Options{RatingTimeout: 10 * time.Millisecond, MetadataTimeout: time.Hour})
+	details, err = c.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, model.SectionStatusUnavailable, details.Status.Rating)
+	c = New(&fakeRatingGateway{}, &fakeMetadataGateway{delay: time.Hour}, &fakeAuthGateway{}, Options{MetadataTimeout: 10 * time.Millisecond})
+	_, err = c.Get(ctx, "1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	scope := tally.NewTestScope("", nil)
+	ratings := &fakeRatingGateway{rating: 4}
+	ratings.fail.Store(true)
+	c := New(ratings, &fakeMetadataGateway{}, &fakeAuthGateway{}, Options{
+		RatingCircuitBreaker: &circuitbreaker.Options{MinRequests: 2, OpenTimeout: time.Hour},
+		Scope:                scope,
+	})
+	for range 2 {
+		_, err := c.Get(ctx, "1")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []CircuitBreakerStatus{{Dependency: "rating", Stats: c.rating.breaker.Stats()}}, c.CircuitBreakers())
+	require.Equal(t, circuitbreaker.Open, c.rating.breaker.State())
+
+	// Details are returned without waiting for the rating while the breaker is open.
+	ratings.delay = time.Hour
+	start := time.Now()
+	details, err := c.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, model.SectionStatusUnavailable, details.Status.Rating)
+	_, err = c.GetTopRated(ctx, 10, 0)
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.ErrorIs(t, err, circuitbreaker.ErrOpen)
+
+	gauges := scope.Snapshot().Gauges()
+	require.Contains(t, gauges, "circuit_breaker_state+component=controller,dependency=rating")
+	assert.Equal(t, float64(circuitbreaker.Open), gauges["circuit_breaker_state+component=controller,dependency=rating"].Value())
+	assert.Contains(t, scope.Snapshot().Counters(), "dependency_calls+component=controller,dependency=rating,result=circuit_open")
+}
+
+func TestGetTopRated(t *testing.T) {
+	ctx := context.Background()
+	var records []ratingmodel.RatedRecord
+	var movies []*metadatamodel.Metadata
+	for i := range 30 {
+		id := strconv.Itoa(i)
+		records = append(records, ratingmodel.RatedRecord{RecordID: ratingmodel.RecordID(id), Rating: 5 - float64(i)/10, VoteCount: 10})
+		// Only one movie in three has metadata.
+		if i%3 == 0 {
+			movies = append(movies, &metadatamodel.Metadata{ID: id, Title: "title " + id})
+		}
+	}
+	ratings := &fakeRatingGateway{records: records}
+	metadata := &fakeMetadataGateway{movies: movies}
+	c := New(ratings, metadata, &fakeAuthGateway{}, Options{})
+
+	res, err := c.GetTopRated(ctx, 5, 0)
+	require.NoError(t, err)
+	var ids []string
+	for _, m := range res {
+		ids = append(ids, m.Metadata.ID)
+	}
+	assert.Equal(t, []string{"0", "3", "6", "9", "12"}, ids, "movies without metadata are replaced")
+	assert.Equal(t, []int{10, 20}, ratings.topLimits)
+	assert.Equal(t, int64(2), metadata.batches.Load(), "the metadata of each page of ratings is fetched with one batch")
+	assert.Zero(t, metadata.calls.Load())
+
+	ratings.topLimits = nil
+	res, err = c.GetTopRated(ctx, 50, 0)
+	require.NoError(t, err)
+	assert.Len(t, res, 10, "every rated movie with metadata")
+	assert.Equal(t, []int{100}, ratings.topLimits)
+}
+
+func TestGetCached(t *testing.T) {
+	ctx := context.Background()
+	const ttl = time.Minute
+	scope := tally.NewTestScope("", nil)
+	release := make(chan struct{})
+	ratings := &fakeRatingGateway{rating: 4}
+	metadata := &fakeMetadataGateway{release: release}
+	c := New(ratings, metadata, &fakeAuthGateway{}, Options{CacheTTL: ttl, CacheMaxStale: time.Hour, Scope: scope})
+	// The clock of the cache only moves when advanced.
+	var now atomic.Int64
+	c.cache.now = func() time.Time { return time.Unix(0, now.Load()) }
+	advance := func(d time.Duration) { now.Add(int64(d)) }
+
+	// Concurrent misses are collapsed into one load, which is held until
+	// every call waits for it.
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.Get(ctx, "1")
+			assert.NoError(t, err)
+		}()
+	}
+	assert.Eventually(t, func() bool {
+		collapsed, ok := scope.Snapshot().Counters()["collapsed+component=cache"]
+		return ok && collapsed.Value() == 9
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int64(1), metadata.calls.Load())
+	_, err := c.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), metadata.calls.Load())
+
+	// Stale details are served while they are refreshed in the background.
+	advance(ttl)
+	details, err := c.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, model.SectionStatusOK, details.Status.Metadata)
+	assert.Eventually(t, func() bool { return metadata.calls.Load() == 2 }, time.Second, time.Millisecond)
+
+	// Stale details are served while the metadata service is unavailable.
+	metadata.fail.Store(true)
+	advance(ttl)
+	_, err = c.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		details, err := c.Get(ctx, "1")
+		return err == nil && details.Status.Metadata == model.SectionStatusStale
+	}, time.Second, 10*time.Millisecond)
+	_, err = c.Get(ctx, "2")
+	assert.ErrorIs(t, err, errUnavailable)
+
+	// The cached rating is served while the rating service is unavailable.
+	metadata.fail.Store(false)
+	ratings.fail.Store(true)
+	assert.Eventually(t, func() bool {
+		details, err := c.Get(ctx, "1")
+		return err == nil && details.Status == model.DetailsStatus{Metadata: model.SectionStatusOK, Rating: model.SectionStatusStale} && *details.Rating == 4
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestList(t *testing.T) {
+	ctx := context.Background()
+	metadata := &fakeMetadataGateway{}
+	for i := range 250 {
+		genre := "drama"
+		if i%2 == 1 {
+			genre = "comedy"
+		}
+		metadata.movies = append(metadata.movies, &metadatamodel.Metadata{ID: strconv.Itoa(i), Title: "title " + strconv.Itoa(1000+i), Genres: []string{genre}})
+	}
+	ratings := &fakeRatingGateway{records: []ratingmodel.RatedRecord{
+		{RecordID: "3", Rating: 4, VoteCount: 2},
+		{RecordID: "5", Rating: 5, VoteCount: 1},
+		{RecordID: "7", Rating: 4, VoteCount: 10},
+	}}
+	c := New(ratings, metadata, &fakeAuthGateway{}, Options{})
+
+	// Every page sorted by title costs a single rating call.
+	var ids []string
+	token := ""
+	for {
+		movies, next, err := c.List(ctx, ListOptions{PageSize: 100, PageToken: token})
+		require.NoError(t, err)
+		for _, m := range movies {
+			ids = append(ids, m.Metadata.ID)
+		}
+		if next == "" {
+			break
+		}
+		token = next
+	}
+	assert.Len(t, ids, 250)
+	assert.Equal(t, int64(3), ratings.batches.Load())
+
+	// Movies sorted by rating or votes are ranked across pages, and movies
+	// without ratings come last.
+	ratings.batches.Store(0)
+	movies, next, err := c.List(ctx, ListOptions{Genre: "comedy", SortBy: SortByRating, PageSize: 2})
+	require.NoError(t, err)
+	require.Len(t, movies, 2)
+	assert.Equal(t, "5", movies[0].Metadata.ID)
+	assert.Equal(t, "7", movies[1].Metadata.ID)
+	assert.Equal(t, int64(2), ratings.batches.Load(), "125 movies are ranked with 2 batches")
+	movies, _, err = c.List(ctx, ListOptions{Genre: "comedy", SortBy: SortByRating, PageSize: 2, PageToken: next})
+	require.NoError(t, err)
+	assert.Equal(t, "3", movies[0].Metadata.ID)
+	assert.Equal(t, 0, movies[1].VoteCount)
+	movies, _, err = c.List(ctx, ListOptions{Genre: "comedy", SortBy: SortByVotes, PageSize: 3})
+	require.NoError(t, err)
+	assert.Equal(t, []int{10, 2, 1}, []int{movies[0].VoteCount, movies[1].VoteCount, movies[2].VoteCount})
+
+	// Page tokens are bound to the filter and sort key they were issued for.
+	_, _, err = c.List(ctx, ListOptions{Genre: "drama", SortBy: SortByRating, PageToken: next})
+	assert.ErrorIs(t, err, ErrInvalidPageToken)
+	_, _, err = c.List(ctx, ListOptions{SortBy: "director"})
+	assert.ErrorIs(t, err, ErrInvalidSort)
+}
+
+func TestRate(t *testing.T) {
+	ctx := context.Background()
+	ratings := &fakeRatingGateway{}
+	c := New(ratings, &fakeMetadataGateway{}, &fakeAuthGateway{}, Options{})
+
+	// The rating is written for the user of the token.
+	require.NoError(t, c.Rate(ctx, "token-alice", "1", 4))
+	assert.Equal(t, ratingmodel.Rating{UserID: "alice", Value: 4}, ratings.puts["1"])
+
+	assert.ErrorIs(t, c.Rate(ctx, "", "1", 4), ErrUnauthenticated)
+	assert.ErrorIs(t, c.Rate(ctx, "forged", "1", 4), ErrUnauthenticated)
+	assert.ErrorIs(t, c.Rate(ctx, "token-alice", "1", 6), ErrInvalidRating)
+
+	// The cached details of a rated movie are loaded again.
+	ratings.rating = 4
+	c = New(ratings, &fakeMetadataGateway{}, &fakeAuthGateway{}, Options{CacheTTL: time.Hour})
+	details, err := c.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, 4.0, *details.Rating)
+	ratings.rating = 5
+	require.NoError(t, c.Rate(ctx, "token-alice", "1", 5))
+	details, err = c.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, 5.0, *details.Rating)
+
+	// Movies without metadata cannot be rated.
+	c = New(ratings, &fakeMetadataGateway{err: gateway.ErrNotFound}, &fakeAuthGateway{}, Options{})
+	assert.ErrorIs(t, c.Rate(ctx, "token-alice", "2", 4), ErrNotFound)
+	assert.NotContains(t, ratings.puts, ratingmodel.RecordID("2"))
+}
+
+func TestListByRatingPages(t *testing.T) {
+	ctx := context.Background()
+	metadata := &fakeMetadataGateway{}
+	var records []ratingmodel.RatedRecord
+	for i := range 10 {
+		id := strconv.Itoa(i)
+		metadata.movies = append(metadata.movies, &metadatamodel.Metadata{ID: id, Title: "title " + id})
+		records = append(records, ratingmodel.RatedRecord{RecordID: ratingmodel.RecordID(id), Rating: float64(i) / 2, VoteCount: 1})
+	}
+	ratings := &fakeRatingGateway{records: records}
+	c := New(ratings, metadata, &fakeAuthGateway{}, Options{})
+	var now atomic.Int64
+	c.rankings.now = func() time.Time { return time.Unix(0, now.Load()) }
+	list := func(token string) ([]string, string) {
+		movies, next, err := c.List(ctx, ListOptions{SortBy: SortByRating, PageSize: 3, PageToken: token})
+		require.NoError(t, err)
+		var ids []string
+		for _, m := range movies {
+			ids = append(ids, m.Metadata.ID)
+		}
+		return ids, next
+	}
+
+	// Later pages are read from the ranking of the first page, even if the
+	// ratings change in between.
+	ids, next := list("")
+	assert.Equal(t, []string{"9", "8", "7"}, ids)
+	ratings.records[0].Rating = 10
+	ratings.records[8].Rating = 0
+	ratings.batches.Store(0)
+	ids, next = list(next)
+	assert.Equal(t, []string{"6", "5", "4"}, ids)
+	assert.Equal(t, int64(0), ratings.batches.Load(), "pages of a ranking cost no rating call")
+
+	// Once the ranking has expired, the movies are ranked again and the list
+	// resumes after the last movie of the previous page.
+	now.Add(int64(rankingTTL))
+	ids, next = list(next)
+	assert.Equal(t, []string{"3", "2", "1"}, ids)
+	ids, next = list(next)
+	assert.Equal(t, []string{"8"}, ids)
+	assert.Empty(t, next)
+}
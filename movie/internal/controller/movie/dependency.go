@@ -0,0 +1,169 @@
+package movie
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/abhishek622/movieapp/internal/circuitbreaker"
+	"github.com/abhishek622/movieapp/movie/internal/gateway"
+	"github.com/uber-go/tally/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const tracerID = "movie-controller"
+
+// Options configures the calls of the movie controller to its dependencies
+// and the movie details cache.
+type Options struct {
+	// MetadataTimeout, RatingTimeout and AuthTimeout limit the duration of
+	// every call to the metadata, rating and auth services. Zero means no
+	// timeout.
+	MetadataTimeout time.Duration
+	RatingTimeout   time.Duration
+	AuthTimeout     time.Duration
+	// MetadataCircuitBreaker and RatingCircuitBreaker configure the circuit
+	// breakers of the metadata and rating services. Calls to a dependency
+	// without breaker options are never rejected.
+	MetadataCircuitBreaker *circuitbreaker.Options
+	RatingCircuitBreaker   *circuitbreaker.Options
+	// CacheTTL is the time movie details are cached for. Details are not
+	// cached if it is zero.
+	CacheTTL time.Duration
+	// CacheMaxStale is the time expired details are still served for while
+	// they are refreshed, or while a dependency is unavailable.
+	CacheMaxStale time.Duration
+	// CacheSize is the maximum number of cached movie details.
+	CacheSize int
+	// Scope receives the dependency, circuit breaker and cache metrics.
+	// Metrics are discarded if it is nil.
+	Scope tally.Scope
+}
+
+// dependency is a downstream service called by the controller.
+type dependency struct {
+	name    string
+	timeout time.Duration
+	// breaker rejects the calls while the dependency fails, or is nil if the
+	// dependency has no circuit breaker.
+	breaker *circuitbreaker.Breaker
+
+	latency  tally.Histogram
+	ok       tally.Counter
+	notFound tally.Counter
+	// unauthenticated counts the auth tokens rejected by the auth service.
+	unauthenticated tally.Counter
+	canceled        tally.Counter
+	timedOut        tally.Counter
+	failed          tally.Counter
+	// rejected counts the calls rejected by the open circuit breaker.
+	rejected tally.Counter
+}
+
+func newDependency(scope tally.Scope, name string, timeout time.Duration, breaker *circuitbreaker.Options) *dependency {
+	scope = scope.Tagged(map[string]string{"component": "controller", "dependency": name})
+	result := func(r string) tally.Counter {
+		return scope.Tagged(map[string]string{"result": r}).Counter("dependency_calls")
+	}
+	d := &dependency{
+		name:            name,
+		timeout:         timeout,
+		latency:         scope.Histogram("dependency_latency", tally.MustMakeExponentialDurationBuckets(time.Millisecond, 2, 15)),
+		ok:              result("ok"),
+		notFound:        result("not_found"),
+		unauthenticated: result("unauthenticated"),
+		canceled:        result("canceled"),
+		timedOut:        result("timeout"),
+		failed:          result("error"),
+		rejected:        result("circuit_open"),
+	}
+	if breaker != nil {
+		opts := *breaker
+		state := scope.Gauge("circuit_breaker_state")
+		state.Update(float64(circuitbreaker.Closed))
+		opts.OnStateChange = func(_, to circuitbreaker.State) {
+			state.Update(float64(to))
+			scope.Tagged(map[string]string{"state": to.String()}).Counter("circuit_breaker_transitions").Inc(1)
+		}
+		d.breaker = circuitbreaker.New(opts)
+	}
+	return d
+}
+
+// call calls fn in a trace span with the timeout of the dependency, and
+// records the latency and result of the call. It fails fast with
+// ErrUnavailable if the circuit breaker is open. Failed and
+// timed out calls count against the breaker, unless the caller gave up.
+func (d *dependency) call(ctx context.Context, operation string, fn func(ctx context.Context) error) error {
+	ctx, span := otel.Tracer(tracerID).Start(ctx, d.name+"/"+operation)
+	defer span.End()
+	done := func(circuitbreaker.Result, time.Duration) {}
+	if d.breaker != nil {
+		var err error
+		if done, err = d.breaker.Allow(); err != nil {
+			d.rejected.Inc(1)
+			err = fmt.Errorf("%w: %s: %w", ErrUnavailable, d.name, err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+	}
+	parent := ctx
+	if d.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	err := fn(ctx)
+	latency := time.Since(start)
+	d.latency.RecordDuration(latency)
+	switch {
+	case parent.Err() != nil:
+		done(circuitbreaker.Ignored, latency)
+	case err == nil || errors.Is(err, gateway.ErrNotFound) || errors.Is(err, gateway.ErrUnauthenticated):
+		done(circuitbreaker.Success, latency)
+	default:
+		done(circuitbreaker.Failure, latency)
+	}
+	switch {
+	case err == nil:
+		d.ok.Inc(1)
+		return nil
+	case errors.Is(err, gateway.ErrNotFound):
+		d.notFound.Inc(1)
+		return err
+	case errors.Is(err, gateway.ErrUnauthenticated):
+		d.unauthenticated.Inc(1)
+		return err
+	case errors.Is(err, context.DeadlineExceeded):
+		d.timedOut.Inc(1)
+	case errors.Is(err, context.Canceled):
+		d.canceled.Inc(1)
+	default:
+		d.failed.Inc(1)
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	return err
+}
+
+// CircuitBreakerStatus is the state of the circuit breaker of a dependency.
+type CircuitBreakerStatus struct {
+	Dependency string `json:"dependency" xml:"dependency"`
+	circuitbreaker.Stats
+}
+
+// CircuitBreakers returns the state of the circuit breakers of the
+// dependencies that have one.
+func (c *Controller) CircuitBreakers() []CircuitBreakerStatus {
+	res := []CircuitBreakerStatus{}
+	for _, d := range []*dependency{c.metadata, c.rating} {
+		if d.breaker != nil {
+			res = append(res, CircuitBreakerStatus{Dependency: d.name, Stats: d.breaker.Stats()})
+		}
+	}
+	return res
+}
@@ -0,0 +1,279 @@
+package movie
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	metadatamodel "github.com/abhishek622/movieapp/metadata/pkg/model"
+	"github.com/abhishek622/movieapp/movie/pkg/model"
+	"go.opentelemetry.io/otel"
+)
+
+var (
+	// ErrInvalidSort is returned when movies are sorted by an unknown key.
+	ErrInvalidSort = errors.New("invalid sort key")
+	// ErrInvalidPageToken is returned when a page token cannot be decoded or
+	// was issued for another filter or sort key.
+	ErrInvalidPageToken = errors.New("invalid page token")
+	// ErrTooManyMovies is returned when more movies than MaxSortedMovies
+	// would have to be sorted by rating or vote count.
+	ErrTooManyMovies = errors.New("too many movies to sort")
+)
+
+// SortBy is the key movies are listed by.
+type SortBy string
+
+const (
+	// SortByTitle lists movies by title in ascending order.
+	SortByTitle = SortBy("title")
+	// SortByRating lists movies by aggregated rating in descending order.
+	SortByRating = SortBy("rating")
+	// SortByVotes lists movies by vote count in descending order.
+	SortByVotes = SortBy("votes")
+)
+
+const (
+	// DefaultPageSize is the number of movies returned by List when no page size is set.
+	DefaultPageSize = 20
+	// MaxPageSize is the maximum number of movies returned by List.
+	MaxPageSize = 100
+	// MaxSortedMovies is the maximum number of movies that can be sorted by
+	// rating or vote count. Narrower filters have to be used for more movies.
+	MaxSortedMovies = 10000
+	// ratingBatchSize is the number of movies whose ratings are fetched with
+	// a single call to the rating service.
+	ratingBatchSize = 100
+)
+
+// ListOptions selects and orders the movies returned by List.
+type ListOptions struct {
+	// Director and Genre filter the movies. Empty values match any movie.
+	Director string
+	Genre    string
+	// SortBy defaults to SortByTitle. Ties are broken by title.
+	SortBy    SortBy
+	PageSize  int
+	PageToken string
+}
+
+// listToken is the decoded page token of a movie list.
+type listToken struct {
+	Director string `json:"director,omitempty"`
+	Genre    string `json:"genre,omitempty"`
+	SortBy   SortBy `json:"sortBy"`
+	// MetadataToken is the metadata page token of lists sorted by title.
+	MetadataToken string `json:"metadataToken,omitempty"`
+	// Ranking identifies the ranking of a list sorted by rating or vote
+	// count, and Offset is the position in the ranking.
+	Ranking string `json:"ranking,omitempty"`
+	Offset  int    `json:"offset,omitempty"`
+	// After is the last movie of the previous page of a list sorted by rating
+	// or vote count. The list resumes after it if its ranking has expired.
+	After *rankCursor `json:"after,omitempty"`
+}
+
+// rankCursor identifies a position in a list sorted by rating or vote count.
+type rankCursor struct {
+	Rating    float64 `json:"rating"`
+	VoteCount int     `json:"voteCount"`
+	Title     string  `json:"title"`
+	ID        string  `json:"id"`
+}
+
+// List returns a page of movies with their aggregated rating and vote count,
+// and the token of the next page if there is one. Movies without ratings
+// have a zero rating and vote count.
+//
+// Lists sorted by title are paged by the metadata service, so a page costs
+// one metadata call and one batched rating call. Lists sorted by rating or
+// vote count need the ratings of every movie matching the filter, which are
+// fetched in batches of ratingBatchSize movies for the first page. The
+// ranking is kept for rankingTTL and the later pages are read from it, so
+// that rating changes do not repeat or skip movies between pages. If the
+// ranking has expired, the movies are ranked again and the list resumes
+// after the last movie of the previous page.
+func (c *Controller) List(ctx context.Context, opts ListOptions) ([]model.RankedMovie, string, error) {
+	ctx, span := otel.Tracer(tracerID).Start(ctx, "Controller/List")
+	defer span.End()
+
+	if opts.SortBy == "" {
+		opts.SortBy = SortByTitle
+	}
+	if opts.SortBy != SortByTitle && opts.SortBy != SortByRating && opts.SortBy != SortByVotes {
+		return nil, "", fmt.Errorf("%w %q", ErrInvalidSort, opts.SortBy)
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = DefaultPageSize
+	} else if opts.PageSize > MaxPageSize {
+		opts.PageSize = MaxPageSize
+	}
+	token := listToken{Director: opts.Director, Genre: opts.Genre, SortBy: opts.SortBy}
+	if opts.PageToken != "" {
+		t, err := decodeListToken(opts.PageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		if t.Director != token.Director || t.Genre != token.Genre || t.SortBy != token.SortBy {
+			return nil, "", ErrInvalidPageToken
+		}
+		token = *t
+	}
+	filter := metadatamodel.Filter{Director: opts.Director, Genre: opts.Genre}
+	if opts.SortBy == SortByTitle {
+		return c.listByTitle(ctx, filter, opts.PageSize, token)
+	}
+	return c.listByRating(ctx, filter, opts.PageSize, token)
+}
+
+func (c *Controller) listByTitle(ctx context.Context, filter metadatamodel.Filter, pageSize int, token listToken) ([]model.RankedMovie, string, error) {
+	metadata, next, err := c.listMetadata(ctx, filter, pageSize, token.MetadataToken)
+	if err != nil {
+		return nil, "", err
+	}
+	res, err := c.rank(ctx, metadata)
+	if err != nil {
+		return nil, "", err
+	}
+	if next == "" {
+		return res, "", nil
+	}
+	token.MetadataToken = next
+	return res, encodeListToken(token), nil
+}
+
+func (c *Controller) listByRating(ctx context.Context, filter metadatamodel.Filter, pageSize int, token listToken) ([]model.RankedMovie, string, error) {
+	compare := compareByRating
+	if token.SortBy == SortByVotes {
+		compare = compareByVotes
+	}
+	ranked, ok := c.rankings.get(token.Ranking)
+	if !ok {
+		var err error
+		if ranked, err = c.rankAll(ctx, filter, compare); err != nil {
+			return nil, "", err
+		}
+		token.Ranking = ""
+		token.Offset = 0
+		if token.After != nil {
+			after := model.RankedMovie{
+				Metadata:  metadatamodel.Metadata{ID: token.After.ID, Title: token.After.Title},
+				Rating:    token.After.Rating,
+				VoteCount: token.After.VoteCount,
+			}
+			token.Offset = len(ranked)
+			if i := slices.IndexFunc(ranked, func(m model.RankedMovie) bool { return compare(m, after) > 0 }); i >= 0 {
+				token.Offset = i
+			}
+		}
+	}
+	if token.Offset >= len(ranked) {
+		return nil, "", nil
+	}
+	res := ranked[token.Offset:]
+	if len(res) <= pageSize {
+		return slices.Clone(res), "", nil
+	}
+	res = slices.Clone(res[:pageSize])
+	if token.Ranking == "" {
+		token.Ranking = c.rankings.put(ranked)
+	}
+	last := res[len(res)-1]
+	token.Offset += pageSize
+	token.After = &rankCursor{Rating: last.Rating, VoteCount: last.VoteCount, Title: last.Metadata.Title, ID: last.Metadata.ID}
+	return res, encodeListToken(token), nil
+}
+
+// rankAll returns every movie matching the filter with its aggregated rating,
+// ordered by compare.
+func (c *Controller) rankAll(ctx context.Context, filter metadatamodel.Filter, compare func(a, b model.RankedMovie) int) ([]model.RankedMovie, error) {
+	var metadata []*metadatamodel.Metadata
+	pageToken := ""
+	for {
+		page, next, err := c.listMetadata(ctx, filter, ratingBatchSize, pageToken)
+		if err != nil {
+			return nil, err
+		}
+		metadata = append(metadata, page...)
+		if len(metadata) > MaxSortedMovies {
+			return nil, fmt.Errorf("%w: more than %d movies match the filter", ErrTooManyMovies, MaxSortedMovies)
+		}
+		if next == "" {
+			break
+		}
+		pageToken = next
+	}
+	res, err := c.rank(ctx, metadata)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(res, compare)
+	return res, nil
+}
+
+// compareByRating orders movies by rating, then vote count, in descending
+// order. Ties are broken by title and id.
+func compareByRating(a, b model.RankedMovie) int {
+	return cmp.Or(cmp.Compare(b.Rating, a.Rating), cmp.Compare(b.VoteCount, a.VoteCount), compareByTitle(a, b))
+}
+
+// compareByVotes orders movies by vote count, then rating, in descending
+// order. Ties are broken by title and id.
+func compareByVotes(a, b model.RankedMovie) int {
+	return cmp.Or(cmp.Compare(b.VoteCount, a.VoteCount), cmp.Compare(b.Rating, a.Rating), compareByTitle(a, b))
+}
+
+func compareByTitle(a, b model.RankedMovie) int {
+	return cmp.Or(cmp.Compare(a.Metadata.Title, b.Metadata.Title), cmp.Compare(a.Metadata.ID, b.Metadata.ID))
+}
+
+// rank returns the movies of metadata with their aggregated ratings, fetched
+// in batches of ratingBatchSize movies.
+func (c *Controller) rank(ctx context.Context, metadata []*metadatamodel.Metadata) ([]model.RankedMovie, error) {
+	ids := make([]string, len(metadata))
+	for i, m := range metadata {
+		ids[i] = m.ID
+	}
+	ratings, err := c.GetRatingBatch(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]model.RankedMovie, len(metadata))
+	for i, m := range metadata {
+		r := ratings[m.ID]
+		res[i] = model.RankedMovie{Metadata: *m, Rating: r.Rating, VoteCount: r.VoteCount}
+	}
+	return res, nil
+}
+
+func (c *Controller) listMetadata(ctx context.Context, filter metadatamodel.Filter, pageSize int, pageToken string) ([]*metadatamodel.Metadata, string, error) {
+	var metadata []*metadatamodel.Metadata
+	var next string
+	err := c.metadata.call(ctx, "List", func(ctx context.Context) (err error) {
+		metadata, next, err = c.metadataGateway.List(ctx, filter, pageSize, pageToken)
+		return err
+	})
+	return metadata, next, err
+}
+
+// encodeListToken returns an opaque token for a movie list position.
+func encodeListToken(t listToken) string {
+	b, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeListToken(token string) (*listToken, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	var t listToken
+	if err := json.Unmarshal(b, &t); err != nil || t.Offset < 0 {
+		return nil, ErrInvalidPageToken
+	}
+	return &t, nil
+}
@@ -0,0 +1,76 @@
+package movie
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/abhishek622/movieapp/movie/pkg/model"
+)
+
+const (
+	// rankingTTL is how long the ranking of a list sorted by rating or vote
+	// count is kept for its later pages.
+	rankingTTL = 10 * time.Minute
+	// maxRankings is the number of rankings kept at once. The oldest ranking
+	// is dropped to make room for a new one.
+	maxRankings = 100
+)
+
+// rankingCache keeps the rankings of lists sorted by rating or vote count, so
+// that every page of a list is read from the ranking of its first page.
+type rankingCache struct {
+	now func() time.Time
+
+	mu       sync.Mutex
+	rankings map[string]*ranking
+}
+
+type ranking struct {
+	movies    []model.RankedMovie
+	createdAt time.Time
+}
+
+func newRankingCache() *rankingCache {
+	return &rankingCache{now: time.Now, rankings: map[string]*ranking{}}
+}
+
+// get returns a ranking by id unless it has expired.
+func (c *rankingCache) get(id string) ([]model.RankedMovie, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, ok := c.rankings[id]
+	if !ok {
+		return nil, false
+	}
+	if c.now().Sub(r.createdAt) >= rankingTTL {
+		delete(c.rankings, id)
+		return nil, false
+	}
+	return r.movies, true
+}
+
+// put stores a ranking and returns its id.
+func (c *rankingCache) put(movies []model.RankedMovie) string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	id := hex.EncodeToString(b)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	var oldest string
+	for k, r := range c.rankings {
+		if now.Sub(r.createdAt) >= rankingTTL {
+			delete(c.rankings, k)
+		} else if oldest == "" || r.createdAt.Before(c.rankings[oldest].createdAt) {
+			oldest = k
+		}
+	}
+	if len(c.rankings) >= maxRankings {
+		delete(c.rankings, oldest)
+	}
+	c.rankings[id] = &ranking{movies: movies, createdAt: now}
+	return id
+}
@@ -0,0 +1,74 @@
+package movie
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/abhishek622/movieapp/movie/internal/gateway"
+	ratingmodel "github.com/abhishek622/movieapp/rating/pkg/model"
+	"go.opentelemetry.io/otel"
+)
+
+var (
+	// ErrUnauthenticated is returned when a rating is sent without a valid
+	// auth token.
+	ErrUnauthenticated = errors.New("missing or invalid auth token")
+	// ErrInvalidRating is returned when a rating value is out of range.
+	ErrInvalidRating = errors.New("invalid rating value")
+)
+
+const (
+	// MinRatingValue and MaxRatingValue bound the values of the ratings sent
+	// through the movie service.
+	MinRatingValue = 1
+	MaxRatingValue = 5
+)
+
+// Rate writes the rating of a movie by the user of an auth token. The token
+// is validated by the auth service, and the movie must have metadata. It
+// returns ErrUnauthenticated if the token is missing or rejected, and
+// ErrNotFound if the movie does not exist. The cached details of the movie
+// are dropped once the rating is written.
+func (c *Controller) Rate(ctx context.Context, token string, id string, value ratingmodel.RatingValue) error {
+	ctx, span := otel.Tracer(tracerID).Start(ctx, "Controller/Rate")
+	defer span.End()
+
+	if value < MinRatingValue || value > MaxRatingValue {
+		return fmt.Errorf("%w: %d is not between %d and %d", ErrInvalidRating, value, MinRatingValue, MaxRatingValue)
+	}
+	if token == "" {
+		return ErrUnauthenticated
+	}
+	var username string
+	err := c.auth.call(ctx, "ValidateToken", func(ctx context.Context) (err error) {
+		username, err = c.authGateway.ValidateToken(ctx, token)
+		return err
+	})
+	if err != nil && errors.Is(err, gateway.ErrUnauthenticated) {
+		return ErrUnauthenticated
+	} else if err != nil {
+		return err
+	}
+	if username == "" {
+		// Tokens without a username cannot be attributed to a user.
+		return ErrUnauthenticated
+	}
+	if _, err := c.getMetadata(ctx, id); err != nil && errors.Is(err, gateway.ErrNotFound) {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	rating := &ratingmodel.Rating{UserID: ratingmodel.UserID(username), Value: value}
+	err = c.rating.call(ctx, "PutRating", func(ctx context.Context) error {
+		return c.ratingGateway.PutRating(ctx, ratingmodel.RecordID(id), ratingmodel.RecordTypeMovie, rating)
+	})
+	if err != nil {
+		return err
+	}
+	if c.cache != nil {
+		// The cached rating does not count the new one.
+		c.cache.invalidate(id)
+	}
+	return nil
+}
@@ -3,3 +3,6 @@ package gateway
 import "errors"
 
 var ErrNotFound = errors.New("not found")
+
+// ErrUnauthenticated is returned when an auth token is rejected.
+var ErrUnauthenticated = errors.New("unauthenticated")
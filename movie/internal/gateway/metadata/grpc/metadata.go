@@ -6,34 +6,32 @@ import (
 	"github.com/abhishek622/movieapp/gen"
 	"github.com/abhishek622/movieapp/internal/grpcutil"
 	"github.com/abhishek622/movieapp/metadata/pkg/model"
-	"github.com/abhishek622/movieapp/pkg/discovery"
+	"github.com/abhishek622/movieapp/movie/internal/gateway"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/status"
 )
 
 type Gateway struct {
-	registry discovery.Registry
-	creds    credentials.TransportCredentials
+	pool *grpcutil.Pool
 }
 
-func New(registry discovery.Registry, creds credentials.TransportCredentials) *Gateway {
-	return &Gateway{registry, creds}
+func New(pool *grpcutil.Pool) *Gateway {
+	return &Gateway{pool}
 }
 
 func (g *Gateway) Get(ctx context.Context, id string) (*model.Metadata, error) {
-	conn, err := grpcutil.ServiceConnection(ctx, "metadata", g.registry, g.creds)
+	conn, err := g.pool.Conn("metadata")
 	if err != nil {
 		return nil, err
 	}
-
-	defer conn.Close()
 	client := gen.NewMetadataServiceClient(conn)
 	const maxRetries = 5
 	for range maxRetries {
 		var resp *gen.GetMetadataResponse
 		resp, err = client.GetMetadata(ctx, &gen.GetMetadataRequest{MovieId: id})
-		if err != nil {
+		if err != nil && status.Code(err) == codes.NotFound {
+			return nil, gateway.ErrNotFound
+		} else if err != nil {
 			if shouldRetry(err) {
 				continue
 			}
@@ -46,6 +44,49 @@ func (g *Gateway) Get(ctx context.Context, id string) (*model.Metadata, error) {
 	return nil, err
 }
 
+// List returns a page of movie metadata selected by the filter, ordered by
+// title, and the token of the next page if there is one.
+func (g *Gateway) List(ctx context.Context, filter model.Filter, pageSize int, pageToken string) ([]*model.Metadata, string, error) {
+	conn, err := g.pool.Conn("metadata")
+	if err != nil {
+		return nil, "", err
+	}
+	client := gen.NewMetadataServiceClient(conn)
+	resp, err := client.ListMetadata(ctx, &gen.ListMetadataRequest{
+		Director:  filter.Director,
+		Genre:     filter.Genre,
+		PageSize:  int32(pageSize),
+		PageToken: pageToken,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	var res []*model.Metadata
+	for _, m := range resp.Metadata {
+		res = append(res, model.MetadataFromProto(m))
+	}
+	return res, resp.NextPageToken, nil
+}
+
+// GetBatch returns the metadata of several movies with a single request.
+// Movies that are not found are not returned.
+func (g *Gateway) GetBatch(ctx context.Context, ids []string) ([]*model.Metadata, error) {
+	conn, err := g.pool.Conn("metadata")
+	if err != nil {
+		return nil, err
+	}
+	client := gen.NewMetadataServiceClient(conn)
+	resp, err := client.GetMetadataBatch(ctx, &gen.GetMetadataBatchRequest{MovieIds: ids})
+	if err != nil {
+		return nil, err
+	}
+	var res []*model.Metadata
+	for _, m := range resp.Metadata {
+		res = append(res, model.MetadataFromProto(m))
+	}
+	return res, nil
+}
+
 func shouldRetry(err error) bool {
 	e, ok := status.FromError(err)
 	if !ok {
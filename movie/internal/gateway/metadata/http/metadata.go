@@ -7,6 +7,7 @@ import (
 	"log"
 	"math/rand"
 	"net/http"
+	"strconv"
 
 	"github.com/abhishek622/movieapp/metadata/pkg/model"
 	"github.com/abhishek622/movieapp/movie/internal/gateway"
@@ -21,22 +22,23 @@ func New(registry discovery.Registry) *Gateway {
 	return &Gateway{registry}
 }
 
-func (g *Gateway) Get(ctx context.Context, id string) (*model.Metadata, error) {
-	addrs, err := g.registry.ServiceAddresses(ctx, "metadata")
+// serviceAddress returns the address of the HTTP endpoint of a random
+// instance of the metadata service.
+func (g *Gateway) serviceAddress(ctx context.Context) (string, error) {
+	addrs, err := g.registry.ServiceAddresses(ctx, "metadata", discovery.EndpointHTTP)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
+	return addrs[rand.Intn(len(addrs))], nil
+}
 
-	// Use HTTP port (gRPC port + 1000)
-	addr := addrs[rand.Intn(len(addrs))]
-	// Extract port from address and add 1000
-	port := "9081" // 8081 + 1000 for metadata service
-	if len(addr) > 0 {
-		// Parse the address to get port and add 1000
-		port = "9081" // 8081 + 1000
+func (g *Gateway) Get(ctx context.Context, id string) (*model.Metadata, error) {
+	addr, err := g.serviceAddress(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	url := "http://localhost:" + port + "/metadata"
+	url := "http://" + addr + "/metadata"
 	log.Printf("Calling metadata service. Request: GET %s", url)
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
@@ -65,3 +67,91 @@ func (g *Gateway) Get(ctx context.Context, id string) (*model.Metadata, error) {
 	}
 	return v, nil
 }
+
+// List returns a page of movie metadata selected by the filter, ordered by
+// title, and the token of the next page if there is one.
+func (g *Gateway) List(ctx context.Context, filter model.Filter, pageSize int, pageToken string) ([]*model.Metadata, string, error) {
+	addr, err := g.serviceAddress(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	url := "http://" + addr + "/metadata/list"
+	log.Printf("Calling metadata service. Request: GET %s", url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	values := req.URL.Query()
+	if filter.Director != "" {
+		values.Add("director", filter.Director)
+	}
+	if filter.Genre != "" {
+		values.Add("genre", filter.Genre)
+	}
+	if pageSize > 0 {
+		values.Add("pageSize", strconv.Itoa(pageSize))
+	}
+	if pageToken != "" {
+		values.Add("pageToken", pageToken)
+	}
+	req.URL.RawQuery = values.Encode()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, "", fmt.Errorf("non-2xx response: %v", resp)
+	}
+
+	var v struct {
+		Metadata      []*model.Metadata `json:"metadata"`
+		NextPageToken string            `json:"nextPageToken"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		return nil, "", err
+	}
+	return v.Metadata, v.NextPageToken, nil
+}
+
+// GetBatch returns the metadata of several movies with a single request.
+// Movies that are not found are not returned.
+func (g *Gateway) GetBatch(ctx context.Context, ids []string) ([]*model.Metadata, error) {
+	addr, err := g.serviceAddress(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	url := "http://" + addr + "/metadata/batch"
+	log.Printf("Calling metadata service. Request: GET %s", url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	values := req.URL.Query()
+	for _, id := range ids {
+		values.Add("id", id)
+	}
+	req.URL.RawQuery = values.Encode()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("non-2xx response: %v", resp)
+	}
+
+	var v struct {
+		Metadata []*model.Metadata `json:"metadata"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		return nil, err
+	}
+	return v.Metadata, nil
+}
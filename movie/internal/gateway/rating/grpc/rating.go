@@ -5,33 +5,101 @@ import (
 
 	"github.com/abhishek622/movieapp/gen"
 	"github.com/abhishek622/movieapp/internal/grpcutil"
-	"github.com/abhishek622/movieapp/pkg/discovery"
+	"github.com/abhishek622/movieapp/movie/internal/gateway"
 	"github.com/abhishek622/movieapp/rating/pkg/model"
-	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Gateway defines an gRPC gateway for a rating service.
 type Gateway struct {
-	registry discovery.Registry
-	creds    credentials.TransportCredentials
+	pool *grpcutil.Pool
 }
 
 // New creates a new gRPC gateway for a rating service.
-func New(registry discovery.Registry, creds credentials.TransportCredentials) *Gateway {
-	return &Gateway{registry, creds}
+func New(pool *grpcutil.Pool) *Gateway {
+	return &Gateway{pool}
 }
 
 // GetAggregatedRating returns the aggregated rating for a record or ErrNotFound if there are no ratings for it.
 func (g *Gateway) GetAggregatedRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType) (float64, error) {
-	conn, err := grpcutil.ServiceConnection(ctx, "rating", g.registry, g.creds)
+	return g.getAggregatedRating(ctx, &gen.GetAggregatedRatingRequest{RecordId: string(recordID), RecordType: string(recordType)})
+}
+
+// GetRolledUpRating returns the aggregated rating of the child records of a
+// record, such as the episodes of a series, or ErrNotFound if they have no ratings.
+func (g *Gateway) GetRolledUpRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType) (float64, error) {
+	return g.getAggregatedRating(ctx, &gen.GetAggregatedRatingRequest{RecordId: string(recordID), RecordType: string(recordType), RollUp: true})
+}
+
+func (g *Gateway) getAggregatedRating(ctx context.Context, req *gen.GetAggregatedRatingRequest) (float64, error) {
+	conn, err := g.pool.Conn("rating")
 	if err != nil {
 		return 0, err
 	}
-	defer conn.Close()
 	client := gen.NewRatingServiceClient(conn)
-	resp, err := client.GetAggregatedRating(ctx, &gen.GetAggregatedRatingRequest{RecordId: string(recordID), RecordType: string(recordType)})
-	if err != nil {
+	resp, err := client.GetAggregatedRating(ctx, req)
+	if err != nil && status.Code(err) == codes.NotFound {
+		return 0, gateway.ErrNotFound
+	} else if err != nil {
 		return 0, err
 	}
 	return resp.RatingValue, nil
 }
+
+// GetTopRated returns the records of a given type with the highest aggregated rating.
+func (g *Gateway) GetTopRated(ctx context.Context, recordType model.RecordType, limit int, minVoteCount int) ([]model.RatedRecord, error) {
+	conn, err := g.pool.Conn("rating")
+	if err != nil {
+		return nil, err
+	}
+	client := gen.NewRatingServiceClient(conn)
+	resp, err := client.GetTopRated(ctx, &gen.GetTopRatedRequest{RecordType: string(recordType), Limit: int32(limit), MinVoteCount: int32(minVoteCount)})
+	if err != nil {
+		return nil, err
+	}
+	var res []model.RatedRecord
+	for _, r := range resp.Records {
+		res = append(res, *model.RatedRecordFromProto(r))
+	}
+	return res, nil
+}
+
+// GetAggregatedRatings returns the aggregated ratings of several records of a
+// given type with a single call. Records without ratings are not returned.
+func (g *Gateway) GetAggregatedRatings(ctx context.Context, recordIDs []model.RecordID, recordType model.RecordType) ([]model.RatedRecord, error) {
+	conn, err := g.pool.Conn("rating")
+	if err != nil {
+		return nil, err
+	}
+	client := gen.NewRatingServiceClient(conn)
+	req := &gen.GetAggregatedRatingsRequest{RecordType: string(recordType)}
+	for _, id := range recordIDs {
+		req.RecordIds = append(req.RecordIds, string(id))
+	}
+	resp, err := client.GetAggregatedRatings(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var res []model.RatedRecord
+	for _, r := range resp.Records {
+		res = append(res, *model.RatedRecordFromProto(r))
+	}
+	return res, nil
+}
+
+// PutRating writes the rating of a user for a record.
+func (g *Gateway) PutRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) error {
+	conn, err := g.pool.Conn("rating")
+	if err != nil {
+		return err
+	}
+	client := gen.NewRatingServiceClient(conn)
+	_, err = client.PutRating(ctx, &gen.PutRatingRequest{
+		UserId:      string(rating.UserID),
+		RecordId:    string(recordID),
+		RecordType:  string(recordType),
+		RatingValue: int32(rating.Value),
+	})
+	return err
+}
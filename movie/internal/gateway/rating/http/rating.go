@@ -21,22 +21,29 @@ func New(registry discovery.Registry) *Gateway {
 	return &Gateway{registry}
 }
 
+// serviceAddress returns the address of the HTTP endpoint of a random
+// instance of the rating service.
+func (g *Gateway) serviceAddress(ctx context.Context) (string, error) {
+	addrs, err := g.registry.ServiceAddresses(ctx, "rating", discovery.EndpointHTTP)
+	if err != nil {
+		return "", err
+	}
+	return addrs[rand.Intn(len(addrs))], nil
+}
+
 func (g *Gateway) GetAggregatedRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType) (float64, error) {
-	addrs, err := g.registry.ServiceAddresses(ctx, "rating")
+	return g.getRating(ctx, recordID, recordType, false)
+}
+
+// getRating returns the aggregated rating of a record, or of its child
+// records if rollup is set, or ErrNotFound if there are no ratings.
+func (g *Gateway) getRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rollup bool) (float64, error) {
+	addr, err := g.serviceAddress(ctx)
 	if err != nil {
 		return 0, err
 	}
 
-	// Use HTTP port (gRPC port + 1000)
-	addr := addrs[rand.Intn(len(addrs))]
-	// Extract port from address and add 1000
-	port := "8082" // Default rating port
-	if len(addr) > 0 {
-		// Parse the address to get port and add 1000
-		port = "9082" // 8082 + 1000
-	}
-	
-	url := "http://localhost:" + port + "/rating"
+	url := "http://" + addr + "/rating"
 	log.Printf("Calling rating service. Request: GET %s", url)
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
@@ -46,7 +53,10 @@ func (g *Gateway) GetAggregatedRating(ctx context.Context, recordID model.Record
 	req = req.WithContext(ctx)
 	values := req.URL.Query()
 	values.Add("id", string(recordID))
-	values.Add("type", fmt.Sprintf("%v", recordType))
+	values.Add("type", string(recordType))
+	if rollup {
+		values.Add("rollup", "true")
+	}
 	req.URL.RawQuery = values.Encode()
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
@@ -69,21 +79,12 @@ func (g *Gateway) GetAggregatedRating(ctx context.Context, recordID model.Record
 }
 
 func (g *Gateway) PutRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) error {
-	addrs, err := g.registry.ServiceAddresses(ctx, "rating")
+	addr, err := g.serviceAddress(ctx)
 	if err != nil {
 		return err
 	}
-	
-	// Use HTTP port (gRPC port + 1000)
-	addr := addrs[rand.Intn(len(addrs))]
-	// Extract port from address and add 1000
-	port := "8082" // Default rating port
-	if len(addr) > 0 {
-		// Parse the address to get port and add 1000
-		port = "9082" // 8082 + 1000
-	}
-	
-	url := "http://localhost:" + port + "/rating"
+
+	url := "http://" + addr + "/rating"
 
 	log.Printf("Calling rating service. Request: PUT %s", url)
 	req, err := http.NewRequest(http.MethodPut, url, nil)
@@ -110,3 +111,86 @@ func (g *Gateway) PutRating(ctx context.Context, recordID model.RecordID, record
 
 	return nil
 }
+
+func (g *Gateway) GetTopRated(ctx context.Context, recordType model.RecordType, limit int, minVoteCount int) ([]model.RatedRecord, error) {
+	addr, err := g.serviceAddress(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	url := "http://" + addr + "/rating/top"
+	log.Printf("Calling rating service. Request: GET %s", url)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.WithContext(ctx)
+	values := req.URL.Query()
+	values.Add("type", fmt.Sprintf("%v", recordType))
+	values.Add("limit", fmt.Sprintf("%d", limit))
+	values.Add("minVotes", fmt.Sprintf("%d", minVoteCount))
+	req.URL.RawQuery = values.Encode()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("non-2xx response: %v", resp)
+	}
+
+	var v []model.RatedRecord
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// GetRolledUpRating returns the aggregated rating of the child records of a
+// record, such as the episodes of a series, or ErrNotFound if they have no ratings.
+func (g *Gateway) GetRolledUpRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType) (float64, error) {
+	return g.getRating(ctx, recordID, recordType, true)
+}
+
+// GetAggregatedRatings returns the aggregated ratings of several records of a
+// given type with a single request. Records without ratings are not returned.
+func (g *Gateway) GetAggregatedRatings(ctx context.Context, recordIDs []model.RecordID, recordType model.RecordType) ([]model.RatedRecord, error) {
+	addr, err := g.serviceAddress(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	url := "http://" + addr + "/rating/batch"
+	log.Printf("Calling rating service. Request: GET %s", url)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.WithContext(ctx)
+	values := req.URL.Query()
+	values.Add("type", string(recordType))
+	for _, id := range recordIDs {
+		values.Add("id", string(id))
+	}
+	req.URL.RawQuery = values.Encode()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("non-2xx response: %v", resp)
+	}
+
+	var v []model.RatedRecord
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
\ No newline at end of file
@@ -0,0 +1,98 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/abhishek622/movieapp/internal/httputil"
+	"github.com/abhishek622/movieapp/movie/internal/controller/movie"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// maxRequestSize is the maximum size of the body of a GraphQL request.
+const maxRequestSize = 1 << 20
+
+// Limits reject the queries that are too expensive. Zero values disable a
+// limit.
+type Limits struct {
+	// MaxDepth is the maximum nesting depth of the fields of a query.
+	MaxDepth int
+	// MaxComplexity is the maximum cost of a query. Every field costs one,
+	// and list fields cost their page size times the fields selected on their
+	// items.
+	MaxComplexity int
+}
+
+// request is a GraphQL request.
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Handler serves the movie graph over HTTP.
+type Handler struct {
+	ctrl   *movie.Controller
+	schema *graphql.Schema
+	limits Limits
+}
+
+// New creates a GraphQL handler rejecting the queries deeper or more
+// complex than the limits.
+func New(ctrl *movie.Controller, limits Limits) *Handler {
+	s := graphql.MustParseSchema(schema, &queryResolver{ctrl}, graphql.UseStringDescriptions(), graphql.MaxDepth(limits.MaxDepth))
+	return &Handler{ctrl: ctrl, schema: s, limits: limits}
+}
+
+// Query handles /graphql requests. Queries are sent as a JSON body of POST
+// requests, or as the query, operationName and variables parameters of GET
+// requests. The responses of invalid queries have a 400 status.
+func (h *Handler) Query(w http.ResponseWriter, req *http.Request) {
+	var r request
+	switch req.Method {
+	case http.MethodGet:
+		r.Query = req.FormValue("query")
+		r.OperationName = req.FormValue("operationName")
+		if v := req.FormValue("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &r.Variables); err != nil {
+				httputil.Error(w, req, http.StatusBadRequest, "invalid variables")
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(io.LimitReader(req.Body, maxRequestSize)).Decode(&r); err != nil {
+			httputil.Error(w, req, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		httputil.Error(w, req, http.StatusMethodNotAllowed, "")
+		return
+	}
+	if r.Query == "" {
+		httputil.Error(w, req, http.StatusBadRequest, "empty query")
+		return
+	}
+	ctx := context.WithValue(req.Context(), loadersKey{}, newLoaders(h.ctrl))
+	ctx = context.WithValue(ctx, budgetKey{}, &budget{max: int64(h.limits.MaxComplexity)})
+	resp := h.schema.Exec(ctx, r.Query, r.OperationName, r.Variables)
+	w.Header().Set("Content-Type", "application/json")
+	if resp.Data == nil {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("GraphQL response write error: %v\n", err)
+	}
+}
+
+// Schema handles /graphql/schema requests with the schema definition of the
+// movie graph.
+func (h *Handler) Schema(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if _, err := io.WriteString(w, schema); err != nil {
+		log.Printf("GraphQL schema write error: %v\n", err)
+	}
+}
@@ -0,0 +1,235 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	metadatamodel "github.com/abhishek622/movieapp/metadata/pkg/model"
+	"github.com/abhishek622/movieapp/movie/internal/controller/movie"
+	"github.com/abhishek622/movieapp/movie/internal/gateway"
+	ratingmodel "github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMetadataGateway serves movies and records the batches of ids it is
+// asked for.
+type fakeMetadataGateway struct {
+	movies []*metadatamodel.Metadata
+
+	mu      sync.Mutex
+	batches [][]string
+}
+
+func (g *fakeMetadataGateway) Get(_ context.Context, id string) (*metadatamodel.Metadata, error) {
+	for _, m := range g.movies {
+		if m.ID == id {
+			return m, nil
+		}
+	}
+	return nil, gateway.ErrNotFound
+}
+
+func (g *fakeMetadataGateway) GetBatch(ctx context.Context, ids []string) ([]*metadatamodel.Metadata, error) {
+	g.mu.Lock()
+	g.batches = append(g.batches, ids)
+	g.mu.Unlock()
+	var res []*metadatamodel.Metadata
+	for _, id := range ids {
+		if m, err := g.Get(ctx, id); err == nil {
+			res = append(res, m)
+		}
+	}
+	return res, nil
+}
+
+func (g *fakeMetadataGateway) List(_ context.Context, filter metadatamodel.Filter, pageSize int, pageToken string) ([]*metadatamodel.Metadata, string, error) {
+	var matched []*metadatamodel.Metadata
+	for _, m := range g.movies {
+		if filter.Match(m) {
+			matched = append(matched, m)
+		}
+	}
+	offset, _ := strconv.Atoi(pageToken)
+	if offset+pageSize >= len(matched) {
+		return matched[offset:], "", nil
+	}
+	return matched[offset : offset+pageSize], strconv.Itoa(offset + pageSize), nil
+}
+
+// fakeRatingGateway rates movie i with i votes of value i.
+type fakeRatingGateway struct {
+	mu      sync.Mutex
+	batches int
+}
+
+func (g *fakeRatingGateway) GetAggregatedRating(context.Context, ratingmodel.RecordID, ratingmodel.RecordType) (float64, error) {
+	return 0, gateway.ErrNotFound
+}
+
+func (g *fakeRatingGateway) GetRolledUpRating(context.Context, ratingmodel.RecordID, ratingmodel.RecordType) (float64, error) {
+	return 0, gateway.ErrNotFound
+}
+
+func (g *fakeRatingGateway) GetTopRated(context.Context, ratingmodel.RecordType, int, int) ([]ratingmodel.RatedRecord, error) {
+	return nil, nil
+}
+
+func (g *fakeRatingGateway) GetAggregatedRatings(_ context.Context, ids []ratingmodel.RecordID, recordType ratingmodel.RecordType) ([]ratingmodel.RatedRecord, error) {
+	g.mu.Lock()
+	g.batches++
+	g.mu.Unlock()
+	var res []ratingmodel.RatedRecord
+	for _, id := range ids {
+		if n, _ := strconv.Atoi(string(id)); n > 0 {
+			res = append(res, ratingmodel.RatedRecord{RecordID: id, RecordType: recordType, Rating: float64(n), VoteCount: n})
+		}
+	}
+	return res, nil
+}
+
+func (g *fakeRatingGateway) PutRating(context.Context, ratingmodel.RecordID, ratingmodel.RecordType, *ratingmodel.Rating) error {
+	return nil
+}
+
+type fakeAuthGateway struct{}
+
+func (fakeAuthGateway) ValidateToken(context.Context, string) (string, error) {
+	return "", gateway.ErrUnauthenticated
+}
+
+func newHandler(limits Limits) (*Handler, *fakeMetadataGateway, *fakeRatingGateway) {
+	metadata := &fakeMetadataGateway{movies: []*metadatamodel.Metadata{
+		{ID: "0", Title: "Alien", Director: "Scott", Genres: []string{"horror"}},
+		{ID: "1", Title: "Heat", Director: "Mann", Genres: []string{"crime"}},
+		{ID: "2", Title: "Thief", Director: "Mann", Genres: []string{"crime"}},
+	}}
+	ratings := &fakeRatingGateway{}
+	ctrl := movie.New(ratings, metadata, fakeAuthGateway{}, movie.Options{})
+	return New(ctrl, limits), metadata, ratings
+}
+
+// query posts a GraphQL query and returns the status and body of the response.
+func query(t *testing.T, h *Handler, q string, variables map[string]any) (int, string) {
+	t.Helper()
+	body, err := json.Marshal(map[string]any{"query": q, "variables": variables})
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	h.Query(w, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body))))
+	return w.Code, w.Body.String()
+}
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]any
+		want      string
+	}{
+		{
+			name:      "movie",
+			query:     `query Q($id: ID!) { movie(id: $id) { id ...m rating { value voteCount } director { name } } } fragment m on Movie { metadata { title genres } }`,
+			variables: map[string]any{"id": "1"},
+			want:      `{"data":{"movie":{"id":"1","metadata":{"title":"Heat","genres":["crime"]},"rating":{"value":1,"voteCount":1},"director":{"name":"Mann"}}}}`,
+		},
+		{
+			name:  "movie without rating",
+			query: `{ movie(id: "0") { rating { value } } missing: movie(id: "9") { id } }`,
+			want:  `{"data":{"movie":{"rating":null},"missing":null}}`,
+		},
+		{
+			name:  "page",
+			query: `{ movies(genre: "crime", first: 1) { movies { metadata { title } } nextPageToken } }`,
+			want:  `{"data":{"movies":{"movies":[{"metadata":{"title":"Heat"}}],"nextPageToken":"` + pageToken(t) + `"}}}`,
+		},
+		{
+			name:  "director",
+			query: `{ director(name: "Mann") { name movies(sortBy: RATING) { id } } }`,
+			want:  `{"data":{"director":{"name":"Mann","movies":[{"id":"2"},{"id":"1"}]}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _ := newHandler(Limits{})
+			status, body := query(t, h, tt.query, tt.variables)
+			assert.Equal(t, http.StatusOK, status)
+			assert.JSONEq(t, tt.want, body)
+		})
+	}
+}
+
+// pageToken returns the token of the second page of crime movies listed one
+// at a time, as listed by the controller.
+func pageToken(t *testing.T) string {
+	t.Helper()
+	h, _, _ := newHandler(Limits{})
+	_, next, err := h.ctrl.List(context.Background(), movie.ListOptions{Genre: "crime", PageSize: 1})
+	require.NoError(t, err)
+	require.NotEmpty(t, next)
+	return next
+}
+
+func TestQueryBatches(t *testing.T) {
+	h, metadata, ratings := newHandler(Limits{})
+
+	// The movies of a query are loaded with one metadata and one rating batch.
+	status, body := query(t, h, `{ a: movie(id: "0") { rating { value } } b: movie(id: "1") { rating { value } } c: movie(id: "2") { rating { value } } }`, nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"data":{"a":{"rating":null},"b":{"rating":{"value":1}},"c":{"rating":{"value":2}}}}`, body)
+	require.Len(t, metadata.batches, 1)
+	assert.ElementsMatch(t, []string{"0", "1", "2"}, metadata.batches[0])
+	assert.Equal(t, 1, ratings.batches)
+
+	// Listed movies are not loaded again.
+	metadata.batches, ratings.batches = nil, 0
+	status, _ = query(t, h, `{ movies { movies { metadata { title } rating { value } director { name } } } }`, nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Empty(t, metadata.batches)
+	assert.Equal(t, 1, ratings.batches, "the ratings of the page are fetched by the list")
+}
+
+func TestQueryLimits(t *testing.T) {
+	q := `{ movies(first: 5) { movies { id director { name } } } }`
+
+	h, _, _ := newHandler(Limits{MaxDepth: 3})
+	status, body := query(t, h, q, nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body, "exceeds max depth 3")
+
+	// movies costs 1 + 5 * (id + director + name) = 16.
+	h, _, _ = newHandler(Limits{MaxComplexity: 15})
+	status, body = query(t, h, q, nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "the query costs more than the maximum complexity of 15")
+
+	h, _, _ = newHandler(Limits{MaxDepth: 4, MaxComplexity: 16})
+	status, body = query(t, h, q, nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.NotContains(t, body, "errors")
+}
+
+func TestQueryErrors(t *testing.T) {
+	h, _, _ := newHandler(Limits{})
+	status, body := query(t, h, `{ movie(id: "1") { rating { stars } } }`, nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body, `Cannot query field \"stars\" on type \"Rating\"`)
+
+	status, body = query(t, h, `{ movies(after: "invalid") { nextPageToken } }`, nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, movie.ErrInvalidPageToken.Error())
+
+	w := httptest.NewRecorder()
+	h.Query(w, httptest.NewRequest(http.MethodGet, "/graphql?"+url.Values{"query": {`{ director(name: "Scott") { movies { id } } }`}}.Encode(), nil))
+	assert.JSONEq(t, `{"data":{"director":{"movies":[{"id":"0"}]}}}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	h.Query(w, httptest.NewRequest(http.MethodDelete, "/graphql", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
@@ -0,0 +1,339 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/abhishek622/movieapp/internal/dataloader"
+	metadatamodel "github.com/abhishek622/movieapp/metadata/pkg/model"
+	"github.com/abhishek622/movieapp/movie/internal/controller/movie"
+	"github.com/abhishek622/movieapp/movie/pkg/model"
+	ratingmodel "github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// schema is the movie graph. List fields cost their page size times the
+// number of fields selected on their items.
+const schema = `
+schema {
+  query: Query
+}
+
+type Query {
+  "The movie with the given id, or null if it is not found."
+  movie(id: ID!): Movie
+  "A page of movies, filtered by director and genre."
+  movies(
+    director: String
+    genre: String
+    sortBy: MovieSort! = TITLE
+    first: Int! = 20
+    "The nextPageToken of the previous page."
+    after: String
+  ): MoviePage!
+  topRatedMovies(limit: Int! = 10, minVoteCount: Int! = 0): [Movie!]!
+  director(name: String!): Director!
+}
+
+"The order of a list of movies. Ties are broken by title."
+enum MovieSort {
+  TITLE
+  RATING
+  VOTES
+}
+
+type Movie {
+  id: ID!
+  "The metadata of the movie, or null if it is not found."
+  metadata: Metadata
+  "The rating of the movie, or null if it has no votes."
+  rating: Rating
+  director: Director
+}
+
+type Metadata {
+  id: ID!
+  title: String!
+  description: String!
+  director: String!
+  genres: [String!]!
+}
+
+"The aggregated rating of a movie."
+type Rating {
+  value: Float!
+  voteCount: Int!
+}
+
+type Director {
+  name: String!
+  "The first movies of the director."
+  movies(first: Int! = 20, sortBy: MovieSort! = TITLE): [Movie!]!
+}
+
+type MoviePage {
+  movies: [Movie!]!
+  "The token of the next page, or null on the last page."
+  nextPageToken: String
+}
+`
+
+// loaderWait is how long the loaders wait for more keys before fetching a
+// batch. The fields of the items of a list are resolved concurrently, so
+// their loads arrive within it.
+const loaderWait = time.Millisecond
+
+// loaders batch the metadata and rating calls of a request.
+type loaders struct {
+	metadata *dataloader.Loader[string, *metadatamodel.Metadata]
+	rating   *dataloader.Loader[string, ratingmodel.RatedRecord]
+}
+
+type loadersKey struct{}
+
+func newLoaders(ctrl *movie.Controller) *loaders {
+	return &loaders{
+		metadata: dataloader.New(ctrl.GetMetadataBatch, movie.MaxPageSize, loaderWait),
+		rating:   dataloader.New(ctrl.GetRatingBatch, movie.MaxPageSize, loaderWait),
+	}
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// movies caches the metadata and rating of listed movies and returns their
+// resolvers.
+func (l *loaders) movies(ctrl *movie.Controller, movies []model.RankedMovie) []*movieResolver {
+	res := make([]*movieResolver, len(movies))
+	for i, m := range movies {
+		l.metadata.Prime(m.Metadata.ID, &m.Metadata)
+		var r ratingmodel.RatedRecord
+		if m.VoteCount > 0 {
+			r = ratingmodel.RatedRecord{RecordID: ratingmodel.RecordID(m.Metadata.ID), RecordType: ratingmodel.RecordTypeMovie, Rating: m.Rating, VoteCount: m.VoteCount}
+		}
+		l.rating.Prime(m.Metadata.ID, r)
+		res[i] = &movieResolver{ctrl, m.Metadata.ID}
+	}
+	return res
+}
+
+func (l *loaders) loadMetadata(ctx context.Context, id string) (*metadatamodel.Metadata, error) {
+	m, err := l.metadata.Load(ctx, id)()
+	if err != nil {
+		return nil, userError(err)
+	}
+	return m, nil
+}
+
+// budget is the complexity a request can spend, or unlimited if max is zero.
+type budget struct {
+	max  int64
+	used atomic.Int64
+}
+
+type budgetKey struct{}
+
+// charge spends the cost of a field from the budget of the request. Fields
+// are charged before they call the controller, so that a request fails
+// before it costs more than its budget.
+func charge(ctx context.Context, cost int) error {
+	b := ctx.Value(budgetKey{}).(*budget)
+	if b.max > 0 && b.used.Add(int64(cost)) > b.max {
+		return fmt.Errorf("the query costs more than the maximum complexity of %d", b.max)
+	}
+	return nil
+}
+
+// selected returns the number of fields selected below the field being
+// resolved whose path starts with prefix.
+func selected(ctx context.Context, prefix string) int {
+	n := 0
+	for _, name := range graphql.SelectedFieldNames(ctx) {
+		if strings.HasPrefix(name, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+// pageSize returns the number of movies of a page of the given size, as
+// listed by the controller.
+func pageSize(n int32) int {
+	return min(max(int(n), 1), movie.MaxPageSize)
+}
+
+// sortBy returns the controller sort key of a MovieSort value.
+func sortBy(s string) movie.SortBy {
+	return movie.SortBy(strings.ToLower(s))
+}
+
+// userError returns the errors caused by the query as is, and logs and hides
+// the other controller errors.
+func userError(err error) error {
+	if errors.Is(err, movie.ErrInvalidSort) || errors.Is(err, movie.ErrInvalidPageToken) || errors.Is(err, movie.ErrTooManyMovies) {
+		return err
+	}
+	log.Printf("GraphQL resolver error: %v\n", err)
+	return errors.New("internal error")
+}
+
+// queryResolver resolves the Query fields with the controller.
+type queryResolver struct {
+	ctrl *movie.Controller
+}
+
+func (r *queryResolver) Movie(ctx context.Context, args struct{ ID graphql.ID }) (*movieResolver, error) {
+	if err := charge(ctx, 1+selected(ctx, "")); err != nil {
+		return nil, err
+	}
+	m, err := loadersFrom(ctx).loadMetadata(ctx, string(args.ID))
+	if err != nil || m == nil {
+		return nil, err
+	}
+	return &movieResolver{r.ctrl, m.ID}, nil
+}
+
+func (r *queryResolver) Movies(ctx context.Context, args struct {
+	Director *string
+	Genre    *string
+	SortBy   string
+	First    int32
+	After    *string
+}) (*moviePageResolver, error) {
+	n := pageSize(args.First)
+	if err := charge(ctx, 1+n*selected(ctx, "movies.")); err != nil {
+		return nil, err
+	}
+	opts := movie.ListOptions{SortBy: sortBy(args.SortBy), PageSize: n}
+	if args.Director != nil {
+		opts.Director = *args.Director
+	}
+	if args.Genre != nil {
+		opts.Genre = *args.Genre
+	}
+	if args.After != nil {
+		opts.PageToken = *args.After
+	}
+	movies, next, err := r.ctrl.List(ctx, opts)
+	if err != nil {
+		return nil, userError(err)
+	}
+	page := &moviePageResolver{movies: loadersFrom(ctx).movies(r.ctrl, movies)}
+	if next != "" {
+		page.next = &next
+	}
+	return page, nil
+}
+
+func (r *queryResolver) TopRatedMovies(ctx context.Context, args struct {
+	Limit        int32
+	MinVoteCount int32
+}) ([]*movieResolver, error) {
+	n := pageSize(args.Limit)
+	if err := charge(ctx, 1+n*selected(ctx, "")); err != nil {
+		return nil, err
+	}
+	movies, err := r.ctrl.GetTopRated(ctx, n, max(int(args.MinVoteCount), 0))
+	if err != nil {
+		return nil, userError(err)
+	}
+	return loadersFrom(ctx).movies(r.ctrl, movies), nil
+}
+
+func (r *queryResolver) Director(ctx context.Context, args struct{ Name string }) (*directorResolver, error) {
+	if err := charge(ctx, 1+selected(ctx, "")); err != nil {
+		return nil, err
+	}
+	return &directorResolver{r.ctrl, args.Name}, nil
+}
+
+// moviePageResolver resolves a page of movies.
+type moviePageResolver struct {
+	movies []*movieResolver
+	next   *string
+}
+
+func (r *moviePageResolver) Movies() []*movieResolver { return r.movies }
+func (r *moviePageResolver) NextPageToken() *string   { return r.next }
+
+// movieResolver resolves the fields of a movie with the request loaders.
+type movieResolver struct {
+	ctrl *movie.Controller
+	id   string
+}
+
+func (r *movieResolver) ID() graphql.ID { return graphql.ID(r.id) }
+
+func (r *movieResolver) Metadata(ctx context.Context) (*metadataResolver, error) {
+	m, err := loadersFrom(ctx).loadMetadata(ctx, r.id)
+	if err != nil || m == nil {
+		return nil, err
+	}
+	return &metadataResolver{m}, nil
+}
+
+func (r *movieResolver) Rating(ctx context.Context) (*ratingResolver, error) {
+	rating, err := loadersFrom(ctx).rating.Load(ctx, r.id)()
+	if err != nil {
+		return nil, userError(err)
+	} else if rating.VoteCount == 0 {
+		return nil, nil
+	}
+	return &ratingResolver{rating}, nil
+}
+
+func (r *movieResolver) Director(ctx context.Context) (*directorResolver, error) {
+	m, err := loadersFrom(ctx).loadMetadata(ctx, r.id)
+	if err != nil || m == nil {
+		return nil, err
+	}
+	return &directorResolver{r.ctrl, m.Director}, nil
+}
+
+// metadataResolver resolves movie metadata.
+type metadataResolver struct {
+	m *metadatamodel.Metadata
+}
+
+func (r *metadataResolver) ID() graphql.ID      { return graphql.ID(r.m.ID) }
+func (r *metadataResolver) Title() string       { return r.m.Title }
+func (r *metadataResolver) Description() string { return r.m.Description }
+func (r *metadataResolver) Director() string    { return r.m.Director }
+func (r *metadataResolver) Genres() []string    { return append([]string{}, r.m.Genres...) }
+
+// ratingResolver resolves the aggregated rating of a movie.
+type ratingResolver struct {
+	r ratingmodel.RatedRecord
+}
+
+func (r *ratingResolver) Value() float64   { return r.r.Rating }
+func (r *ratingResolver) VoteCount() int32 { return int32(r.r.VoteCount) }
+
+// directorResolver resolves a director and lists their movies.
+type directorResolver struct {
+	ctrl *movie.Controller
+	name string
+}
+
+func (r *directorResolver) Name() string { return r.name }
+
+func (r *directorResolver) Movies(ctx context.Context, args struct {
+	First  int32
+	SortBy string
+}) ([]*movieResolver, error) {
+	n := pageSize(args.First)
+	if err := charge(ctx, 1+n*selected(ctx, "")); err != nil {
+		return nil, err
+	}
+	movies, _, err := r.ctrl.List(ctx, movie.ListOptions{Director: r.name, SortBy: sortBy(args.SortBy), PageSize: n})
+	if err != nil {
+		return nil, userError(err)
+	}
+	return loadersFrom(ctx).movies(r.ctrl, movies), nil
+}
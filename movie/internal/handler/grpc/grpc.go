@@ -3,11 +3,14 @@ package grpc
 import (
 	"context"
 	"errors"
+	"strings"
 
 	"github.com/abhishek622/movieapp/gen"
-	"github.com/abhishek622/movieapp/metadata/pkg/model"
 	"github.com/abhishek622/movieapp/movie/internal/controller/movie"
+	moviemodel "github.com/abhishek622/movieapp/movie/pkg/model"
+	ratingmodel "github.com/abhishek622/movieapp/rating/pkg/model"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -22,7 +25,8 @@ func New(ctrl *movie.Controller) *Handler {
 	return &Handler{ctrl: ctrl}
 }
 
-// GetMovieDetails returns moviie details by id.
+// GetMovieDetails returns movie details by id. The status of every section
+// tells whether it could be fetched.
 func (h *Handler) GetMovieDetails(ctx context.Context, req *gen.GetMovieDetailsRequest) (*gen.GetMovieDetailsResponse, error) {
 	if req == nil || req.MovieId == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty id")
@@ -30,17 +34,107 @@ func (h *Handler) GetMovieDetails(ctx context.Context, req *gen.GetMovieDetailsR
 	m, err := h.ctrl.Get(ctx, req.MovieId)
 	if err != nil && errors.Is(err, movie.ErrNotFound) {
 		return nil, status.Error(codes.NotFound, err.Error())
+	} else if err != nil && errors.Is(err, movie.ErrUnavailable) {
+		return nil, status.Error(codes.Unavailable, err.Error())
 	} else if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
-	var rating float64
-	if m.Rating != nil {
-		rating = *m.Rating
+	return &gen.GetMovieDetailsResponse{MovieDetails: moviemodel.MovieDetailsToProto(m)}, nil
+}
+
+// GetTopRatedMovies returns the highest rated movies.
+func (h *Handler) GetTopRatedMovies(ctx context.Context, req *gen.GetTopRatedMoviesRequest) (*gen.GetTopRatedMoviesResponse, error) {
+	if req == nil || req.Limit < 0 || req.MinVoteCount < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or negative limit/min vote count")
+	}
+	movies, err := h.ctrl.GetTopRated(ctx, int(req.Limit), int(req.MinVoteCount))
+	if err != nil && errors.Is(err, movie.ErrUnavailable) {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	res := &gen.GetTopRatedMoviesResponse{}
+	for i := range movies {
+		res.Movies = append(res.Movies, moviemodel.RankedMovieToProto(&movies[i]))
+	}
+	return res, nil
+}
+
+// ListMovies returns a page of movies filtered by director and genre, sorted
+// by title, rating or vote count.
+func (h *Handler) ListMovies(ctx context.Context, req *gen.ListMoviesRequest) (*gen.ListMoviesResponse, error) {
+	if req == nil || req.PageSize < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or negative page size")
+	}
+	movies, next, err := h.ctrl.List(ctx, movie.ListOptions{
+		Director:  req.Director,
+		Genre:     req.Genre,
+		SortBy:    movie.SortBy(req.SortBy),
+		PageSize:  int(req.PageSize),
+		PageToken: req.PageToken,
+	})
+	if err != nil && (errors.Is(err, movie.ErrInvalidSort) || errors.Is(err, movie.ErrInvalidPageToken)) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil && errors.Is(err, movie.ErrTooManyMovies) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	} else if err != nil && errors.Is(err, movie.ErrUnavailable) {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	res := &gen.ListMoviesResponse{NextPageToken: next}
+	for i := range movies {
+		res.Movies = append(res.Movies, moviemodel.RankedMovieToProto(&movies[i]))
+	}
+	return res, nil
+}
+
+// GetRecordRating returns the ratings of a record of any type, such as an
+// episode or a series.
+func (h *Handler) GetRecordRating(ctx context.Context, req *gen.GetRecordRatingRequest) (*gen.GetRecordRatingResponse, error) {
+	if req == nil || req.RecordId == "" || req.RecordType == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty id/type")
+	}
+	r, err := h.ctrl.GetRating(ctx, ratingmodel.RecordID(req.RecordId), ratingmodel.RecordType(req.RecordType))
+	if err != nil && errors.Is(err, movie.ErrInvalidRecord) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil && errors.Is(err, movie.ErrUnavailable) {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &gen.GetRecordRatingResponse{RecordRating: moviemodel.RecordRatingToProto(r)}, nil
+}
+
+// RateMovie rates a movie as the user of the bearer token sent in the
+// authorization metadata of the call.
+func (h *Handler) RateMovie(ctx context.Context, req *gen.RateMovieRequest) (*gen.RateMovieResponse, error) {
+	if req == nil || req.MovieId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty id")
+	}
+	err := h.ctrl.Rate(ctx, bearerToken(ctx), req.MovieId, ratingmodel.RatingValue(req.RatingValue))
+	if err != nil && errors.Is(err, movie.ErrUnauthenticated) {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	} else if err != nil && errors.Is(err, movie.ErrInvalidRating) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil && errors.Is(err, movie.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	} else if err != nil && errors.Is(err, movie.ErrUnavailable) {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &gen.RateMovieResponse{}, nil
+}
+
+// bearerToken returns the token of the "authorization: Bearer <token>"
+// metadata of an incoming call, or an empty string.
+func bearerToken(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get("authorization") {
+		if token, ok := strings.CutPrefix(v, "Bearer "); ok {
+			return token
+		}
 	}
-	return &gen.GetMovieDetailsResponse{
-		MovieDetails: &gen.MovieDetails{
-			Metadata: model.MetadataToProto(&m.Metadata),
-			Rating:   rating,
-		},
-	}, nil
+	return ""
 }
@@ -1,12 +1,17 @@
 package http
 
 import (
-	"encoding/json"
 	"errors"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/abhishek622/movieapp/gen"
+	"github.com/abhishek622/movieapp/internal/httputil"
 	"github.com/abhishek622/movieapp/movie/internal/controller/movie"
+	"github.com/abhishek622/movieapp/movie/pkg/model"
+	ratingmodel "github.com/abhishek622/movieapp/rating/pkg/model"
 )
 
 type Handler struct {
@@ -21,16 +26,161 @@ func (h *Handler) GetMovieDetails(w http.ResponseWriter, req *http.Request) {
 	id := req.FormValue("id")
 	details, err := h.ctrl.Get(req.Context(), id)
 	if err != nil && errors.Is(err, movie.ErrNotFound) {
-		w.WriteHeader(http.StatusNotFound)
+		httputil.Error(w, req, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil && errors.Is(err, movie.ErrUnavailable) {
+		httputil.Error(w, req, http.StatusServiceUnavailable, err.Error())
 		return
 	} else if err != nil {
 		log.Printf("Repository get error: %v\n", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		httputil.Error(w, req, http.StatusInternalServerError, "")
+		return
+	}
+	httputil.Respond(w, req, "movieDetails", details, model.MovieDetailsToProto(details))
+}
+
+func (h *Handler) GetTopRatedMovies(w http.ResponseWriter, req *http.Request) {
+	limit, err := strconv.Atoi(req.FormValue("limit"))
+	if err != nil && req.FormValue("limit") != "" {
+		httputil.Error(w, req, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	minVoteCount, err := strconv.Atoi(req.FormValue("minVotes"))
+	if err != nil && req.FormValue("minVotes") != "" {
+		httputil.Error(w, req, http.StatusBadRequest, "invalid minVotes")
+		return
+	}
+	if limit < 0 || minVoteCount < 0 {
+		httputil.Error(w, req, http.StatusBadRequest, "negative limit or minVotes")
+		return
+	}
+	movies, err := h.ctrl.GetTopRated(req.Context(), limit, minVoteCount)
+	if err != nil && errors.Is(err, movie.ErrUnavailable) {
+		httputil.Error(w, req, http.StatusServiceUnavailable, err.Error())
+		return
+	} else if err != nil {
+		log.Printf("Top rated get error: %v\n", err)
+		httputil.Error(w, req, http.StatusInternalServerError, "")
+		return
+	}
+	msg := &gen.GetTopRatedMoviesResponse{}
+	for i := range movies {
+		msg.Movies = append(msg.Movies, model.RankedMovieToProto(&movies[i]))
+	}
+	httputil.Respond(w, req, "movies>movie", movies, msg)
+}
+
+// movieList is a page of movies returned by ListMovies.
+type movieList struct {
+	Movies        []model.RankedMovie `json:"movies" xml:"movie"`
+	NextPageToken string              `json:"nextPageToken,omitempty" xml:"nextPageToken,omitempty"`
+}
+
+// ListMovies handles GET /movies requests. The director and genre parameters
+// filter the movies, sortBy is one of title, rating and votes, and pageSize
+// and pageToken select a page.
+func (h *Handler) ListMovies(w http.ResponseWriter, req *http.Request) {
+	pageSize, err := strconv.Atoi(req.FormValue("pageSize"))
+	if (err != nil && req.FormValue("pageSize") != "") || pageSize < 0 {
+		httputil.Error(w, req, http.StatusBadRequest, "invalid pageSize")
+		return
+	}
+	movies, next, err := h.ctrl.List(req.Context(), movie.ListOptions{
+		Director:  req.FormValue("director"),
+		Genre:     req.FormValue("genre"),
+		SortBy:    movie.SortBy(req.FormValue("sortBy")),
+		PageSize:  pageSize,
+		PageToken: req.FormValue("pageToken"),
+	})
+	if err != nil && (errors.Is(err, movie.ErrInvalidSort) || errors.Is(err, movie.ErrInvalidPageToken) || errors.Is(err, movie.ErrTooManyMovies)) {
+		httputil.Error(w, req, http.StatusBadRequest, err.Error())
+		return
+	} else if err != nil && errors.Is(err, movie.ErrUnavailable) {
+		httputil.Error(w, req, http.StatusServiceUnavailable, err.Error())
+		return
+	} else if err != nil {
+		log.Printf("Movie list error: %v\n", err)
+		httputil.Error(w, req, http.StatusInternalServerError, "")
+		return
+	}
+	if movies == nil {
+		movies = []model.RankedMovie{}
+	}
+	msg := &gen.ListMoviesResponse{NextPageToken: next}
+	for i := range movies {
+		msg.Movies = append(msg.Movies, model.RankedMovieToProto(&movies[i]))
+	}
+	httputil.Respond(w, req, "movieList", movieList{Movies: movies, NextPageToken: next}, msg)
+}
+
+// GetRecordRating handles GET /rating requests for the ratings of a record of
+// any type, such as an episode or a series.
+func (h *Handler) GetRecordRating(w http.ResponseWriter, req *http.Request) {
+	recordID := ratingmodel.RecordID(req.FormValue("id"))
+	recordType := ratingmodel.RecordType(req.FormValue("type"))
+	if recordID == "" || recordType == "" {
+		httputil.Error(w, req, http.StatusBadRequest, "empty id or type")
+		return
+	}
+	rating, err := h.ctrl.GetRating(req.Context(), recordID, recordType)
+	if err != nil && errors.Is(err, movie.ErrInvalidRecord) {
+		httputil.Error(w, req, http.StatusBadRequest, err.Error())
+		return
+	} else if err != nil && errors.Is(err, movie.ErrUnavailable) {
+		httputil.Error(w, req, http.StatusServiceUnavailable, err.Error())
+		return
+	} else if err != nil {
+		log.Printf("Rating get error: %v\n", err)
+		httputil.Error(w, req, http.StatusInternalServerError, "")
 		return
 	}
+	httputil.Respond(w, req, "recordRating", rating, model.RecordRatingToProto(rating))
+}
 
-	if err := json.NewEncoder(w).Encode(details); err != nil {
-		log.Printf("Response encode error: %v\n", err)
-		w.WriteHeader(http.StatusInternalServerError)
+// RateMovie handles PUT /movie/rating requests rating the movie of the id
+// parameter with the value parameter, as the user of the bearer token of
+// the Authorization header. It responds with no content once the movie is
+// rated.
+func (h *Handler) RateMovie(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPut {
+		w.Header().Set("Allow", http.MethodPut)
+		httputil.Error(w, req, http.StatusMethodNotAllowed, "")
+		return
+	}
+	id := req.FormValue("id")
+	if id == "" {
+		httputil.Error(w, req, http.StatusBadRequest, "empty id")
+		return
+	}
+	v, err := strconv.Atoi(req.FormValue("value"))
+	if err != nil {
+		httputil.Error(w, req, http.StatusBadRequest, "invalid value")
+		return
+	}
+	var token string
+	if t, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer "); ok {
+		token = t
+	}
+	err = h.ctrl.Rate(req.Context(), token, id, ratingmodel.RatingValue(v))
+	if err != nil && errors.Is(err, movie.ErrUnauthenticated) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		httputil.Error(w, req, http.StatusUnauthorized, err.Error())
+	} else if err != nil && errors.Is(err, movie.ErrInvalidRating) {
+		httputil.Error(w, req, http.StatusBadRequest, err.Error())
+	} else if err != nil && errors.Is(err, movie.ErrNotFound) {
+		httputil.Error(w, req, http.StatusNotFound, err.Error())
+	} else if err != nil && errors.Is(err, movie.ErrUnavailable) {
+		httputil.Error(w, req, http.StatusServiceUnavailable, err.Error())
+	} else if err != nil {
+		log.Printf("Movie rate error: %v\n", err)
+		httputil.Error(w, req, http.StatusInternalServerError, "")
+	} else {
+		w.WriteHeader(http.StatusNoContent)
 	}
 }
+
+// CircuitBreakers handles GET /admin/circuitbreakers requests with the state
+// of the circuit breakers of the movie service dependencies.
+func (h *Handler) CircuitBreakers(w http.ResponseWriter, req *http.Request) {
+	httputil.Respond(w, req, "circuitBreakers>circuitBreaker", h.ctrl.CircuitBreakers(), nil)
+}
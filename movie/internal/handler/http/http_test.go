@@ -0,0 +1,146 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	metadatamodel "github.com/abhishek622/movieapp/metadata/pkg/model"
+	"github.com/abhishek622/movieapp/movie/internal/controller/movie"
+	"github.com/abhishek622/movieapp/movie/internal/gateway"
+	"github.com/abhishek622/movieapp/movie/pkg/model"
+	ratingmodel "github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRatingGateway rates movies with the average of the ratings put.
+type fakeRatingGateway struct {
+	mu      sync.Mutex
+	ratings map[ratingmodel.RecordID]map[ratingmodel.UserID]ratingmodel.RatingValue
+}
+
+func (g *fakeRatingGateway) GetAggregatedRating(_ context.Context, recordID ratingmodel.RecordID, _ ratingmodel.RecordType) (float64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.ratings[recordID]) == 0 {
+		return 0, gateway.ErrNotFound
+	}
+	var sum float64
+	for _, v := range g.ratings[recordID] {
+		sum += float64(v)
+	}
+	return sum / float64(len(g.ratings[recordID])), nil
+}
+
+func (g *fakeRatingGateway) GetRolledUpRating(ctx context.Context, recordID ratingmodel.RecordID, recordType ratingmodel.RecordType) (float64, error) {
+	return g.GetAggregatedRating(ctx, recordID, recordType)
+}
+
+func (g *fakeRatingGateway) GetTopRated(context.Context, ratingmodel.RecordType, int, int) ([]ratingmodel.RatedRecord, error) {
+	return nil, nil
+}
+
+func (g *fakeRatingGateway) GetAggregatedRatings(context.Context, []ratingmodel.RecordID, ratingmodel.RecordType) ([]ratingmodel.RatedRecord, error) {
+	return nil, nil
+}
+
+func (g *fakeRatingGateway) PutRating(_ context.Context, recordID ratingmodel.RecordID, _ ratingmodel.RecordType, rating *ratingmodel.Rating) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.ratings[recordID] == nil {
+		g.ratings[recordID] = map[ratingmodel.UserID]ratingmodel.RatingValue{}
+	}
+	g.ratings[recordID][rating.UserID] = rating.Value
+	return nil
+}
+
+// fakeMetadataGateway only finds the movie with id 1.
+type fakeMetadataGateway struct{}
+
+func (fakeMetadataGateway) Get(_ context.Context, id string) (*metadatamodel.Metadata, error) {
+	if id != "1" {
+		return nil, gateway.ErrNotFound
+	}
+	return &metadatamodel.Metadata{ID: id, Title: "Heat"}, nil
+}
+
+func (fakeMetadataGateway) GetBatch(context.Context, []string) ([]*metadatamodel.Metadata, error) {
+	return nil, nil
+}
+
+func (fakeMetadataGateway) List(context.Context, metadatamodel.Filter, int, string) ([]*metadatamodel.Metadata, string, error) {
+	return nil, "", nil
+}
+
+// fakeAuthGateway accepts the tokens of the form "token-<username>".
+type fakeAuthGateway struct{}
+
+func (fakeAuthGateway) ValidateToken(_ context.Context, token string) (string, error) {
+	username, ok := strings.CutPrefix(token, "token-")
+	if !ok {
+		return "", gateway.ErrUnauthenticated
+	}
+	return username, nil
+}
+
+func TestRateMovie(t *testing.T) {
+	ratings := &fakeRatingGateway{ratings: map[ratingmodel.RecordID]map[ratingmodel.UserID]ratingmodel.RatingValue{"1": {"bob": 2}}}
+	h := New(movie.New(ratings, fakeMetadataGateway{}, fakeAuthGateway{}, movie.Options{CacheTTL: time.Hour}))
+	rate := func(method, query, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/movie/rating?"+query, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		h.RateMovie(w, req)
+		return w
+	}
+	get := func() *model.MovieDetails {
+		w := httptest.NewRecorder()
+		h.GetMovieDetails(w, httptest.NewRequest(http.MethodGet, "/movie?id=1", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var details model.MovieDetails
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&details))
+		return &details
+	}
+
+	// The rating of the movie is cached until it is rated.
+	assert.Equal(t, 2.0, *get().Rating)
+	w := rate(http.MethodPut, "id=1&value=4", "token-alice")
+	require.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, ratingmodel.RatingValue(4), ratings.ratings["1"]["alice"])
+	assert.Equal(t, 3.0, *get().Rating)
+
+	tests := []struct {
+		name   string
+		method string
+		query  string
+		token  string
+		want   int
+	}{
+		{name: "no token", method: http.MethodPut, query: "id=1&value=4", want: http.StatusUnauthorized},
+		{name: "invalid token", method: http.MethodPut, query: "id=1&value=4", token: "forged", want: http.StatusUnauthorized},
+		{name: "out of range", method: http.MethodPut, query: "id=1&value=6", token: "token-alice", want: http.StatusBadRequest},
+		{name: "invalid value", method: http.MethodPut, query: "id=1&value=good", token: "token-alice", want: http.StatusBadRequest},
+		{name: "empty id", method: http.MethodPut, query: "value=4", token: "token-alice", want: http.StatusBadRequest},
+		{name: "unknown movie", method: http.MethodPut, query: "id=2&value=4", token: "token-alice", want: http.StatusNotFound},
+		{name: "get", method: http.MethodGet, query: "id=1&value=4", token: "token-alice", want: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := rate(tt.method, tt.query, tt.token)
+			assert.Equal(t, tt.want, w.Code)
+			if tt.want == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+	assert.Len(t, ratings.ratings["1"], 2)
+	assert.Empty(t, ratings.ratings["2"])
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"github.com/abhishek622/movieapp/gen"
+	"github.com/abhishek622/movieapp/metadata/pkg/model"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// MovieDetailsToProto converts a MovieDetails struct into a generated proto
+// counterpart. A missing rating is converted to 0.
+func MovieDetailsToProto(d *MovieDetails) *gen.MovieDetails {
+	p := &gen.MovieDetails{
+		Metadata:       model.MetadataToProto(&d.Metadata),
+		MetadataStatus: SectionStatusToProto(d.Status.Metadata),
+		RatingStatus:   SectionStatusToProto(d.Status.Rating),
+	}
+	if d.Rating != nil {
+		p.Rating = *d.Rating
+	}
+	return p
+}
+
+// RankedMovieToProto converts a RankedMovie struct into a generated proto counterpart.
+func RankedMovieToProto(m *RankedMovie) *gen.RankedMovie {
+	return &gen.RankedMovie{
+		Metadata:  model.MetadataToProto(&m.Metadata),
+		Rating:    m.Rating,
+		VoteCount: int32(m.VoteCount),
+	}
+}
+
+// RecordRatingToProto converts a RecordRating struct into a generated proto
+// counterpart. Missing ratings are left unset.
+func RecordRatingToProto(r *RecordRating) *gen.RecordRating {
+	p := &gen.RecordRating{RecordId: string(r.RecordID), RecordType: string(r.RecordType)}
+	if r.Rating != nil {
+		p.Rating = wrapperspb.Double(*r.Rating)
+	}
+	if r.RolledUpRating != nil {
+		p.RolledUpRating = wrapperspb.Double(*r.RolledUpRating)
+	}
+	return p
+}
+
+// SectionStatusToProto converts a SectionStatus into a generated proto counterpart.
+func SectionStatusToProto(s SectionStatus) gen.SectionStatus {
+	switch s {
+	case SectionStatusOK:
+		return gen.SectionStatus_SECTION_STATUS_OK
+	case SectionStatusNotFound:
+		return gen.SectionStatus_SECTION_STATUS_NOT_FOUND
+	case SectionStatusUnavailable:
+		return gen.SectionStatus_SECTION_STATUS_UNAVAILABLE
+	case SectionStatusStale:
+		return gen.SectionStatus_SECTION_STATUS_STALE
+	default:
+		return gen.SectionStatus_SECTION_STATUS_UNSPECIFIED
+	}
+}
@@ -1,8 +1,50 @@
 package model
 
-import "github.com/abhishek622/movieapp/metadata/pkg/model"
+import (
+	"github.com/abhishek622/movieapp/metadata/pkg/model"
+	ratingmodel "github.com/abhishek622/movieapp/rating/pkg/model"
+)
 
 type MovieDetails struct {
-	Rating   *float64       `json:"rating,omitempty"`
-	Metadata model.Metadata `json:"metadata"`
+	Rating   *float64       `json:"rating,omitempty" xml:"rating,omitempty"`
+	Metadata model.Metadata `json:"metadata" xml:"metadata"`
+	// Status tells which sections of the details could be fetched.
+	Status DetailsStatus `json:"status" xml:"status"`
+}
+
+// SectionStatus tells whether a section of a response could be fetched from
+// the service owning it.
+type SectionStatus string
+
+const (
+	SectionStatusOK       = SectionStatus("ok")
+	SectionStatusNotFound = SectionStatus("not_found")
+	// SectionStatusUnavailable means the section could not be fetched and is left out.
+	SectionStatusUnavailable = SectionStatus("unavailable")
+	// SectionStatusStale means the section could not be fetched and an older copy is returned.
+	SectionStatusStale = SectionStatus("stale")
+)
+
+// DetailsStatus holds the status of every section of movie details.
+type DetailsStatus struct {
+	Metadata SectionStatus `json:"metadata" xml:"metadata"`
+	Rating   SectionStatus `json:"rating" xml:"rating"`
+}
+
+// RankedMovie is a movie entry of a top rated list.
+type RankedMovie struct {
+	Rating    float64        `json:"rating" xml:"rating"`
+	VoteCount int            `json:"voteCount" xml:"voteCount"`
+	Metadata  model.Metadata `json:"metadata" xml:"metadata"`
+}
+
+// RecordRating holds the ratings of a rated record of any type.
+type RecordRating struct {
+	RecordID   ratingmodel.RecordID   `json:"recordId" xml:"recordId"`
+	RecordType ratingmodel.RecordType `json:"recordType" xml:"recordType"`
+	// Rating is the aggregated rating of the record itself.
+	Rating *float64 `json:"rating,omitempty" xml:"rating,omitempty"`
+	// RolledUpRating is the aggregated rating of the child records of the
+	// record, such as the episodes of a series.
+	RolledUpRating *float64 `json:"rolledUpRating,omitempty" xml:"rolledUpRating,omitempty"`
 }
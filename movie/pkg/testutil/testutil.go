@@ -2,7 +2,9 @@ package testutil
 
 import (
 	"github.com/abhishek622/movieapp/gen"
+	"github.com/abhishek622/movieapp/internal/grpcutil"
 	"github.com/abhishek622/movieapp/movie/internal/controller/movie"
+	authgateway "github.com/abhishek622/movieapp/movie/internal/gateway/auth/grpc"
 	metadatagateway "github.com/abhishek622/movieapp/movie/internal/gateway/metadata/grpc"
 	ratinggateway "github.com/abhishek622/movieapp/movie/internal/gateway/rating/grpc"
 	grpchandler "github.com/abhishek622/movieapp/movie/internal/handler/grpc"
@@ -11,9 +13,14 @@ import (
 )
 
 // NewTestMovieGRPCServer creates a new movie gRPC server to be used in tests.
-func NewTestMovieGRPCServer(registry discovery.Registry) gen.MovieServiceServer {
-	metadataGateway := metadatagateway.New(registry, insecure.NewCredentials())
-	ratingGateway := ratinggateway.New(registry, insecure.NewCredentials())
-	ctrl := movie.New(ratingGateway, metadataGateway)
-	return grpchandler.New(ctrl)
+// Ratings are sent with auth tokens validated by the auth service at authAddr.
+// The returned function closes the connections of the server to the metadata
+// and rating services, and must be called once it is stopped.
+func NewTestMovieGRPCServer(registry discovery.Registry, authAddr string) (gen.MovieServiceServer, func() error) {
+	pool := grpcutil.NewPool(registry, insecure.NewCredentials(), grpcutil.PoolOptions{})
+	metadataGateway := metadatagateway.New(pool)
+	ratingGateway := ratinggateway.New(pool)
+	authGateway := authgateway.New(authAddr, insecure.NewCredentials())
+	ctrl := movie.New(ratingGateway, metadataGateway, authGateway, movie.Options{})
+	return grpchandler.New(ctrl), pool.Close
 }
@@ -26,25 +26,52 @@ func NewRegistry(addr string) (*Registry, error) {
 	return &Registry{client: client}, nil
 }
 
-// create a service record in registry
-func (r *Registry) Register(ctx context.Context, instanceID string, serviceName string, hostPort string) error {
+// create a service record in registry. The endpoints are advertised as tagged
+// addresses, with their protocols in the service metadata, and the first
+// endpoint is the address of the service.
+func (r *Registry) Register(ctx context.Context, instanceID string, serviceName string, endpoints []discovery.Endpoint) error {
+	if len(endpoints) == 0 {
+		return errors.New("service instance has no endpoints")
+	}
+
+	addrs := map[string]consul.ServiceAddress{}
+	meta := map[string]string{}
+	for _, e := range endpoints {
+		addr, err := serviceAddress(e.HostPort)
+		if err != nil {
+			return fmt.Errorf("endpoint %s: %w", e.Name, err)
+		}
+		addrs[e.Name] = addr
+		meta[protocolMetaKey(e.Name)] = e.Protocol
+	}
+
+	return r.client.Agent().ServiceRegister(&consul.AgentServiceRegistration{
+		Address:         addrs[endpoints[0].Name].Address,
+		ID:              instanceID,
+		Name:            serviceName,
+		Port:            addrs[endpoints[0].Name].Port,
+		TaggedAddresses: addrs,
+		Meta:            meta,
+		Check:           &consul.AgentServiceCheck{CheckID: instanceID, TTL: "5s"},
+	})
+}
+
+func serviceAddress(hostPort string) (consul.ServiceAddress, error) {
 	parts := strings.Split(hostPort, ":")
 	if len(parts) != 2 {
-		return errors.New("hostPort must be in a form of <host>:<port>")
+		return consul.ServiceAddress{}, errors.New("hostPort must be in a form of <host>:<port>")
 	}
 
 	port, err := strconv.Atoi(parts[1])
 	if err != nil {
-		return err
+		return consul.ServiceAddress{}, err
 	}
+	return consul.ServiceAddress{Address: parts[0], Port: port}, nil
+}
 
-	return r.client.Agent().ServiceRegister(&consul.AgentServiceRegistration{
-		Address: parts[0],
-		ID:      instanceID,
-		Name:    serviceName,
-		Port:    port,
-		Check:   &consul.AgentServiceCheck{CheckID: instanceID, TTL: "5s"},
-	})
+// protocolMetaKey returns the service metadata key of the protocol of an endpoint.
+func protocolMetaKey(endpointName string) string {
+	return "endpoint-" + endpointName + "-protocol"
 }
 
 // remove record from registry
@@ -52,8 +79,23 @@ func (r *Registry) Deregister(ctx context.Context, instanceID string, _ string)
 	return r.client.Agent().ServiceDeregister(instanceID)
 }
 
-// list addresses of active instances of the given service
-func (r *Registry) ServiceAddresses(ctx context.Context, serviceName string) ([]string, error) {
+// list addresses of the named endpoint of active instances of the given service
+func (r *Registry) ServiceAddresses(ctx context.Context, serviceName string, endpointName string) ([]string, error) {
+	endpoints, err := r.ServiceEndpoints(ctx, serviceName, endpointName)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]string, 0, len(endpoints))
+	for _, e := range endpoints {
+		res = append(res, e.HostPort)
+	}
+	return res, nil
+}
+
+// list the named endpoint of active instances of the given service, with the
+// protocol in their service metadata
+func (r *Registry) ServiceEndpoints(ctx context.Context, serviceName string, endpointName string) ([]discovery.Endpoint, error) {
 	entries, _, err := r.client.Health().Service(serviceName, "", true, nil)
 	if err != nil {
 		return nil, err
@@ -61,9 +103,18 @@ func (r *Registry) ServiceAddresses(ctx context.Context, serviceName string) ([]
 		return nil, discovery.ErrNotFound
 	}
 
-	var res []string
+	var res []discovery.Endpoint
 	for _, e := range entries {
-		res = append(res, fmt.Sprintf("%s:%d", e.Service.Address, e.Service.Port))
+		if addr, ok := e.Service.TaggedAddresses[endpointName]; ok {
+			res = append(res, discovery.Endpoint{
+				Name:     endpointName,
+				Protocol: e.Service.Meta[protocolMetaKey(endpointName)],
+				HostPort: fmt.Sprintf("%s:%d", addr.Address, addr.Port),
+			})
+		}
+	}
+	if len(res) == 0 {
+		return nil, discovery.ErrNotFound
 	}
 
 	return res, nil
@@ -0,0 +1,99 @@
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/abhishek622/movieapp/pkg/discovery"
+	"github.com/abhishek622/movieapp/pkg/discovery/discoverytest"
+	consul "github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAgent is a Consul agent that keeps the registered services in memory
+// and reports them all as healthy.
+type testAgent struct {
+	mu       sync.Mutex
+	services map[string]*consul.AgentServiceRegistration
+}
+
+func (a *testAgent) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	switch {
+	case req.Method == http.MethodPut && req.URL.Path == "/v1/agent/service/register":
+		var reg consul.AgentServiceRegistration
+		if err := json.NewDecoder(req.Body).Decode(&reg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		a.services[reg.ID] = &reg
+	case req.Method == http.MethodPut && strings.HasPrefix(req.URL.Path, "/v1/agent/service/deregister/"):
+		delete(a.services, strings.TrimPrefix(req.URL.Path, "/v1/agent/service/deregister/"))
+	case req.Method == http.MethodGet && strings.HasPrefix(req.URL.Path, "/v1/health/service/"):
+		entries := []*consul.ServiceEntry{}
+		for _, reg := range a.services {
+			if reg.Name != strings.TrimPrefix(req.URL.Path, "/v1/health/service/") {
+				continue
+			}
+			entries = append(entries, &consul.ServiceEntry{Service: &consul.AgentService{
+				ID:              reg.ID,
+				Service:         reg.Name,
+				Address:         reg.Address,
+				Port:            reg.Port,
+				TaggedAddresses: reg.TaggedAddresses,
+				Meta:            reg.Meta,
+			}})
+		}
+		json.NewEncoder(w).Encode(entries)
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+// newTestRegistry creates a registry of a test agent.
+func newTestRegistry(t *testing.T) (*Registry, *testAgent) {
+	t.Helper()
+	agent := &testAgent{services: map[string]*consul.AgentServiceRegistration{}}
+	srv := httptest.NewServer(agent)
+	t.Cleanup(srv.Close)
+	r, err := NewRegistry(strings.TrimPrefix(srv.URL, "http://"))
+	require.NoError(t, err)
+	return r, agent
+}
+
+func TestRegistry(t *testing.T) {
+	r, _ := newTestRegistry(t)
+	discoverytest.TestRegistry(t, r)
+}
+
+func TestRegister(t *testing.T) {
+	ctx := context.Background()
+	r, agent := newTestRegistry(t)
+	require.NoError(t, r.Register(ctx, "rating-1", "rating", []discovery.Endpoint{
+		{Name: discovery.EndpointGRPC, Protocol: "grpc", HostPort: "localhost:8082"},
+		{Name: discovery.EndpointMetrics, Protocol: "http", HostPort: "localhost:8092"},
+	}))
+
+	// The endpoints are tagged addresses with their protocols in the service
+	// metadata, and the first endpoint is the address of the service.
+	reg := agent.services["rating-1"]
+	require.NotNil(t, reg)
+	assert.Equal(t, 8082, reg.Port)
+	assert.Equal(t, map[string]consul.ServiceAddress{
+		"grpc":    {Address: "localhost", Port: 8082},
+		"metrics": {Address: "localhost", Port: 8092},
+	}, reg.TaggedAddresses)
+	assert.Equal(t, map[string]string{
+		"endpoint-grpc-protocol":    "grpc",
+		"endpoint-metrics-protocol": "http",
+	}, reg.Meta)
+
+	assert.Error(t, r.Register(ctx, "rating-2", "rating", []discovery.Endpoint{{Name: discovery.EndpointGRPC, HostPort: "localhost"}}))
+}
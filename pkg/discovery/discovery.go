@@ -8,15 +8,37 @@ import (
 	"time"
 )
 
+// Names of the endpoints advertised by service instances.
+const (
+	EndpointGRPC    = "grpc"
+	EndpointHTTP    = "http"
+	EndpointMetrics = "metrics"
+)
+
+// Endpoint is a named address of a service instance, such as its gRPC API,
+// its HTTP API or its metrics.
+type Endpoint struct {
+	Name string
+	// Protocol is the protocol served at the address, such as grpc or http.
+	Protocol string
+	HostPort string
+}
+
 type Registry interface {
-	// Register create service instance record in the registry
-	Register(ctx context.Context, instanceID string, serviceName string, hostPort string) error
+	// Register create service instance record in the registry, advertising
+	// the endpoints of the instance
+	Register(ctx context.Context, instanceID string, serviceName string, endpoints []Endpoint) error
 
 	// Deregister removes a service instance record from registry
 	Deregister(ctx context.Context, instanceID string, serviceName string) error
 
-	// ServiceAddresses returns the list of addresses of active instances of the given service
-	ServiceAddresses(ctx context.Context, servicerID string) ([]string, error)
+	// ServiceAddresses returns the list of addresses of the named endpoint of
+	// active instances of the given service
+	ServiceAddresses(ctx context.Context, serviceName string, endpointName string) ([]string, error)
+
+	// ServiceEndpoints returns the named endpoint of active instances of the
+	// given service, with the protocol they advertise
+	ServiceEndpoints(ctx context.Context, serviceName string, endpointName string) ([]Endpoint, error)
 
 	// ReportHealthyState is a push mechanism for reporting healthy state to the registry
 	ReportHealthyState(instanceID string, serviceName string) error
@@ -0,0 +1,61 @@
+// Package discoverytest tests the behavior shared by the implementations of
+// discovery.Registry.
+package discoverytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/abhishek622/movieapp/pkg/discovery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRegistry registers instances of a rating service in an empty registry
+// and checks the endpoints and addresses resolved for them.
+func TestRegistry(t *testing.T, r discovery.Registry) {
+	ctx := context.Background()
+	grpc1 := discovery.Endpoint{Name: discovery.EndpointGRPC, Protocol: "grpc", HostPort: "localhost:8082"}
+	http1 := discovery.Endpoint{Name: discovery.EndpointHTTP, Protocol: "http", HostPort: "localhost:9082"}
+	grpc2 := discovery.Endpoint{Name: discovery.EndpointGRPC, Protocol: "grpc", HostPort: "localhost:8182"}
+	require.NoError(t, r.Register(ctx, "rating-1", "rating", []discovery.Endpoint{grpc1, http1}))
+	require.NoError(t, r.Register(ctx, "rating-2", "rating", []discovery.Endpoint{grpc2}))
+
+	tests := []struct {
+		name     string
+		service  string
+		endpoint string
+		want     []discovery.Endpoint
+	}{
+		{name: "every instance", service: "rating", endpoint: discovery.EndpointGRPC, want: []discovery.Endpoint{grpc1, grpc2}},
+		// Instances without the endpoint are skipped.
+		{name: "some instances", service: "rating", endpoint: discovery.EndpointHTTP, want: []discovery.Endpoint{http1}},
+		{name: "no instance", service: "rating", endpoint: discovery.EndpointMetrics},
+		{name: "unknown service", service: "metadata", endpoint: discovery.EndpointGRPC},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints, err := r.ServiceEndpoints(ctx, tt.service, tt.endpoint)
+			addrs, addrsErr := r.ServiceAddresses(ctx, tt.service, tt.endpoint)
+			if tt.want == nil {
+				assert.ErrorIs(t, err, discovery.ErrNotFound)
+				assert.ErrorIs(t, addrsErr, discovery.ErrNotFound)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, addrsErr)
+			assert.ElementsMatch(t, tt.want, endpoints)
+			var want []string
+			for _, e := range tt.want {
+				want = append(want, e.HostPort)
+			}
+			assert.ElementsMatch(t, want, addrs)
+		})
+	}
+
+	require.NoError(t, r.Deregister(ctx, "rating-1", "rating"))
+	_, err := r.ServiceEndpoints(ctx, "rating", discovery.EndpointHTTP)
+	assert.ErrorIs(t, err, discovery.ErrNotFound)
+
+	assert.Error(t, r.Register(ctx, "rating-3", "rating", nil))
+}
@@ -16,7 +16,7 @@ type Registry struct {
 }
 
 type serviceInstance struct {
-	hostPort   string
+	endpoints  map[string]discovery.Endpoint
 	lastActive time.Time
 }
 
@@ -24,14 +24,21 @@ func NewRegistry() *Registry {
 	return &Registry{serviceAddrs: map[string]map[string]*serviceInstance{}}
 }
 
-func (r *Registry) Register(ctx context.Context, instanceID string, serviceName string, hostPort string) error {
+func (r *Registry) Register(ctx context.Context, instanceID string, serviceName string, endpoints []discovery.Endpoint) error {
+	if len(endpoints) == 0 {
+		return errors.New("service instance has no endpoints")
+	}
 	r.Lock()
 	defer r.Unlock()
 	if _, ok := r.serviceAddrs[serviceName]; !ok {
 		r.serviceAddrs[serviceName] = map[string]*serviceInstance{}
 	}
 
-	r.serviceAddrs[serviceName][instanceID] = &serviceInstance{hostPort: hostPort, lastActive: time.Now()}
+	i := &serviceInstance{endpoints: map[string]discovery.Endpoint{}, lastActive: time.Now()}
+	for _, e := range endpoints {
+		i.endpoints[e.Name] = e
+	}
+	r.serviceAddrs[serviceName][instanceID] = i
 	return nil
 }
 
@@ -59,21 +66,39 @@ func (r *Registry) ReportHealthyState(instanceID string, serviceName string) err
 	return nil
 }
 
-func (r *Registry) ServiceAddresses(ctx context.Context, serviceName string) ([]string, error) {
+func (r *Registry) ServiceAddresses(ctx context.Context, serviceName string, endpointName string) ([]string, error) {
+	endpoints, err := r.ServiceEndpoints(ctx, serviceName, endpointName)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]string, 0, len(endpoints))
+	for _, e := range endpoints {
+		res = append(res, e.HostPort)
+	}
+	return res, nil
+}
+
+func (r *Registry) ServiceEndpoints(ctx context.Context, serviceName string, endpointName string) ([]discovery.Endpoint, error) {
 	r.RLock()
 	defer r.RUnlock()
 	if len(r.serviceAddrs[serviceName]) == 0 {
 		return nil, discovery.ErrNotFound
 	}
 
-	var res []string
+	var res []discovery.Endpoint
 	for instanceID, i := range r.serviceAddrs[serviceName] {
 		if i.lastActive.Before(time.Now().Add(-5 * time.Second)) {
 			log.Println("Instance " + instanceID + " of service " + serviceName + " is not active, skipping")
 			continue
 		}
 
-		res = append(res, i.hostPort)
+		if e, ok := i.endpoints[endpointName]; ok {
+			res = append(res, e)
+		}
+	}
+	if len(res) == 0 {
+		return nil, discovery.ErrNotFound
 	}
 	return res, nil
 
@@ -0,0 +1,32 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/abhishek622/movieapp/pkg/discovery"
+	"github.com/abhishek622/movieapp/pkg/discovery/discoverytest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	discoverytest.TestRegistry(t, NewRegistry())
+}
+
+func TestInactiveInstances(t *testing.T) {
+	ctx := context.Background()
+	r := NewRegistry()
+	assert.Error(t, r.ReportHealthyState("rating-1", "rating"))
+	require.NoError(t, r.Register(ctx, "rating-1", "rating", []discovery.Endpoint{{Name: discovery.EndpointGRPC, Protocol: "grpc", HostPort: "localhost:8082"}}))
+
+	// Instances that do not report their healthy state are skipped.
+	r.serviceAddrs["rating"]["rating-1"].lastActive = time.Now().Add(-time.Minute)
+	_, err := r.ServiceAddresses(ctx, "rating", discovery.EndpointGRPC)
+	assert.ErrorIs(t, err, discovery.ErrNotFound)
+	require.NoError(t, r.ReportHealthyState("rating-1", "rating"))
+	addrs, err := r.ServiceAddresses(ctx, "rating", discovery.EndpointGRPC)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"localhost:8082"}, addrs)
+}
@@ -0,0 +1,75 @@
+package schemaregistry
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// CheckCompatibility verifies that data written with either schema can be
+// read with the other one, both in protobuf and in the protobuf JSON mapping:
+//   - a field number kept in both schemas keeps its name, type and cardinality,
+//   - a removed field has its number and name reserved,
+//   - a reserved number or name is not used again.
+func CheckCompatibility(prev, next protoreflect.MessageDescriptor) error {
+	if prev.FullName() != next.FullName() {
+		return fmt.Errorf("%w: message %s renamed to %s", ErrIncompatible, prev.FullName(), next.FullName())
+	}
+	return checkFields(prev, next, map[protoreflect.FullName]bool{})
+}
+
+func checkFields(prev, next protoreflect.MessageDescriptor, checked map[protoreflect.FullName]bool) error {
+	if checked[prev.FullName()] {
+		return nil
+	}
+	checked[prev.FullName()] = true
+
+	prevFields, nextFields := prev.Fields(), next.Fields()
+	for i := 0; i < prevFields.Len(); i++ {
+		of := prevFields.Get(i)
+		nf := nextFields.ByNumber(of.Number())
+		if nf == nil {
+			if !next.ReservedRanges().Has(of.Number()) || !next.ReservedNames().Has(of.Name()) {
+				return fmt.Errorf("%w: removed field %s must reserve number %d and name %q", ErrIncompatible, of.FullName(), of.Number(), of.Name())
+			}
+			continue
+		}
+		if err := checkField(of, nf); err != nil {
+			return err
+		}
+		if of.Kind() == protoreflect.MessageKind || of.Kind() == protoreflect.GroupKind {
+			if err := checkFields(of.Message(), nf.Message(), checked); err != nil {
+				return err
+			}
+		}
+	}
+	for i := 0; i < nextFields.Len(); i++ {
+		nf := nextFields.Get(i)
+		if prevFields.ByNumber(nf.Number()) != nil {
+			continue
+		}
+		if prev.ReservedRanges().Has(nf.Number()) || prev.ReservedNames().Has(nf.Name()) {
+			return fmt.Errorf("%w: field %s reuses a reserved number or name", ErrIncompatible, nf.FullName())
+		}
+		if of := prevFields.ByJSONName(nf.JSONName()); of != nil {
+			return fmt.Errorf("%w: field %s reuses the JSON name of field number %d", ErrIncompatible, nf.FullName(), of.Number())
+		}
+	}
+	return nil
+}
+
+func checkField(prev, next protoreflect.FieldDescriptor) error {
+	switch {
+	case prev.Name() != next.Name() || prev.JSONName() != next.JSONName():
+		return fmt.Errorf("%w: field %d renamed from %s to %s", ErrIncompatible, prev.Number(), prev.Name(), next.Name())
+	case prev.Kind() != next.Kind():
+		return fmt.Errorf("%w: field %s changed type from %s to %s", ErrIncompatible, prev.FullName(), prev.Kind(), next.Kind())
+	case prev.Cardinality() != next.Cardinality() || prev.IsMap() != next.IsMap():
+		return fmt.Errorf("%w: field %s changed cardinality", ErrIncompatible, prev.FullName())
+	case prev.Kind() == protoreflect.MessageKind && prev.Message().FullName() != next.Message().FullName():
+		return fmt.Errorf("%w: field %s changed type from %s to %s", ErrIncompatible, prev.FullName(), prev.Message().FullName(), next.Message().FullName())
+	case prev.Kind() == protoreflect.EnumKind && prev.Enum().FullName() != next.Enum().FullName():
+		return fmt.Errorf("%w: field %s changed type from %s to %s", ErrIncompatible, prev.FullName(), prev.Enum().FullName(), next.Enum().FullName())
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package schemaregistry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+var (
+	// ErrNotFound is returned when a subject or a version is not registered.
+	ErrNotFound = errors.New("schema not found")
+	// ErrIncompatible is returned when a schema is not compatible with a registered version.
+	ErrIncompatible = errors.New("incompatible schema")
+)
+
+// Registry is a local stand-in for a schema registry. Each subject is stored
+// as a JSON file holding all registered versions of a protobuf message schema.
+// A schema can only be registered if it is compatible with all previous
+// versions in both directions, so that producers and consumers can be
+// upgraded in any order.
+type Registry struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// New creates a registry storing subjects in dir.
+func New(dir string) *Registry {
+	return &Registry{dir: dir}
+}
+
+type subjectFile struct {
+	Subject  string        `json:"subject"`
+	Versions []versionFile `json:"versions"`
+}
+
+type versionFile struct {
+	Version int    `json:"version"`
+	Message string `json:"message"`
+	// Schema is the file descriptor set of the message in protobuf JSON.
+	Schema json.RawMessage `json:"schema"`
+}
+
+// Register registers the schema of a message under subject and returns its
+// version. Registering a schema identical to a registered version returns
+// that version. It returns ErrIncompatible if the schema is not compatible with
+// a registered version.
+func (r *Registry) Register(subject string, md protoreflect.MessageDescriptor) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f, err := r.read(subject)
+	if errors.Is(err, ErrNotFound) {
+		f = &subjectFile{Subject: subject}
+	} else if err != nil {
+		return 0, err
+	}
+	schema, err := protojson.Marshal(fileDescriptorSet(md))
+	if err != nil {
+		return 0, err
+	}
+	for _, v := range f.Versions {
+		old, err := v.descriptor()
+		if err != nil {
+			return 0, err
+		}
+		if v.Message == string(md.FullName()) && equalSchemas(v.Schema, schema) {
+			return v.Version, nil
+		}
+		if err := CheckCompatibility(old, md); err != nil {
+			return 0, fmt.Errorf("version %d: %w", v.Version, err)
+		}
+	}
+	version := len(f.Versions) + 1
+	f.Versions = append(f.Versions, versionFile{Version: version, Message: string(md.FullName()), Schema: schema})
+	if err := r.write(f); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// Get returns a registered version of a subject.
+func (r *Registry) Get(subject string, version int) (protoreflect.MessageDescriptor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f, err := r.read(subject)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range f.Versions {
+		if v.Version == version {
+			return v.descriptor()
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Check verifies that md is compatible with every registered version of a
+// subject. A subject without versions is compatible with any schema.
+func (r *Registry) Check(subject string, md protoreflect.MessageDescriptor) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f, err := r.read(subject)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, v := range f.Versions {
+		old, err := v.descriptor()
+		if err != nil {
+			return err
+		}
+		if err := CheckCompatibility(old, md); err != nil {
+			return fmt.Errorf("version %d: %w", v.Version, err)
+		}
+	}
+	return nil
+}
+
+func (r *Registry) path(subject string) string {
+	return filepath.Join(r.dir, subject+".json")
+}
+
+func (r *Registry) read(subject string) (*subjectFile, error) {
+	b, err := os.ReadFile(r.path(subject))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	var f subjectFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// write atomically replaces the file of a subject.
+func (r *Registry) write(f *subjectFile) error {
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := r.path(f.Subject) + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.path(f.Subject))
+}
+
+func (v versionFile) descriptor() (protoreflect.MessageDescriptor, error) {
+	var set descriptorpb.FileDescriptorSet
+	if err := protojson.Unmarshal(v.Schema, &set); err != nil {
+		return nil, err
+	}
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, err
+	}
+	d, err := files.FindDescriptorByName(protoreflect.FullName(v.Message))
+	if err != nil {
+		return nil, err
+	}
+	md, ok := d.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a message", v.Message)
+	}
+	return md, nil
+}
+
+// fileDescriptorSet returns the file of a message and all its dependencies,
+// dependencies first.
+func fileDescriptorSet(md protoreflect.MessageDescriptor) *descriptorpb.FileDescriptorSet {
+	set := &descriptorpb.FileDescriptorSet{}
+	seen := map[string]bool{}
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if seen[fd.Path()] {
+			return
+		}
+		seen[fd.Path()] = true
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(fd))
+	}
+	add(md.ParentFile())
+	return set
+}
+
+func equalSchemas(a, b []byte) bool {
+	var x, y descriptorpb.FileDescriptorSet
+	if protojson.Unmarshal(a, &x) != nil || protojson.Unmarshal(b, &y) != nil {
+		return false
+	}
+	return proto.Equal(&x, &y)
+}
@@ -0,0 +1,113 @@
+package schemaregistry
+
+import (
+	"testing"
+
+	"github.com/abhishek622/movieapp/gen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// ratingEvent returns the RatingEvent schema changed by fn.
+func ratingEvent(t *testing.T, fn func(*descriptorpb.DescriptorProto)) protoreflect.MessageDescriptor {
+	md := (&gen.RatingEvent{}).ProtoReflect().Descriptor()
+	fdp := proto.Clone(protodesc.ToFileDescriptorProto(md.ParentFile())).(*descriptorpb.FileDescriptorProto)
+	fn(fdp.MessageType[0])
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	require.NoError(t, err)
+	return fd.Messages().ByName(md.Name())
+}
+
+func removeField(m *descriptorpb.DescriptorProto, name string) {
+	for i, f := range m.Field {
+		if f.GetName() == name {
+			m.Field = append(m.Field[:i], m.Field[i+1:]...)
+			return
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	r := New(t.TempDir())
+	const subject = "ratings-value"
+	current := ratingEvent(t, func(*descriptorpb.DescriptorProto) {})
+
+	v, err := r.Register(subject, current)
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+	v, err = r.Register(subject, current)
+	require.NoError(t, err)
+	assert.Equal(t, 1, v, "registering the same schema again returns its version")
+
+	tests := []struct {
+		name    string
+		change  func(*descriptorpb.DescriptorProto)
+		wantErr error
+	}{
+		{
+			name: "removed field without reservation",
+			change: func(m *descriptorpb.DescriptorProto) {
+				removeField(m, "provider_id")
+			},
+			wantErr: ErrIncompatible,
+		},
+		{
+			name: "changed field type",
+			change: func(m *descriptorpb.DescriptorProto) {
+				m.Field[6].Type = descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()
+			},
+			wantErr: ErrIncompatible,
+		},
+		{
+			name: "renamed field",
+			change: func(m *descriptorpb.DescriptorProto) {
+				m.Field[6].Name = proto.String("score")
+				m.Field[6].JsonName = proto.String("score")
+			},
+			wantErr: ErrIncompatible,
+		},
+		{
+			name: "added field",
+			change: func(m *descriptorpb.DescriptorProto) {
+				m.Field = append(m.Field, &descriptorpb.FieldDescriptorProto{
+					Name:     proto.String("comment"),
+					JsonName: proto.String("comment"),
+					Number:   proto.Int32(9),
+					Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.Check(subject, ratingEvent(t, tt.change))
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	// Removing a field is compatible once its number and name are reserved.
+	reserved := ratingEvent(t, func(m *descriptorpb.DescriptorProto) {
+		removeField(m, "provider_id")
+		m.ReservedRange = append(m.ReservedRange, &descriptorpb.DescriptorProto_ReservedRange{Start: proto.Int32(2), End: proto.Int32(3)})
+		m.ReservedName = append(m.ReservedName, "provider_id")
+	})
+	v, err = r.Register(subject, reserved)
+	require.NoError(t, err)
+	assert.Equal(t, 2, v)
+	got, err := New(r.dir).Get(subject, 2)
+	require.NoError(t, err)
+	assert.Nil(t, got.Fields().ByName("provider_id"))
+
+	// A reserved number cannot be used again.
+	_, err = r.Register(subject, ratingEvent(t, func(m *descriptorpb.DescriptorProto) {
+		m.Field[1].Name = proto.String("source_id")
+		m.Field[1].JsonName = proto.String("sourceId")
+	}))
+	assert.ErrorIs(t, err, ErrIncompatible)
+}
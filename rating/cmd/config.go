@@ -1,14 +1,32 @@
 package main
 
+import (
+	"time"
+
+	"github.com/abhishek622/movieapp/rating/internal/eventlog/eventlogutil"
+	"github.com/abhishek622/movieapp/rating/internal/provider"
+)
+
 type config struct {
 	API              apiConfig              `yaml:"api"`
 	ServiceDiscovery serviceDiscoveryConfig `yaml:"serviceDiscovery"`
+	Auth             authConfig             `yaml:"auth"`
+	Reviews          reviewsConfig          `yaml:"reviews"`
 	Jaeger           jaegerConfig           `yaml:"jaeger"`
 	Prometheus       prometheusConfig       `yaml:"prometheus"`
+	Admin            adminConfig            `yaml:"admin"`
+	Ingester         ingesterConfig         `yaml:"ingester"`
+	EventSourcing    eventSourcingConfig    `yaml:"eventSourcing"`
+	Providers        providersConfig        `yaml:"providers"`
 }
 
 type apiConfig struct {
-	Port int `yaml:"port"`
+	// Port and HTTPPort are the ports of the gRPC and HTTP APIs.
+	Port     int `yaml:"port"`
+	HTTPPort int `yaml:"httpPort"`
+	// HTTPWrites enables PUT and DELETE /rating on the HTTP API, which does
+	// not authenticate its clients. The gRPC API requires client certificates.
+	HTTPWrites bool `yaml:"httpWrites"`
 }
 
 type serviceDiscoveryConfig struct {
@@ -19,6 +37,17 @@ type consulConfig struct {
 	Address string `yaml:"address"`
 }
 
+type authConfig struct {
+	// Address is the address of the auth service, which is not registered in
+	// service discovery.
+	Address string `yaml:"address"`
+}
+
+type reviewsConfig struct {
+	// Moderators are the users allowed to moderate reviews.
+	Moderators []string `yaml:"moderators"`
+}
+
 type jaegerConfig struct {
 	Host string `yaml:"host"`
 	Port string `yaml:"port"`
@@ -27,3 +56,96 @@ type jaegerConfig struct {
 type prometheusConfig struct {
 	MetricsPort int `yaml:"metricsPort"`
 }
+
+type adminConfig struct {
+	// Address is the address of the admin endpoints, which are not served if
+	// it is unset. It should not be reachable by the clients of the service.
+	Address string `yaml:"address"`
+}
+
+type ingesterConfig struct {
+	// Type is one of kafka, file or none. Ingestion is disabled if it is empty.
+	Type  string              `yaml:"type"`
+	Kafka kafkaIngesterConfig `yaml:"kafka"`
+	File  fileIngesterConfig  `yaml:"file"`
+	// Workers, QueueSize, BatchSize and BatchWait tune the ingestion worker
+	// pool. Defaults are used for unset values.
+	Workers   int           `yaml:"workers"`
+	QueueSize int           `yaml:"queueSize"`
+	BatchSize int           `yaml:"batchSize"`
+	BatchWait time.Duration `yaml:"batchWait"`
+}
+
+type kafkaIngesterConfig struct {
+	Address string `yaml:"address"`
+	GroupID string `yaml:"groupId"`
+	Topic   string `yaml:"topic"`
+	// DeadLetterTopic receives the messages that cannot be processed.
+	DeadLetterTopic string `yaml:"deadLetterTopic"`
+	// SchemaRegistry is the directory of the local schema registry. Schema
+	// versions of messages are not checked if it is empty.
+	SchemaRegistry string `yaml:"schemaRegistry"`
+}
+
+type fileIngesterConfig struct {
+	Path string `yaml:"path"`
+}
+
+type eventSourcingConfig struct {
+	// Enabled makes the event log the source of truth for ratings. The
+	// repository is rebuilt from the log when the service starts.
+	Enabled bool `yaml:"enabled"`
+	// Log is the event log type, file or kafka.
+	Log   string              `yaml:"log"`
+	File  fileEventLogConfig  `yaml:"file"`
+	Kafka kafkaEventLogConfig `yaml:"kafka"`
+	// Snapshot is the snapshot file the repository is rebuilt from. The whole
+	// log is replayed if it is empty or the file does not exist.
+	Snapshot string `yaml:"snapshot"`
+}
+
+func (c eventSourcingConfig) options() eventlogutil.Options {
+	return eventlogutil.Options{Type: c.Log, FilePath: c.File.Path, KafkaAddress: c.Kafka.Address, KafkaTopic: c.Kafka.Topic}
+}
+
+type fileEventLogConfig struct {
+	Path string `yaml:"path"`
+}
+
+type kafkaEventLogConfig struct {
+	Address string `yaml:"address"`
+	Topic   string `yaml:"topic"`
+}
+
+type providersConfig struct {
+	// Default applies to the providers that are not listed.
+	Default providerConfig   `yaml:"default"`
+	List    []providerConfig `yaml:"list"`
+}
+
+type providerConfig struct {
+	ID string `yaml:"id"`
+	// Weight is the trust weight of the ratings of the provider, 1 if unset.
+	Weight *float64 `yaml:"weight"`
+	// Enabled tells whether the ratings of the provider are aggregated, true if unset.
+	Enabled *bool `yaml:"enabled"`
+}
+
+func (c providerConfig) provider() provider.Provider {
+	p := provider.Provider{ID: c.ID, Weight: 1, Enabled: true}
+	if c.Weight != nil {
+		p.Weight = *c.Weight
+	}
+	if c.Enabled != nil {
+		p.Enabled = *c.Enabled
+	}
+	return p
+}
+
+func (c providersConfig) registry() *provider.Registry {
+	var providers []provider.Provider
+	for _, p := range c.List {
+		providers = append(providers, p.provider())
+	}
+	return provider.NewRegistry(c.Default.provider(), providers...)
+}
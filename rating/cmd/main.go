@@ -15,13 +15,24 @@ import (
 	"time"
 
 	"github.com/abhishek622/movieapp/gen"
+	"github.com/abhishek622/movieapp/internal/grpcutil"
 	"github.com/abhishek622/movieapp/pkg/discovery"
 	"github.com/abhishek622/movieapp/pkg/discovery/consul"
+	"github.com/abhishek622/movieapp/pkg/schemaregistry"
 	"github.com/abhishek622/movieapp/pkg/tracing"
 	"github.com/abhishek622/movieapp/rating/internal/controller/rating"
+	"github.com/abhishek622/movieapp/rating/internal/controller/review"
+	"github.com/abhishek622/movieapp/rating/internal/eventlog"
+	"github.com/abhishek622/movieapp/rating/internal/eventlog/eventlogutil"
+	authgateway "github.com/abhishek622/movieapp/rating/internal/gateway/auth/grpc"
 	grpchandler "github.com/abhishek622/movieapp/rating/internal/handler/grpc"
 	httphandler "github.com/abhishek622/movieapp/rating/internal/handler/http"
+	"github.com/abhishek622/movieapp/rating/internal/ingester"
+	"github.com/abhishek622/movieapp/rating/internal/ingester/file"
+	"github.com/abhishek622/movieapp/rating/internal/ingester/kafka"
 	"github.com/abhishek622/movieapp/rating/internal/repository/memory"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/abhishek622/movieapp/rating/pkg/ratingevent"
 	"github.com/opentracing/opentracing-go"
 	"github.com/uber-go/tally/v4"
 	"github.com/uber-go/tally/v4/prometheus"
@@ -111,7 +122,14 @@ func main() {
 
 	// --- Service registration / health heartbeat ---
 	instanceID := discovery.GenerateInstanceID(serviceName)
-	if err := registry.Register(ctx, instanceID, serviceName, fmt.Sprintf("localhost:%d", port)); err != nil {
+	endpoints := []discovery.Endpoint{
+		{Name: discovery.EndpointGRPC, Protocol: "grpc", HostPort: fmt.Sprintf("localhost:%d", port)},
+		{Name: discovery.EndpointHTTP, Protocol: "http", HostPort: fmt.Sprintf("localhost:%d", cfg.API.HTTPPort)},
+	}
+	if cfg.Prometheus.MetricsPort != 0 {
+		endpoints = append(endpoints, discovery.Endpoint{Name: discovery.EndpointMetrics, Protocol: "http", HostPort: fmt.Sprintf("localhost:%d", cfg.Prometheus.MetricsPort)})
+	}
+	if err := registry.Register(ctx, instanceID, serviceName, endpoints); err != nil {
 		logger.Fatal("Failed to report healthy state", zap.Error(err))
 	}
 	go func() {
@@ -126,9 +144,44 @@ func main() {
 
 	// --- gRPC server (mTLS) ---
 	repo := memory.New()
-	ctrl := rating.New(repo, nil)
-	h := grpchandler.New(ctrl)
-	httpHandler := httphandler.New(ctrl)
+	ingester, err := newIngester(cfg.Ingester, scope, logger)
+	if err != nil {
+		logger.Fatal("Failed to create rating ingester", zap.Error(err))
+	}
+	providers := cfg.Providers.registry()
+	var ctrl *rating.Controller
+	if cfg.EventSourcing.Enabled {
+		eventLog, closeLog, err := eventlogutil.Open(cfg.EventSourcing.options())
+		if err != nil {
+			logger.Fatal("Failed to open rating event log", zap.Error(err))
+		}
+		defer closeLog()
+		ctrl = rating.NewEventSourced(repo, ingester, providers, eventLog)
+		snapshot, err := eventlog.ReadSnapshot(cfg.EventSourcing.Snapshot)
+		if err != nil {
+			logger.Fatal("Failed to read rating snapshot", zap.Error(err))
+		}
+		pos, err := ctrl.Rebuild(ctx, snapshot)
+		if err != nil {
+			logger.Fatal("Failed to rebuild ratings from the event log", zap.Error(err))
+		}
+		logger.Info("Rebuilt ratings from the event log", zap.String("log", cfg.EventSourcing.Log), zap.Bool("snapshot", snapshot != nil), zap.Any("position", pos))
+	} else {
+		ctrl = rating.New(repo, ingester, providers)
+	}
+	httpHandler := httphandler.New(ctrl, cfg.API.HTTPWrites)
+	// The ingestion status is inspected on the admin address, which only
+	// listens on localhost unless configured otherwise.
+	if cfg.Admin.Address != "" {
+		adminMux := http.NewServeMux()
+		adminMux.HandleFunc("/admin/ingestion", httpHandler.IngestionStatus)
+		go func() {
+			logger.Info("Starting admin server", zap.String("addr", cfg.Admin.Address))
+			if err := http.ListenAndServe(cfg.Admin.Address, adminMux); err != nil {
+				logger.Error("Admin server error", zap.Error(err))
+			}
+		}()
+	}
 	serverCert, err := tls.LoadX509KeyPair("configs/rating-cert.pem", "configs/rating-key.pem")
 	if err != nil {
 		logger.Fatal("Failed to load server certificate and key", zap.Error(err))
@@ -147,13 +200,21 @@ func main() {
 		ClientAuth:   tls.RequireAndVerifyClientCert,
 		MinVersion:   tls.VersionTLS13,
 	}
+	authGateway := authgateway.New(cfg.Auth.Address, credentials.NewTLS(&tls.Config{RootCAs: certPool, MinVersion: tls.VersionTLS13}))
+	var moderators []model.UserID
+	for _, m := range cfg.Reviews.Moderators {
+		moderators = append(moderators, model.UserID(m))
+	}
+	h := grpchandler.New(ctrl, review.New(repo, authGateway, moderators))
 
 	// Start HTTP server
 	go func() {
 		httpMux := http.NewServeMux()
 		httpMux.HandleFunc("/rating", httpHandler.Handle)
+		httpMux.HandleFunc("/rating/top", httpHandler.GetTopRated)
+		httpMux.HandleFunc("/rating/batch", httpHandler.GetBatch)
 		httpServer := &http.Server{
-			Addr:    fmt.Sprintf("localhost:%d", port+1000), // HTTP on port+1000
+			Addr:    fmt.Sprintf("localhost:%d", cfg.API.HTTPPort),
 			Handler: httpMux,
 		}
 		logger.Info("Starting HTTP server", zap.String("addr", httpServer.Addr))
@@ -169,6 +230,7 @@ func main() {
 	srv := grpc.NewServer(
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 		grpc.Creds(credentials.NewTLS(tlsConfig)),
+		grpcutil.KeepaliveEnforcementPolicy(),
 	)
 	reflection.Register(srv)
 	gen.RegisterRatingServiceServer(srv, h)
@@ -177,6 +239,29 @@ func main() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 	var wg sync.WaitGroup
+	if ingester != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.Info("Starting rating ingestion", zap.String("type", cfg.Ingester.Type))
+			opts := rating.IngestOptions{
+				Workers:   cfg.Ingester.Workers,
+				QueueSize: cfg.Ingester.QueueSize,
+				BatchSize: cfg.Ingester.BatchSize,
+				BatchWait: cfg.Ingester.BatchWait,
+				Scope:     scope,
+				Logger:    logger,
+				// Events ingested from the Kafka event log are already in it.
+				FromEventLog: cfg.Ingester.Type == "kafka" && cfg.EventSourcing.Log == "kafka" &&
+					cfg.Ingester.Kafka.Address == cfg.EventSourcing.Kafka.Address && cfg.Ingester.Kafka.Topic == cfg.EventSourcing.Kafka.Topic,
+			}
+			if err := ctrl.StartIngestion(ctx, opts); err != nil {
+				logger.Error("Rating ingestion failed", zap.Error(err))
+				return
+			}
+			logger.Info("Rating ingestion stopped")
+		}()
+	}
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -186,8 +271,9 @@ func main() {
 		// Shutdown Jaeger tracer
 		logger.Info("Jaeger tracer shutdown completed")
 
-		// Then cancel context and stop gRPC server
+		// Then cancel context to stop ingestion, close watch streams and stop gRPC server
 		cancel()
+		ctrl.Shutdown()
 		srv.GracefulStop()
 		logger.Info("Graceful stopped the gRPC server")
 	}()
@@ -197,3 +283,33 @@ func main() {
 
 	wg.Wait()
 }
+
+type ratingIngester interface {
+	Ingest(ctx context.Context) (chan ingester.Message, error)
+}
+
+// newIngester creates the rating ingester selected in the configuration or
+// returns nil if ingestion is disabled.
+func newIngester(cfg ingesterConfig, scope tally.Scope, logger *zap.Logger) (ratingIngester, error) {
+	switch cfg.Type {
+	case "", "none":
+		return nil, nil
+	case "kafka":
+		var registry *schemaregistry.Registry
+		if cfg.Kafka.SchemaRegistry != "" {
+			registry = schemaregistry.New(cfg.Kafka.SchemaRegistry)
+			if err := registry.Check(ratingevent.Subject, ratingevent.Descriptor()); err != nil {
+				return nil, err
+			}
+		}
+		ingester, err := kafka.NewIngester(cfg.Kafka.Address, cfg.Kafka.GroupID, cfg.Kafka.Topic, cfg.Kafka.DeadLetterTopic, registry, scope, logger)
+		if err != nil {
+			return nil, err
+		}
+		return ingester, nil
+	case "file":
+		return file.NewIngester(cfg.File.Path, logger), nil
+	default:
+		return nil, fmt.Errorf("unsupported ingester type %q", cfg.Type)
+	}
+}
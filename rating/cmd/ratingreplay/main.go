@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/abhishek622/movieapp/rating/internal/controller/rating"
+	"github.com/abhishek622/movieapp/rating/internal/eventlog"
+	"github.com/abhishek622/movieapp/rating/internal/eventlog/eventlogutil"
+	"github.com/abhishek622/movieapp/rating/internal/repository/memory"
+	"github.com/abhishek622/movieapp/rating/internal/repository/mysql"
+)
+
+func main() {
+	logType := flag.String("log", "file", "event log type: file or kafka")
+	logFile := flag.String("file", "ratings.log", "event log file")
+	brokers := flag.String("brokers", "localhost:9092", "Kafka bootstrap servers")
+	topic := flag.String("topic", "ratings", "event log topic")
+	target := flag.String("target", "mysql", "projection to rebuild: mysql or memory")
+	fromSnapshot := flag.String("from-snapshot", "", "snapshot to start from, the log is replayed from offset zero if empty")
+	writeSnapshot := flag.String("write-snapshot", "", "file to write a snapshot of the rebuilt projection to")
+	flag.Parse()
+
+	var snapshot *eventlog.Snapshot
+	if *fromSnapshot != "" {
+		s, err := eventlog.ReadSnapshot(*fromSnapshot)
+		if err != nil {
+			log.Fatalf("cannot read snapshot: %v", err)
+		}
+		if s == nil {
+			log.Fatalf("snapshot %s does not exist", *fromSnapshot)
+		}
+		snapshot = s
+		fmt.Printf("Starting from snapshot %s with %d ratings at %v\n", *fromSnapshot, len(s.Ratings), s.Position)
+	}
+
+	eventLog, closeLog, err := eventlogutil.Open(eventlogutil.Options{Type: *logType, FilePath: *logFile, KafkaAddress: *brokers, KafkaTopic: *topic})
+	if err != nil {
+		log.Fatalf("cannot open event log: %v", err)
+	}
+	defer closeLog()
+	var ctrl *rating.Controller
+	switch *target {
+	case "mysql":
+		repo, err := mysql.New()
+		if err != nil {
+			log.Fatalf("cannot connect to MySQL: %v", err)
+		}
+		ctrl = rating.NewEventSourced(repo, nil, nil, eventLog)
+	case "memory":
+		ctrl = rating.NewEventSourced(memory.New(), nil, nil, eventLog)
+	default:
+		log.Fatalf("unsupported target %q", *target)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	pos, err := ctrl.Rebuild(ctx, snapshot)
+	if err != nil {
+		log.Fatalf("replay failed: %v", err)
+	}
+	fmt.Printf("Rebuilt the %s projection up to %v\n", *target, pos)
+
+	if *writeSnapshot != "" {
+		s, err := ctrl.Snapshot(ctx, pos)
+		if err != nil {
+			log.Fatalf("cannot create snapshot: %v", err)
+		}
+		if err := eventlog.WriteSnapshot(*writeSnapshot, s); err != nil {
+			log.Fatalf("cannot write snapshot: %v", err)
+		}
+		fmt.Printf("Wrote a snapshot with %d ratings to %s\n", len(s.Ratings), *writeSnapshot)
+	}
+}
@@ -4,65 +4,280 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/abhishek622/movieapp/rating/internal/ingester"
+	"github.com/abhishek622/movieapp/rating/internal/provider"
 	"github.com/abhishek622/movieapp/rating/internal/repository"
 	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/abhishek622/movieapp/rating/pkg/recordtype"
 )
 
-// ErrNotFound is returned when no ratings are found for a record.
-var ErrNotFound = errors.New("ratings not found for a record")
+var (
+	// ErrNotFound is returned when no ratings are found for a record.
+	ErrNotFound = errors.New("ratings not found for a record")
+	// ErrInvalidRecord is returned when a record type is not registered or a
+	// record id is not valid for its type.
+	ErrInvalidRecord = errors.New("invalid record")
+)
+
+// OwnProviderID is the provider id of the ratings written through the rating service API.
+const OwnProviderID = "rating"
+
+const (
+	// DefaultTopRatedLimit is the number of records returned by GetTopRated when no limit is set.
+	DefaultTopRatedLimit = 10
+	// MaxTopRatedLimit is the maximum number of records returned by GetTopRated.
+	MaxTopRatedLimit = 100
+	// MaxBatchSize is the maximum number of records whose ratings are
+	// aggregated by a single GetAggregatedRatings call.
+	MaxBatchSize = 100
+)
 
 type ratingRepository interface {
 	Get(ctx context.Context, recordID model.RecordID, recordType model.RecordType) ([]model.Rating, error)
+	GetByPrefix(ctx context.Context, recordType model.RecordType, prefix string) ([]model.Rating, error)
+	GetBatch(ctx context.Context, recordType model.RecordType, recordIDs []model.RecordID) ([]model.Rating, error)
 	Put(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) error
+	PutBatch(ctx context.Context, ratings []model.Rating) error
+	Delete(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID, providerID string, deletedAt time.Time) error
+	Rebuild(ctx context.Context, build func(repository.Projection) error) error
+	GetTopRated(ctx context.Context, recordType model.RecordType, limit int, minVoteCount int, weights repository.ProviderWeights) ([]model.RatedRecord, error)
+	Export(ctx context.Context, recordType model.RecordType, since time.Time, until time.Time, after *model.RatingKey, fn func(*model.Rating) error) error
+	Tombstones(ctx context.Context, fn func(*model.Tombstone) error) error
 }
 
 type ratingIngester interface {
-	Ingest(ctx context.Context) (chan model.RatingEvent, error)
+	Ingest(ctx context.Context) (chan ingester.Message, error)
 }
 
 // Controller defines a rating service controller.
 type Controller struct {
 	repo     ratingRepository
 	ingester ratingIngester
+	watchers *watchHub
+	dedup    *dedupStore
+	// providers weigh the ratings of each provider in aggregated ratings.
+	providers *provider.Registry
+	// log is the event log in event-sourcing mode, or nil.
+	log eventLog
 }
 
-// New creates a rating service controller.
-func New(repo ratingRepository, ingester ratingIngester) *Controller {
-	return &Controller{repo, ingester}
+// New creates a rating service controller. Ratings of all providers weigh the
+// same if providers is nil.
+func New(repo ratingRepository, ingester ratingIngester, providers *provider.Registry) *Controller {
+	if providers == nil {
+		providers = provider.Default()
+	}
+	return &Controller{repo, ingester, newWatchHub(), newDedupStore(dedupCapacity), providers, nil}
 }
 
 // GetAggregatedRating returns the aggregated rating for a record or ErrNotFound if there are no ratings for it.
 func (c *Controller) GetAggregatedRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType) (float64, error) {
+	agg, err := c.GetAggregatedRatingByProvider(ctx, recordID, recordType)
+	if err != nil {
+		return 0, err
+	}
+	return agg.Rating, nil
+}
+
+// GetAggregatedRatingByProvider returns the aggregated rating for a record
+// with the aggregated rating of every provider, or ErrNotFound if there are no
+// ratings for it and ErrInvalidRecord if the record does not match its type.
+// Ratings are weighted by the trust weight of their provider and ratings of
+// disabled providers are skipped.
+func (c *Controller) GetAggregatedRatingByProvider(ctx context.Context, recordID model.RecordID, recordType model.RecordType) (*model.AggregatedRating, error) {
+	if err := validateRecord(recordID, recordType); err != nil {
+		return nil, err
+	}
 	ratings, err := c.repo.Get(ctx, recordID, recordType)
 	if err != nil && err == repository.ErrNotFound {
-		return 0, ErrNotFound
+		return nil, ErrNotFound
 	} else if err != nil {
-		return 0, err
+		return nil, err
+	}
+	return c.aggregate(ratings)
+}
+
+// GetRolledUpRating returns the aggregated rating of the child records of a
+// record, such as the episodes of a series, with the aggregated rating of
+// every provider. Every rating of a child record counts the same, so children
+// with more ratings weigh more. It returns ErrNotFound if the child records
+// have no ratings.
+func (c *Controller) GetRolledUpRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType) (*model.AggregatedRating, error) {
+	if err := validateRecord(recordID, recordType); err != nil {
+		return nil, err
+	}
+	children := recordtype.Children(recordType)
+	if len(children) == 0 {
+		return nil, fmt.Errorf("%w: %s records have no child records", ErrInvalidRecord, recordType)
 	}
-	sum := float64(0)
+	var ratings []model.Rating
+	for _, t := range children {
+		r, err := c.repo.GetByPrefix(ctx, t.Name, recordtype.ChildPrefix(recordID))
+		if err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, r...)
+	}
+	return c.aggregate(ratings)
+}
+
+// GetAggregatedRatings returns the aggregated ratings of several records of a
+// given type, in the order of recordIDs, with a single repository lookup.
+// Records without ratings are skipped. At most MaxBatchSize records can be
+// requested at once.
+func (c *Controller) GetAggregatedRatings(ctx context.Context, recordIDs []model.RecordID, recordType model.RecordType) ([]model.RatedRecord, error) {
+	if len(recordIDs) > MaxBatchSize {
+		return nil, fmt.Errorf("%w: more than %d records requested", ErrInvalidRecord, MaxBatchSize)
+	}
+	for _, id := range recordIDs {
+		if err := validateRecord(id, recordType); err != nil {
+			return nil, err
+		}
+	}
+	ratings, err := c.repo.GetBatch(ctx, recordType, recordIDs)
+	if err != nil {
+		return nil, err
+	}
+	byRecord := map[model.RecordID][]model.Rating{}
+	for _, r := range ratings {
+		byRecord[model.RecordID(r.RecordID)] = append(byRecord[model.RecordID(r.RecordID)], r)
+	}
+	var res []model.RatedRecord
+	for _, id := range recordIDs {
+		ratings, ok := byRecord[id]
+		if !ok {
+			continue
+		}
+		// Duplicate ids are returned once.
+		delete(byRecord, id)
+		agg, err := c.aggregate(ratings)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		res = append(res, model.RatedRecord{RecordID: id, RecordType: recordType, Rating: agg.Rating, VoteCount: agg.VoteCount})
+	}
+	return res, nil
+}
+
+// aggregate returns the weighted aggregated rating of ratings, or ErrNotFound
+// if no rating has weight.
+func (c *Controller) aggregate(ratings []model.Rating) (*model.AggregatedRating, error) {
+	byProvider := map[string]*model.ProviderRating{}
 	for _, r := range ratings {
-		sum += float64(r.Value)
+		pr, ok := byProvider[r.ProviderID]
+		if !ok {
+			p := c.providers.Get(r.ProviderID)
+			pr = &model.ProviderRating{ProviderID: r.ProviderID, Weight: p.Weight, Enabled: p.Enabled}
+			byProvider[r.ProviderID] = pr
+		}
+		pr.Rating += float64(r.Value)
+		pr.VoteCount++
+	}
+	agg := &model.AggregatedRating{}
+	var sum, weights float64
+	for _, pr := range byProvider {
+		if pr.Enabled && pr.Weight > 0 {
+			sum += pr.Weight * pr.Rating
+			weights += pr.Weight * float64(pr.VoteCount)
+			agg.VoteCount += pr.VoteCount
+		}
+		pr.Rating /= float64(pr.VoteCount)
+		agg.Providers = append(agg.Providers, *pr)
 	}
-	return sum / float64(len(ratings)), nil
+	if weights == 0 {
+		return nil, ErrNotFound
+	}
+	agg.Rating = sum / weights
+	slices.SortFunc(agg.Providers, func(a, b model.ProviderRating) int { return strings.Compare(a.ProviderID, b.ProviderID) })
+	return agg, nil
 }
 
-// PutRating writes a rating for a given record.
+// PutRating writes the rating of a user for a given record and notifies the
+// watchers of the record. A rating replaces the previous rating of the same
+// user unless the previous one was updated later. It returns ErrInvalidRecord
+// if the record is not valid for its type.
 func (c *Controller) PutRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) error {
-	return c.repo.Put(ctx, recordID, recordType, rating)
+	if err := validateRecord(recordID, recordType); err != nil {
+		return err
+	}
+	if rating.UpdatedAt.IsZero() {
+		rating.UpdatedAt = time.Now().UTC()
+	}
+	if rating.ProviderID == "" {
+		rating.ProviderID = OwnProviderID
+	}
+	key, err := c.record(ctx, model.RatingEventTypePut, recordID, recordType, rating)
+	if err != nil {
+		return err
+	}
+	if err := c.repo.Put(ctx, recordID, recordType, rating); err != nil {
+		return err
+	}
+	c.applied(key)
+	c.notifyWatchers(ctx, recordID, recordType)
+	return nil
 }
 
-// StartIngestion starts the ingestion of rating events.
-func (s *Controller) StartIngestion(ctx context.Context) error {
-	ch, err := s.ingester.Ingest(ctx)
+// DeleteRating removes the rating of a user for a given record and notifies
+// the watchers of the record. Only the rating written through the rating
+// service API is removed, the ratings of the user from other providers are kept. Deleting a rating that does not exist is not an
+// error, deleting the rating of an invalid record is.
+func (c *Controller) DeleteRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID) error {
+	if err := validateRecord(recordID, recordType); err != nil {
+		return err
+	}
+	deletedAt := time.Now().UTC()
+	key, err := c.record(ctx, model.RatingEventTypeDelete, recordID, recordType, &model.Rating{UserID: userID, UpdatedAt: deletedAt, ProviderID: OwnProviderID})
 	if err != nil {
 		return err
 	}
-	for e := range ch {
-		fmt.Printf("Consumed a message: %v\n", e)
-		if err := s.PutRating(ctx, model.RecordID(e.RecordID), model.RecordType(e.RecordType), &model.Rating{UserID: e.UserID, Value: e.Value}); err != nil {
-			return err
-		}
+	if err := c.repo.Delete(ctx, recordID, recordType, userID, OwnProviderID, deletedAt); err != nil {
+		return err
+	}
+	c.applied(key)
+	c.notifyWatchers(ctx, recordID, recordType)
+	return nil
+}
+
+// validateRecord checks the record against the record type registry.
+func validateRecord(recordID model.RecordID, recordType model.RecordType) error {
+	if err := recordtype.Validate(recordType, recordID); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRecord, err)
 	}
 	return nil
 }
+
+// GetTopRated returns the records of a given type with the highest aggregated
+// rating. Ratings are weighted by the trust weight of their provider, as in
+// GetAggregatedRating, and records with less than minVoteCount ratings of
+// enabled providers are skipped.
+func (c *Controller) GetTopRated(ctx context.Context, recordType model.RecordType, limit int, minVoteCount int) ([]model.RatedRecord, error) {
+	if limit <= 0 {
+		limit = DefaultTopRatedLimit
+	} else if limit > MaxTopRatedLimit {
+		limit = MaxTopRatedLimit
+	}
+	return c.repo.GetTopRated(ctx, recordType, limit, minVoteCount, c.providerWeights())
+}
+
+// providerWeights returns the weights of the providers in aggregated ratings.
+// Disabled providers weigh zero.
+func (c *Controller) providerWeights() repository.ProviderWeights {
+	weight := func(p provider.Provider) float64 {
+		if !p.Enabled || p.Weight <= 0 {
+			return 0
+		}
+		return p.Weight
+	}
+	w := repository.ProviderWeights{Default: weight(c.providers.Unregistered()), Weights: map[string]float64{}}
+	for _, p := range c.providers.List() {
+		w.Weights[p.ID] = weight(p)
+	}
+	return w
+}
@@ -0,0 +1,316 @@
+package rating
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	ingester "github.com/abhishek622/movieapp/rating/internal/ingester/memory"
+	"github.com/abhishek622/movieapp/rating/internal/provider"
+	"github.com/abhishek622/movieapp/rating/internal/repository/memory"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally/v4"
+)
+
+func TestWatchAggregatedRating(t *testing.T) {
+	ctx := context.Background()
+	c := New(memory.New(), nil, nil)
+	const recordID = model.RecordID("1")
+	put := func(userID model.UserID, v model.RatingValue) {
+		require.NoError(t, c.PutRating(ctx, recordID, model.RecordTypeMovie, &model.Rating{UserID: userID, Value: v}))
+	}
+	receive := func(ch <-chan float64) float64 {
+		select {
+		case v := <-ch:
+			return v
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for an update")
+			return 0
+		}
+	}
+
+	updates, err := c.WatchAggregatedRating(ctx, recordID, model.RecordTypeMovie, WatchOptions{MinInterval: 100 * time.Millisecond, Coalesce: true})
+	require.NoError(t, err)
+	put("user1", 5)
+	assert.Equal(t, float64(5), receive(updates))
+
+	// Both changes happen within the minimum interval and are coalesced into one update.
+	put("user2", 1)
+	put("user3", 0)
+	assert.Equal(t, float64(2), receive(updates))
+
+	c.Shutdown()
+	_, ok := <-updates
+	assert.False(t, ok, "updates channel must be closed on shutdown")
+	_, err = c.WatchAggregatedRating(ctx, recordID, model.RecordTypeMovie, WatchOptions{})
+	assert.ErrorIs(t, err, ErrShutdown)
+}
+
+func TestGetAggregatedRatingByProvider(t *testing.T) {
+	ctx := context.Background()
+	providers := provider.NewRegistry(provider.Provider{Weight: 1, Enabled: true},
+		provider.Provider{ID: "partner", Weight: 0.5, Enabled: true},
+		provider.Provider{ID: "spam", Weight: 1, Enabled: false},
+	)
+	c := New(memory.New(), nil, providers)
+	for _, r := range []model.Rating{
+		{UserID: "user1", Value: 5},
+		{UserID: "user2", Value: 2, ProviderID: "partner"},
+		{UserID: "user3", Value: 2, ProviderID: "partner"},
+		{UserID: "user4", Value: 1, ProviderID: "spam"},
+	} {
+		require.NoError(t, c.PutRating(ctx, "1", model.RecordTypeMovie, &r))
+	}
+
+	got, err := c.GetAggregatedRatingByProvider(ctx, "1", model.RecordTypeMovie)
+	require.NoError(t, err)
+	// (5*1 + 2*0.5 + 2*0.5) / (1 + 0.5 + 0.5)
+	assert.Equal(t, &model.AggregatedRating{Rating: 3.5, VoteCount: 3, Providers: []model.ProviderRating{
+		{ProviderID: "partner", Rating: 2, VoteCount: 2, Weight: 0.5, Enabled: true},
+		{ProviderID: OwnProviderID, Rating: 5, VoteCount: 1, Weight: 1, Enabled: true},
+		{ProviderID: "spam", Rating: 1, VoteCount: 1, Weight: 1, Enabled: false},
+	}}, got)
+
+	// Records rated only by disabled providers have no aggregated rating.
+	require.NoError(t, c.PutRating(ctx, "2", model.RecordTypeMovie, &model.Rating{UserID: "user1", Value: 3, ProviderID: "spam"}))
+	_, err = c.GetAggregatedRating(ctx, "2", model.RecordTypeMovie)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestGetTopRatedWeightsProviders(t *testing.T) {
+	ctx := context.Background()
+	providers := provider.NewRegistry(provider.Provider{Weight: 1, Enabled: true},
+		provider.Provider{ID: "partner", Weight: 0.25, Enabled: true},
+		provider.Provider{ID: "spam", Weight: 1, Enabled: false},
+	)
+	c := New(memory.New(), nil, providers)
+	for _, r := range []struct {
+		recordID model.RecordID
+		rating   model.Rating
+	}{
+		{"1", model.Rating{UserID: "105", Value: 4}},
+		// The user 105 of the partner is not our user 105.
+		{"1", model.Rating{UserID: "105", Value: 1, ProviderID: "partner"}},
+		{"2", model.Rating{UserID: "105", Value: 3}},
+		{"3", model.Rating{UserID: "105", Value: 2}},
+		{"3", model.Rating{UserID: "106", Value: 5, ProviderID: "spam"}},
+		{"4", model.Rating{UserID: "106", Value: 5, ProviderID: "spam"}},
+	} {
+		require.NoError(t, c.PutRating(ctx, r.recordID, model.RecordTypeMovie, &r.rating))
+	}
+
+	got, err := c.GetTopRated(ctx, model.RecordTypeMovie, 10, 0)
+	require.NoError(t, err)
+	// (4*1 + 1*0.25) / (1 + 0.25)
+	assert.Equal(t, []model.RatedRecord{
+		{RecordID: "1", RecordType: model.RecordTypeMovie, Rating: 3.4, VoteCount: 2},
+		{RecordID: "2", RecordType: model.RecordTypeMovie, Rating: 3, VoteCount: 1},
+		{RecordID: "3", RecordType: model.RecordTypeMovie, Rating: 2, VoteCount: 1},
+	}, got)
+	for _, r := range got {
+		agg, err := c.GetAggregatedRatingByProvider(ctx, r.RecordID, r.RecordType)
+		require.NoError(t, err)
+		assert.Equal(t, agg.Rating, r.Rating, "record %s", r.RecordID)
+		assert.Equal(t, agg.VoteCount, r.VoteCount, "record %s", r.RecordID)
+	}
+
+	// Deleting our rating keeps the rating of the partner user with the same id.
+	require.NoError(t, c.DeleteRating(ctx, "1", model.RecordTypeMovie, "105"))
+	agg, err := c.GetAggregatedRatingByProvider(ctx, "1", model.RecordTypeMovie)
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), agg.Rating)
+}
+
+func TestGetRolledUpRating(t *testing.T) {
+	ctx := context.Background()
+	c := New(memory.New(), nil, nil)
+	for _, r := range []struct {
+		recordID   model.RecordID
+		recordType model.RecordType
+		value      model.RatingValue
+	}{
+		{"s1/e1", model.RecordTypeEpisode, 4},
+		{"s1/e1", model.RecordTypeEpisode, 2},
+		{"s1/e2", model.RecordTypeEpisode, 3},
+		{"s1", model.RecordTypeSeries, 1},
+		{"s10/e1", model.RecordTypeEpisode, 1},
+	} {
+		require.NoError(t, c.PutRating(ctx, r.recordID, r.recordType, &model.Rating{UserID: model.UserID(fmt.Sprint("user", r.value)), Value: r.value}))
+	}
+
+	got, err := c.GetRolledUpRating(ctx, "s1", model.RecordTypeSeries)
+	require.NoError(t, err)
+	assert.Equal(t, float64(3), got.Rating)
+	assert.Equal(t, 3, got.VoteCount)
+	series, err := c.GetAggregatedRating(ctx, "s1", model.RecordTypeSeries)
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), series)
+
+	_, err = c.GetRolledUpRating(ctx, "s2", model.RecordTypeSeries)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = c.GetRolledUpRating(ctx, "1", model.RecordTypeMovie)
+	assert.ErrorIs(t, err, ErrInvalidRecord)
+	for _, r := range []struct {
+		recordID   model.RecordID
+		recordType model.RecordType
+	}{
+		{"1", "book"},
+		{"e1", model.RecordTypeEpisode},
+		{"s1/", model.RecordTypeEpisode},
+		{"s1/s2", model.RecordTypeSeries},
+	} {
+		assert.ErrorIs(t, c.PutRating(ctx, r.recordID, r.recordType, &model.Rating{UserID: "user1", Value: 1}), ErrInvalidRecord, "%s %s", r.recordType, r.recordID)
+		_, err := c.GetAggregatedRatingByProvider(ctx, r.recordID, r.recordType)
+		assert.ErrorIs(t, err, ErrInvalidRecord, "%s %s", r.recordType, r.recordID)
+	}
+}
+
+func TestStartIngestion(t *testing.T) {
+	ctx := context.Background()
+	in := ingester.NewIngester(2)
+	c := New(memory.New(), in, nil)
+	for _, e := range []model.RatingEvent{
+		{Rating: model.Rating{RecordID: "1", RecordType: "movie", UserID: "user1", Value: 4}},
+		{Rating: model.Rating{RecordID: "1", RecordType: "movie", UserID: "user2", Value: 2}},
+	} {
+		require.NoError(t, in.Publish(ctx, e))
+	}
+	in.Close()
+	require.NoError(t, c.StartIngestion(ctx, IngestOptions{}))
+
+	got, err := c.GetAggregatedRating(ctx, "1", model.RecordTypeMovie)
+	require.NoError(t, err)
+	assert.Equal(t, float64(3), got)
+	assert.ErrorIs(t, New(memory.New(), nil, nil).StartIngestion(ctx, IngestOptions{}), ErrNoIngester)
+}
+
+type flakyRepository struct {
+	*memory.Repository
+	failures int
+}
+
+func (r *flakyRepository) Put(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) error {
+	if r.failures > 0 {
+		r.failures--
+		return errors.New("connection refused")
+	}
+	return r.Repository.Put(ctx, recordID, recordType, rating)
+}
+
+func (r *flakyRepository) PutBatch(ctx context.Context, ratings []model.Rating) error {
+	if r.failures > 0 {
+		r.failures--
+		return errors.New("connection refused")
+	}
+	return r.Repository.PutBatch(ctx, ratings)
+}
+
+func TestStartIngestionRetriesAndRejects(t *testing.T) {
+	defer func(d time.Duration) { ingestBackoff = d }(ingestBackoff)
+	ingestBackoff = time.Millisecond
+	ctx := context.Background()
+	// Failed writes are retried until they succeed and never rejected.
+	const failures = 8
+	repo := &flakyRepository{Repository: memory.New(), failures: failures}
+	in := ingester.NewIngester(2)
+	c := New(repo, in, nil)
+	require.NoError(t, in.Publish(ctx, model.RatingEvent{Rating: model.Rating{RecordID: "1", RecordType: "movie", UserID: "user1", Value: 4}}))
+	require.NoError(t, in.Publish(ctx, model.RatingEvent{Rating: model.Rating{RecordID: "1", RecordType: "movie", Value: 1}}))
+	in.Close()
+	scope := tally.NewTestScope("", nil)
+	require.NoError(t, c.StartIngestion(ctx, IngestOptions{Scope: scope}))
+
+	got, err := c.GetAggregatedRating(ctx, "1", model.RecordTypeMovie)
+	require.NoError(t, err)
+	assert.Equal(t, float64(4), got)
+	assert.Equal(t, 1, in.Acked())
+	deadLetters := in.DeadLetters()
+	require.Len(t, deadLetters, 1)
+	assert.ErrorIs(t, deadLetters[0].Reason, ErrInvalidEvent)
+
+	counters := map[string]int64{}
+	for _, c := range scope.Snapshot().Counters() {
+		counters[c.Name()] = c.Value()
+	}
+	assert.Equal(t, map[string]int64{"consumed": 2, "applied": 1, "duplicate": 0, "failed": 1, "retry": failures}, counters)
+}
+
+func TestStartIngestionDeduplicates(t *testing.T) {
+	ctx := context.Background()
+	in := ingester.NewIngester(3)
+	c := New(memory.New(), in, nil)
+	now := time.Now()
+	rating := func(v model.RatingValue) model.Rating {
+		return model.Rating{RecordID: "1", RecordType: "movie", UserID: "user1", Value: v, ProviderID: "provider"}
+	}
+	for _, e := range []model.RatingEvent{
+		{Rating: rating(4), EventID: "1", Timestamp: now},
+		// An update produced before the current rating arrives out of order.
+		{Rating: rating(1), EventID: "2", Timestamp: now.Add(-time.Second)},
+		// A redelivered event is skipped even though it looks like the latest one.
+		{Rating: rating(2), EventID: "1", Timestamp: now.Add(time.Second)},
+	} {
+		require.NoError(t, in.Publish(ctx, e))
+	}
+	in.Close()
+	require.NoError(t, c.StartIngestion(ctx, IngestOptions{}))
+
+	got, err := c.GetAggregatedRating(ctx, "1", model.RecordTypeMovie)
+	require.NoError(t, err)
+	assert.Equal(t, float64(4), got)
+	assert.Equal(t, 3, in.Acked())
+}
+
+func TestStartIngestionFailedWrite(t *testing.T) {
+	defer func(backoff, drain time.Duration) { ingestBackoff, ingestDrainTimeout = backoff, drain }(ingestBackoff, ingestDrainTimeout)
+	ingestBackoff, ingestDrainTimeout = time.Millisecond, 10*time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	repo := &flakyRepository{Repository: memory.New(), failures: 1 << 30}
+	require.NoError(t, repo.Repository.Put(ctx, "1", model.RecordTypeMovie, &model.Rating{UserID: "user1", Value: 3}))
+	in := ingester.NewIngester(2)
+	c := New(repo, in, nil)
+	updates, err := c.WatchAggregatedRating(context.Background(), "1", model.RecordTypeMovie, WatchOptions{})
+	require.NoError(t, err)
+	defer c.Shutdown()
+	assert.Equal(t, float64(3), <-updates)
+
+	// Neither the event nor its duplicate in the same batch is acknowledged
+	// while the write fails, and the watchers are not notified.
+	e := model.RatingEvent{Rating: model.Rating{RecordID: "1", RecordType: "movie", UserID: "user2", Value: 5, ProviderID: "provider"}, EventID: "1"}
+	require.NoError(t, in.Publish(ctx, e))
+	require.NoError(t, in.Publish(ctx, e))
+	require.NoError(t, c.StartIngestion(ctx, IngestOptions{}))
+	assert.Equal(t, 0, in.Acked())
+	select {
+	case v := <-updates:
+		t.Fatalf("unexpected update %v", v)
+	default:
+	}
+}
+
+func TestStartIngestionKeepsRecordOrder(t *testing.T) {
+	ctx := context.Background()
+	const records, updates = 20, 50
+	in := ingester.NewIngester(records * updates)
+	c := New(memory.New(), in, nil)
+	// Events have no timestamp, so the last processed update of each record wins.
+	for v := range updates {
+		for r := range records {
+			require.NoError(t, in.Publish(ctx, model.RatingEvent{Rating: model.Rating{RecordID: fmt.Sprint(r), RecordType: "movie", UserID: "user", Value: model.RatingValue(v)}}))
+		}
+	}
+	in.Close()
+	require.NoError(t, c.StartIngestion(ctx, IngestOptions{Workers: 4, QueueSize: 2, BatchSize: 7, BatchWait: time.Millisecond}))
+
+	for r := range records {
+		got, err := c.GetAggregatedRating(ctx, model.RecordID(fmt.Sprint(r)), model.RecordTypeMovie)
+		require.NoError(t, err)
+		assert.Equal(t, float64(updates-1), got, "record %d", r)
+	}
+	assert.Equal(t, records*updates, in.Acked())
+}
@@ -0,0 +1,48 @@
+package rating
+
+import (
+	"container/list"
+	"sync"
+)
+
+// dedupCapacity is the number of most recently processed event ids remembered
+// to detect redelivered events.
+const dedupCapacity = 100_000
+
+// dedupStore is a bounded set of processed event keys. The least recently
+// added key is evicted once the store is full. Duplicates older than that are
+// still harmless because ratings are resolved with last-writer-wins.
+type dedupStore struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	keys     map[string]*list.Element
+}
+
+func newDedupStore(capacity int) *dedupStore {
+	return &dedupStore{capacity: capacity, order: list.New(), keys: map[string]*list.Element{}}
+}
+
+// seen reports whether the key was added before.
+func (d *dedupStore) seen(key string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, ok := d.keys[key]
+	return ok
+}
+
+// add remembers a key, evicting the oldest one if the store is full.
+func (d *dedupStore) add(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if e, ok := d.keys[key]; ok {
+		d.order.MoveToFront(e)
+		return
+	}
+	d.keys[key] = d.order.PushFront(key)
+	if d.order.Len() > d.capacity {
+		oldest := d.order.Back()
+		d.order.Remove(oldest)
+		delete(d.keys, oldest.Value.(string))
+	}
+}
@@ -0,0 +1,178 @@
+package rating
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/abhishek622/movieapp/rating/internal/eventlog"
+	"github.com/abhishek622/movieapp/rating/internal/provider"
+	"github.com/abhishek622/movieapp/rating/internal/repository"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/google/uuid"
+)
+
+// ErrNoEventLog is returned when projections are rebuilt without an event log.
+var ErrNoEventLog = errors.New("no rating event log configured")
+
+// replayBatchSize is the maximum number of ratings written to the repository
+// at once while projections are rebuilt.
+const replayBatchSize = 500
+
+type eventLog interface {
+	Append(ctx context.Context, events ...model.RatingEvent) error
+	Replay(ctx context.Context, from eventlog.Position, fn func(model.RatingEvent) error) (eventlog.Position, error)
+}
+
+// NewEventSourced creates a rating service controller in event-sourcing mode.
+// Every change is appended to the event log before it is applied to the
+// repository, so that the repository and its aggregates are projections of
+// the log that can be rebuilt from it.
+func NewEventSourced(repo ratingRepository, ingester ratingIngester, providers *provider.Registry, log eventLog) *Controller {
+	c := New(repo, ingester, providers)
+	c.log = log
+	return c
+}
+
+// record appends a change to the event log in event-sourcing mode and returns
+// the dedup key of its event, or an empty string if there is no event log.
+func (c *Controller) record(ctx context.Context, eventType model.RatingEventType, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) (string, error) {
+	if c.log == nil {
+		return "", nil
+	}
+	e := model.RatingEvent{
+		Rating: model.Rating{
+			RecordID:   string(recordID),
+			RecordType: string(recordType),
+			UserID:     rating.UserID,
+			Value:      rating.Value,
+			ProviderID: rating.ProviderID,
+		},
+		EventID:   uuid.NewString(),
+		EventType: eventType,
+		Timestamp: rating.UpdatedAt,
+	}
+	if err := c.log.Append(ctx, e); err != nil {
+		return "", err
+	}
+	return dedupKey(e), nil
+}
+
+// applied remembers an event applied to the repository so that it is skipped
+// if it is ingested from the event log.
+func (c *Controller) applied(key string) {
+	if key != "" {
+		c.dedup.add(key)
+	}
+}
+
+// Rebuild replaces the ratings in the repository with the ratings built from
+// the event log. If snapshot is set, the ratings start from the snapshot and
+// only the events appended after it are replayed. The new ratings are built
+// aside and replace the current ones, which are served until then, once the
+// replay succeeds. Rebuild returns the position after the last replayed
+// event. Ratings must not be changed while the projections are rebuilt.
+func (c *Controller) Rebuild(ctx context.Context, snapshot *eventlog.Snapshot) (eventlog.Position, error) {
+	if c.log == nil {
+		return nil, ErrNoEventLog
+	}
+	var pos eventlog.Position
+	err := c.repo.Rebuild(ctx, func(repo repository.Projection) error {
+		var from eventlog.Position
+		if snapshot != nil {
+			for ratings := range slices.Chunk(snapshot.Ratings, replayBatchSize) {
+				if err := repo.PutBatch(ctx, ratings); err != nil {
+					return err
+				}
+			}
+			for _, t := range snapshot.Tombstones {
+				if err := repo.Delete(ctx, t.RecordID, t.RecordType, t.UserID, t.ProviderID, t.DeletedAt); err != nil {
+					return err
+				}
+			}
+			from = snapshot.Position
+		}
+		p := &projector{repo: repo}
+		var err error
+		if pos, err = c.log.Replay(ctx, from, func(e model.RatingEvent) error { return p.apply(ctx, e) }); err != nil {
+			return err
+		}
+		return p.flush(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pos, nil
+}
+
+// Snapshot returns the ratings and tombstones in the repository as a snapshot
+// of the event log at the given position, which must be the position the
+// repository was built up to.
+func (c *Controller) Snapshot(ctx context.Context, pos eventlog.Position) (*eventlog.Snapshot, error) {
+	s := &eventlog.Snapshot{Position: pos.Clone(), CreatedAt: time.Now().UTC()}
+	err := c.repo.Export(ctx, "", time.Time{}, time.Time{}, nil, func(r *model.Rating) error {
+		s.Ratings = append(s.Ratings, *r)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = c.repo.Tombstones(ctx, func(t *model.Tombstone) error {
+		s.Tombstones = append(s.Tombstones, *t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// projector applies replayed events to the repository. Consecutive puts are
+// written in batches.
+type projector struct {
+	repo    repository.Projection
+	pending []model.Rating
+}
+
+func (p *projector) apply(ctx context.Context, e model.RatingEvent) error {
+	if validateEvent(e) != nil {
+		return nil
+	}
+	if e.EventType == model.RatingEventTypeDelete {
+		if err := p.flush(ctx); err != nil {
+			return err
+		}
+		return p.repo.Delete(ctx, model.RecordID(e.RecordID), model.RecordType(e.RecordType), e.UserID, e.ProviderID, eventTime(e))
+	}
+	p.pending = append(p.pending, eventRating(e))
+	if len(p.pending) < replayBatchSize {
+		return nil
+	}
+	return p.flush(ctx)
+}
+
+func (p *projector) flush(ctx context.Context) error {
+	if len(p.pending) == 0 {
+		return nil
+	}
+	if err := p.repo.PutBatch(ctx, p.pending); err != nil {
+		return err
+	}
+	p.pending = p.pending[:0]
+	return nil
+}
+
+// eventTime returns the time of an event, or the current time for events
+// without a timestamp.
+func eventTime(e model.RatingEvent) time.Time {
+	if e.Timestamp.IsZero() {
+		return time.Now().UTC()
+	}
+	return e.Timestamp
+}
+
+// eventRating returns the rating written by a put event.
+func eventRating(e model.RatingEvent) model.Rating {
+	return model.Rating{RecordID: e.RecordID, RecordType: e.RecordType, UserID: e.UserID, Value: e.Value, UpdatedAt: eventTime(e), ProviderID: e.ProviderID}
+}
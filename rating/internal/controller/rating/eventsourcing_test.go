@@ -0,0 +1,141 @@
+package rating
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/abhishek622/movieapp/rating/internal/eventlog"
+	"github.com/abhishek622/movieapp/rating/internal/eventlog/file"
+	ingester "github.com/abhishek622/movieapp/rating/internal/ingester/memory"
+	"github.com/abhishek622/movieapp/rating/internal/repository/memory"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventSourcedRebuild(t *testing.T) {
+	ctx := context.Background()
+	l, err := file.Open(filepath.Join(t.TempDir(), "ratings.log"))
+	require.NoError(t, err)
+	defer l.Close()
+	c := NewEventSourced(memory.New(), nil, nil, l)
+	put := func(recordID model.RecordID, userID model.UserID, v model.RatingValue) {
+		require.NoError(t, c.PutRating(ctx, recordID, model.RecordTypeMovie, &model.Rating{UserID: userID, Value: v}))
+	}
+	aggregated := func(c *Controller, recordID model.RecordID) float64 {
+		v, err := c.GetAggregatedRating(ctx, recordID, model.RecordTypeMovie)
+		if err != nil {
+			return -1
+		}
+		return v
+	}
+
+	put("1", "user1", 5)
+	put("1", "user2", 3)
+	put("2", "user1", 2)
+	require.NoError(t, c.DeleteRating(ctx, "1", model.RecordTypeMovie, "user2"))
+	assert.Equal(t, float64(5), aggregated(c, "1"))
+
+	// A new projection is rebuilt from offset zero.
+	rebuilt := NewEventSourced(memory.New(), nil, nil, l)
+	pos, err := rebuilt.Rebuild(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, eventlog.Position{0: 4}, pos)
+	assert.Equal(t, float64(5), aggregated(rebuilt, "1"))
+	assert.Equal(t, float64(2), aggregated(rebuilt, "2"))
+	snapshot, err := rebuilt.Snapshot(ctx, pos)
+	require.NoError(t, err)
+	assert.Len(t, snapshot.Ratings, 2)
+
+	// Events appended after the snapshot are replayed on top of it.
+	put("2", "user2", 4)
+	require.NoError(t, c.DeleteRating(ctx, "1", model.RecordTypeMovie, "user1"))
+	fromSnapshot := NewEventSourced(memory.New(), nil, nil, l)
+	pos, err = fromSnapshot.Rebuild(ctx, snapshot)
+	require.NoError(t, err)
+	assert.Equal(t, eventlog.Position{0: 6}, pos)
+	assert.Equal(t, float64(-1), aggregated(fromSnapshot, "1"))
+	assert.Equal(t, float64(3), aggregated(fromSnapshot, "2"))
+
+	_, err = New(memory.New(), nil, nil).Rebuild(ctx, nil)
+	assert.ErrorIs(t, err, ErrNoEventLog)
+}
+
+func TestStartIngestionDeletes(t *testing.T) {
+	ctx := context.Background()
+	in := ingester.NewIngester(3)
+	c := New(memory.New(), in, nil)
+	for _, e := range []model.RatingEvent{
+		{Rating: model.Rating{RecordID: "1", RecordType: "movie", UserID: "user1", Value: 4}},
+		{Rating: model.Rating{RecordID: "1", RecordType: "movie", UserID: "user1"}, EventType: model.RatingEventTypeDelete},
+		{Rating: model.Rating{RecordID: "1", RecordType: "movie", UserID: "user2", Value: 2}, EventType: model.RatingEventTypePut},
+	} {
+		require.NoError(t, in.Publish(ctx, e))
+	}
+	in.Close()
+	require.NoError(t, c.StartIngestion(ctx, IngestOptions{}))
+
+	got, err := c.GetAggregatedRating(ctx, "1", model.RecordTypeMovie)
+	require.NoError(t, err)
+	assert.Equal(t, float64(2), got)
+	assert.Equal(t, 3, in.Acked())
+}
+
+func TestEventSourcedIngestion(t *testing.T) {
+	ctx := context.Background()
+	l, err := file.Open(filepath.Join(t.TempDir(), "ratings.log"))
+	require.NoError(t, err)
+	defer l.Close()
+	in := ingester.NewIngester(3)
+	c := NewEventSourced(memory.New(), in, nil, l)
+	now := time.Now().UTC()
+	for _, e := range []model.RatingEvent{
+		{Rating: model.Rating{RecordID: "1", RecordType: "movie", UserID: "user1", Value: 4, ProviderID: "imdb"}, Timestamp: now},
+		{Rating: model.Rating{RecordID: "1", RecordType: "movie", UserID: "user2", Value: 2, ProviderID: "imdb"}, Timestamp: now},
+		{Rating: model.Rating{RecordID: "1", RecordType: "movie", UserID: "user2", ProviderID: "imdb"}, EventType: model.RatingEventTypeDelete, Timestamp: now.Add(time.Second)},
+	} {
+		require.NoError(t, in.Publish(ctx, e))
+	}
+	in.Close()
+	require.NoError(t, c.StartIngestion(ctx, IngestOptions{}))
+
+	// The ingested events are in the log, so a rebuilt projection keeps them.
+	rebuilt := NewEventSourced(memory.New(), nil, nil, l)
+	pos, err := rebuilt.Rebuild(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, eventlog.Position{0: 3}, pos)
+	got, err := rebuilt.GetAggregatedRating(ctx, "1", model.RecordTypeMovie)
+	require.NoError(t, err)
+	assert.Equal(t, float64(4), got)
+
+	// The tombstone of the deleted rating is kept in snapshots, so that an
+	// older rating replayed on top of a snapshot does not bring it back.
+	snapshot, err := rebuilt.Snapshot(ctx, pos)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Tombstone{{RatingKey: model.RatingKey{RecordID: "1", RecordType: model.RecordTypeMovie, UserID: "user2", ProviderID: "imdb"}, DeletedAt: now.Add(time.Second)}}, snapshot.Tombstones)
+	require.NoError(t, l.Append(ctx, model.RatingEvent{Rating: model.Rating{RecordID: "1", RecordType: "movie", UserID: "user2", Value: 1, ProviderID: "imdb"}, Timestamp: now}))
+	fromSnapshot := NewEventSourced(memory.New(), nil, nil, l)
+	_, err = fromSnapshot.Rebuild(ctx, snapshot)
+	require.NoError(t, err)
+	got, err = fromSnapshot.GetAggregatedRating(ctx, "1", model.RecordTypeMovie)
+	require.NoError(t, err)
+	assert.Equal(t, float64(4), got)
+}
+
+func TestEventSourcedRebuildFailureKeepsRatings(t *testing.T) {
+	ctx := context.Background()
+	l, err := file.Open(filepath.Join(t.TempDir(), "ratings.log"))
+	require.NoError(t, err)
+	defer l.Close()
+	c := NewEventSourced(memory.New(), nil, nil, l)
+	require.NoError(t, c.PutRating(ctx, "1", model.RecordTypeMovie, &model.Rating{UserID: "user1", Value: 5}))
+
+	// The snapshot position is after the end of the log, so the replay fails.
+	_, err = c.Rebuild(ctx, &eventlog.Snapshot{Position: eventlog.Position{0: 10}})
+	assert.Error(t, err)
+	got, err := c.GetAggregatedRating(ctx, "1", model.RecordTypeMovie)
+	require.NoError(t, err)
+	assert.Equal(t, float64(5), got)
+}
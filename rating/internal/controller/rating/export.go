@@ -0,0 +1,105 @@
+package rating
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+)
+
+// ErrInvalidCursor is returned when an export cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid export cursor")
+
+// exportBatchSize is the maximum number of ratings passed in a single export batch.
+const exportBatchSize = 500
+
+// ExportFilter selects the ratings included in an export.
+type ExportFilter struct {
+	// RecordType limits the export to a single record type if set.
+	RecordType model.RecordType
+	// StartTime and EndTime limit the export to ratings updated within the range if set.
+	StartTime time.Time
+	EndTime   time.Time
+	// SnapshotTime excludes ratings updated after it. It defaults to the time
+	// the export starts. Resumed exports must reuse the original snapshot time
+	// so that the ratings of the first batches and of the resumed ones are
+	// selected alike. Ratings updated after the snapshot time, including the
+	// ones not exported yet when the export was interrupted, are left to an
+	// incremental export whose StartTime is the snapshot time.
+	SnapshotTime time.Time
+	// Cursor resumes an export after the last batch that was received.
+	Cursor string
+}
+
+// ExportBatch is a part of a rating export.
+type ExportBatch struct {
+	Ratings []model.Rating
+	// Cursor resumes the export after this batch.
+	Cursor       string
+	SnapshotTime time.Time
+}
+
+// ExportRatings passes all ratings matching the filter to fn in batches ordered
+// by record id, record type and user id.
+func (c *Controller) ExportRatings(ctx context.Context, filter ExportFilter, fn func(*ExportBatch) error) error {
+	var after *model.RatingKey
+	if filter.Cursor != "" {
+		key, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return err
+		}
+		after = key
+	}
+	snapshot := filter.SnapshotTime
+	if snapshot.IsZero() {
+		snapshot = time.Now().UTC()
+	}
+	until := snapshot
+	if !filter.EndTime.IsZero() && filter.EndTime.Before(until) {
+		until = filter.EndTime
+	}
+
+	batch := &ExportBatch{SnapshotTime: snapshot}
+	flush := func() error {
+		if len(batch.Ratings) == 0 {
+			return nil
+		}
+		last := batch.Ratings[len(batch.Ratings)-1]
+		batch.Cursor = encodeCursor(model.RatingKey{RecordID: model.RecordID(last.RecordID), RecordType: model.RecordType(last.RecordType), UserID: last.UserID, ProviderID: last.ProviderID})
+		if err := fn(batch); err != nil {
+			return err
+		}
+		batch = &ExportBatch{SnapshotTime: snapshot}
+		return nil
+	}
+	if err := c.repo.Export(ctx, filter.RecordType, filter.StartTime, until, after, func(r *model.Rating) error {
+		batch.Ratings = append(batch.Ratings, *r)
+		if len(batch.Ratings) == exportBatchSize {
+			return flush()
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	return flush()
+}
+
+func encodeCursor(key model.RatingKey) string {
+	b, _ := json.Marshal(key)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(cursor string) (*model.RatingKey, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var key model.RatingKey
+	if err := json.Unmarshal(b, &key); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &key, nil
+}
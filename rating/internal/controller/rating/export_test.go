@@ -0,0 +1,97 @@
+package rating
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/abhishek622/movieapp/rating/internal/repository/memory"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exportUsers exports the users of the exported ratings and the batches.
+func exportUsers(t *testing.T, c *Controller, filter ExportFilter) ([]model.UserID, []*ExportBatch) {
+	var users []model.UserID
+	var batches []*ExportBatch
+	err := c.ExportRatings(context.Background(), filter, func(b *ExportBatch) error {
+		for _, r := range b.Ratings {
+			users = append(users, r.UserID)
+		}
+		batches = append(batches, b)
+		return nil
+	})
+	require.NoError(t, err)
+	return users, batches
+}
+
+func TestExportRatingsResume(t *testing.T) {
+	ctx := context.Background()
+	c := New(memory.New(), nil, nil)
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var want []model.UserID
+	for i := range exportBatchSize + 10 {
+		userID := model.UserID(fmt.Sprintf("user%04d", i))
+		want = append(want, userID)
+		require.NoError(t, c.PutRating(ctx, "1", model.RecordTypeMovie, &model.Rating{UserID: userID, Value: 5, UpdatedAt: base}))
+	}
+	snapshot := base.Add(time.Hour)
+
+	users, batches := exportUsers(t, c, ExportFilter{SnapshotTime: snapshot})
+	assert.Equal(t, want, users, "ratings must be exported in key order")
+	require.Len(t, batches, 2)
+	assert.Len(t, batches[0].Ratings, exportBatchSize)
+	assert.Equal(t, snapshot, batches[0].SnapshotTime)
+
+	// Ratings updated after the snapshot time are left out of the resumed
+	// export, and are exported by an export starting at the snapshot time.
+	late := &model.Rating{UserID: "user9999", Value: 1, UpdatedAt: snapshot.Add(time.Minute)}
+	require.NoError(t, c.PutRating(ctx, "1", model.RecordTypeMovie, late))
+	users, _ = exportUsers(t, c, ExportFilter{SnapshotTime: snapshot, Cursor: batches[0].Cursor})
+	assert.Equal(t, want[exportBatchSize:], users)
+	users, _ = exportUsers(t, c, ExportFilter{StartTime: snapshot, SnapshotTime: snapshot.Add(time.Hour)})
+	assert.Equal(t, []model.UserID{late.UserID}, users)
+
+	err := c.ExportRatings(ctx, ExportFilter{Cursor: "not a cursor"}, func(*ExportBatch) error { return nil })
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestExportRatingsFilters(t *testing.T) {
+	ctx := context.Background()
+	c := New(memory.New(), nil, nil)
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, r := range []struct {
+		recordID   model.RecordID
+		recordType model.RecordType
+		userID     model.UserID
+	}{
+		{"1", model.RecordTypeMovie, "a"},
+		{"1", model.RecordTypeMovie, "b"},
+		{"1", model.RecordTypeMovie, "c"},
+		{"s1/1", model.RecordTypeEpisode, "d"},
+	} {
+		updatedAt := base.Add(time.Duration(i) * time.Hour)
+		require.NoError(t, c.PutRating(ctx, r.recordID, r.recordType, &model.Rating{UserID: r.userID, Value: 3, UpdatedAt: updatedAt}))
+	}
+
+	tests := []struct {
+		name   string
+		filter ExportFilter
+		want   []model.UserID
+	}{
+		{"all", ExportFilter{}, []model.UserID{"a", "b", "c", "d"}},
+		{"record type", ExportFilter{RecordType: model.RecordTypeMovie}, []model.UserID{"a", "b", "c"}},
+		{"start time", ExportFilter{StartTime: base.Add(time.Hour)}, []model.UserID{"b", "c", "d"}},
+		{"end time", ExportFilter{EndTime: base.Add(time.Hour)}, []model.UserID{"a", "b"}},
+		{"time range", ExportFilter{StartTime: base.Add(time.Hour), EndTime: base.Add(2 * time.Hour)}, []model.UserID{"b", "c"}},
+		{"snapshot time", ExportFilter{SnapshotTime: base.Add(90 * time.Minute)}, []model.UserID{"a", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, _ := exportUsers(t, c, tt.filter)
+			assert.Equal(t, tt.want, users)
+		})
+	}
+}
@@ -0,0 +1,408 @@
+package rating
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/abhishek622/movieapp/rating/internal/ingester"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/abhishek622/movieapp/rating/pkg/ratingevent"
+	"github.com/uber-go/tally/v4"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrNoIngester is returned when ingestion is started without an ingester.
+	ErrNoIngester = errors.New("no rating ingester configured")
+	// ErrInvalidEvent is returned when an ingested rating event is not valid.
+	ErrInvalidEvent = errors.New("invalid rating event")
+)
+
+var (
+	// ingestBackoff is the delay before the second attempt. It doubles with
+	// every further attempt up to ingestMaxBackoff.
+	ingestBackoff    = 100 * time.Millisecond
+	ingestMaxBackoff = 5 * time.Second
+	// ingestDrainTimeout is the maximum time the workers keep processing queued
+	// events after ingestion is stopped.
+	ingestDrainTimeout = 5 * time.Second
+)
+
+// IngestOptions configures the ingestion of rating events. Zero values are
+// replaced with the defaults.
+type IngestOptions struct {
+	// Workers is the number of workers processing events in parallel. Events
+	// of the same record are always processed by the same worker, in order.
+	Workers int
+	// QueueSize is the number of events queued per worker. Reading from the
+	// ingester pauses while the queue of a worker is full.
+	QueueSize int
+	// BatchSize is the maximum number of ratings written to the repository at once.
+	BatchSize int
+	// BatchWait is the maximum time an event waits for its batch to fill up.
+	BatchWait time.Duration
+	// Scope receives the ingestion metrics. Metrics are discarded if it is nil.
+	Scope tally.Scope
+	// Logger logs ingestion events. Nothing is logged if it is nil.
+	Logger *zap.Logger
+	// FromEventLog tells that the ingester reads the event log of an
+	// event-sourced controller. Otherwise the ingested events are appended to
+	// the event log before they are applied, so that rebuilt projections keep
+	// them.
+	FromEventLog bool
+}
+
+type ingestMetrics struct {
+	consumed     tally.Counter
+	applied      tally.Counter
+	duplicates   tally.Counter
+	failed       tally.Counter
+	retries      tally.Counter
+	batchSize    tally.Histogram
+	writeLatency tally.Histogram
+	// latency is the time from the event timestamp until its rating is persisted.
+	latency tally.Histogram
+}
+
+func newIngestMetrics(scope tally.Scope) *ingestMetrics {
+	scope = scope.Tagged(map[string]string{"component": "ingestion"})
+	latencyBuckets := tally.MustMakeExponentialDurationBuckets(time.Millisecond, 2, 20)
+	return &ingestMetrics{
+		consumed:     scope.Counter("consumed"),
+		applied:      scope.Counter("applied"),
+		duplicates:   scope.Counter("duplicate"),
+		failed:       scope.Counter("failed"),
+		retries:      scope.Counter("retry"),
+		batchSize:    scope.Histogram("batch_size", tally.MustMakeExponentialValueBuckets(1, 2, 10)),
+		writeLatency: scope.Histogram("write_latency", latencyBuckets),
+		latency:      scope.Histogram("end_to_end_latency", latencyBuckets),
+	}
+}
+
+// ingestion holds the state of a running ingestion.
+type ingestion struct {
+	*Controller
+	metrics *ingestMetrics
+	logger  *zap.Logger
+	// appendToLog tells whether ingested events are appended to the event log.
+	appendToLog bool
+}
+
+// DefaultIngestOptions are the ingestion options used for zero values.
+var DefaultIngestOptions = IngestOptions{
+	Workers:   8,
+	QueueSize: 128,
+	BatchSize: 100,
+	BatchWait: 50 * time.Millisecond,
+}
+
+func (o IngestOptions) withDefaults() IngestOptions {
+	if o.Workers <= 0 {
+		o.Workers = DefaultIngestOptions.Workers
+	}
+	if o.QueueSize <= 0 {
+		o.QueueSize = DefaultIngestOptions.QueueSize
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = DefaultIngestOptions.BatchSize
+	}
+	if o.BatchWait <= 0 {
+		o.BatchWait = DefaultIngestOptions.BatchWait
+	}
+	if o.Scope == nil {
+		o.Scope = tally.NoopScope
+	}
+	if o.Logger == nil {
+		o.Logger = zap.NewNop()
+	}
+	return o
+}
+
+// StartIngestion starts the ingestion of rating events. It returns when ctx is
+// done or the ingester has no more messages. Events are sharded by record
+// between workers, which write them to the repository in batches. Messages are
+// acknowledged once their rating is persisted. Only the messages with invalid
+// events are rejected. Failed writes are retried with backoff until they
+// succeed, and the workers stop taking messages meanwhile, so that an outage
+// of the repository pauses the ingestion instead of rejecting its messages.
+func (s *Controller) StartIngestion(ctx context.Context, opts IngestOptions) error {
+	if s.ingester == nil {
+		return ErrNoIngester
+	}
+	opts = opts.withDefaults()
+	ch, err := s.ingester.Ingest(ctx)
+	if err != nil {
+		return err
+	}
+	in := &ingestion{s, newIngestMetrics(opts.Scope), opts.Logger.With(zap.String("component", "ingestion")), s.log != nil && !opts.FromEventLog}
+	in.logger.Info("Started ingestion", zap.Int("workers", opts.Workers), zap.Int("batchSize", opts.BatchSize))
+
+	// Workers are not stopped by ctx so that they can finish the queued events.
+	workCtx, stopWorkers := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopWorkers()
+	queues := make([]chan ingester.Message, opts.Workers)
+	var wg sync.WaitGroup
+	for i := range queues {
+		queues[i] = make(chan ingester.Message, opts.QueueSize)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			in.runWorker(workCtx, queues[i], opts)
+		}()
+	}
+
+	dispatch(ctx, ch, queues)
+	for _, q := range queues {
+		close(q)
+	}
+	if ctx.Err() != nil {
+		t := time.AfterFunc(ingestDrainTimeout, stopWorkers)
+		defer t.Stop()
+	}
+	wg.Wait()
+	in.logger.Info("Stopped ingestion")
+	return nil
+}
+
+// dispatch passes every message to the queue of the worker owning its record
+// until ctx is done or the ingester has no more messages.
+func dispatch(ctx context.Context, ch chan ingester.Message, queues []chan ingester.Message) {
+	for {
+		var msg ingester.Message
+		var ok bool
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok = <-ch:
+			if !ok {
+				return
+			}
+		}
+		h := fnv.New32a()
+		h.Write([]byte(msg.Event.RecordType + "/" + msg.Event.RecordID))
+		select {
+		case <-ctx.Done():
+			return
+		case queues[h.Sum32()%uint32(len(queues))] <- msg:
+		}
+	}
+}
+
+// runWorker processes the messages of a queue in batches until the queue is closed.
+func (s *ingestion) runWorker(ctx context.Context, queue chan ingester.Message, opts IngestOptions) {
+	batch := make([]ingester.Message, 0, opts.BatchSize)
+	timer := time.NewTimer(opts.BatchWait)
+	timer.Stop()
+	for {
+		select {
+		case msg, ok := <-queue:
+			if !ok {
+				s.processBatch(ctx, batch)
+				return
+			}
+			batch = append(batch, msg)
+			if len(batch) == 1 {
+				timer.Reset(opts.BatchWait)
+			}
+			if len(batch) < opts.BatchSize {
+				continue
+			}
+			timer.Stop()
+		case <-timer.C:
+		}
+		s.processBatch(ctx, batch)
+		batch = batch[:0]
+	}
+}
+
+// processBatch applies the events of a batch of messages to the repository
+// and acknowledges or rejects the messages. Consecutive puts are written
+// together, deletes are applied one by one in their order. Messages are left
+// unacknowledged if ctx is done before their events are applied. Duplicates
+// of an event of the batch are acknowledged once it is applied, and the
+// watchers of a record are notified once its events are applied.
+func (s *ingestion) processBatch(ctx context.Context, batch []ingester.Message) {
+	var msgs []ingester.Message
+	// dups holds the duplicates of the events of the batch by dedup key.
+	dups := map[string][]ingester.Message{}
+	s.metrics.batchSize.RecordValue(float64(len(batch)))
+	for _, msg := range batch {
+		e := msg.Event
+		s.metrics.consumed.Inc(1)
+		s.logger.Debug("Consumed a rating event", zap.String("eventId", e.EventID), zap.String("recordId", e.RecordID), zap.String("userId", string(e.UserID)))
+		key := dedupKey(e)
+		if _, ok := dups[key]; ok && key != "" {
+			s.metrics.duplicates.Inc(1)
+			s.logger.Debug("Skipping a duplicate event", zap.String("key", key))
+			dups[key] = append(dups[key], msg)
+			continue
+		}
+		if key != "" && s.dedup.seen(key) {
+			s.metrics.duplicates.Inc(1)
+			s.logger.Debug("Skipping a duplicate event", zap.String("key", key))
+			s.retry(ctx, msg.Ack)
+			continue
+		}
+		if err := validateEvent(e); err != nil {
+			s.reject(ctx, msg, err)
+			continue
+		}
+		if key != "" {
+			dups[key] = nil
+		}
+		msgs = append(msgs, msg)
+	}
+	if s.appendToLog && len(msgs) > 0 {
+		events := make([]model.RatingEvent, len(msgs))
+		for i := range msgs {
+			// The time of events without a timestamp is fixed so that
+			// replaying them from the event log gives the same ratings.
+			msgs[i].Event.Timestamp = eventTime(msgs[i].Event)
+			events[i] = msgs[i].Event
+		}
+		if err := s.retry(ctx, func() error { return s.log.Append(ctx, events...) }); err != nil {
+			return
+		}
+	}
+
+	var records []watchKey
+	seen := map[watchKey]bool{}
+	for len(msgs) > 0 {
+		n := 0
+		for n < len(msgs) && msgs[n].Event.EventType != model.RatingEventTypeDelete {
+			n++
+		}
+		var ok bool
+		if n == 0 {
+			ok, n = s.delete(ctx, msgs[0]), 1
+		} else {
+			ok = s.putBatch(ctx, msgs[:n])
+		}
+		if !ok {
+			break
+		}
+		for _, msg := range msgs[:n] {
+			for _, dup := range dups[dedupKey(msg.Event)] {
+				s.retry(ctx, dup.Ack)
+			}
+			key := watchKey{model.RecordID(msg.Event.RecordID), model.RecordType(msg.Event.RecordType)}
+			if !seen[key] {
+				seen[key] = true
+				records = append(records, key)
+			}
+		}
+		msgs = msgs[n:]
+	}
+	for _, key := range records {
+		s.notifyWatchers(ctx, key.recordID, key.recordType)
+	}
+}
+
+// putBatch persists the ratings of put events. It returns false if ctx is
+// done before the ratings are persisted.
+func (s *ingestion) putBatch(ctx context.Context, msgs []ingester.Message) bool {
+	ratings := make([]model.Rating, len(msgs))
+	for i, msg := range msgs {
+		ratings[i] = eventRating(msg.Event)
+	}
+	start := time.Now()
+	err := s.retry(ctx, func() error { return s.repo.PutBatch(ctx, ratings) })
+	s.metrics.writeLatency.RecordDuration(time.Since(start))
+	if err != nil {
+		return false
+	}
+	for _, msg := range msgs {
+		s.acknowledge(ctx, msg)
+	}
+	return true
+}
+
+// delete applies a delete event. It returns false if ctx is done before the
+// rating is deleted.
+func (s *ingestion) delete(ctx context.Context, msg ingester.Message) bool {
+	e := msg.Event
+	start := time.Now()
+	err := s.retry(ctx, func() error {
+		return s.repo.Delete(ctx, model.RecordID(e.RecordID), model.RecordType(e.RecordType), e.UserID, e.ProviderID, eventTime(e))
+	})
+	s.metrics.writeLatency.RecordDuration(time.Since(start))
+	if err != nil {
+		return false
+	}
+	s.acknowledge(ctx, msg)
+	return true
+}
+
+// acknowledge remembers the event of a persisted message and acknowledges the message.
+func (s *ingestion) acknowledge(ctx context.Context, msg ingester.Message) {
+	s.metrics.applied.Inc(1)
+	if !msg.Event.Timestamp.IsZero() {
+		s.metrics.latency.RecordDuration(time.Since(msg.Event.Timestamp))
+	}
+	if key := dedupKey(msg.Event); key != "" {
+		s.dedup.add(key)
+	}
+	s.retry(ctx, msg.Ack)
+}
+
+func (s *ingestion) reject(ctx context.Context, msg ingester.Message, err error) {
+	s.metrics.failed.Inc(1)
+	s.logger.Warn("Rejecting a rating event", zap.String("eventId", msg.Event.EventID), zap.String("recordId", msg.Event.RecordID), zap.Error(err))
+	s.retry(ctx, func() error { return msg.Nack(err) })
+}
+
+// dedupKey returns the key identifying an event across redeliveries or an
+// empty string if the event has no id.
+func dedupKey(e model.RatingEvent) string {
+	if e.EventID == "" {
+		return ""
+	}
+	return e.ProviderID + "/" + e.EventID
+}
+
+func validateEvent(e model.RatingEvent) error {
+	if err := ratingevent.Validate(&e); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	return nil
+}
+
+// retry calls fn until it succeeds or ctx is done, and returns the last error.
+func (s *ingestion) retry(ctx context.Context, fn func() error) error {
+	backoff := ingestBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		s.metrics.retries.Inc(1)
+		s.logger.Warn("Attempt failed, retrying", zap.Int("attempt", attempt), zap.Duration("backoff", backoff), zap.Error(err))
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+		backoff = min(2*backoff, ingestMaxBackoff)
+	}
+}
+
+// IngestionStatus returns the status of the partitions consumed by the
+// ingester. It returns ErrUnsupported if the ingester has no partitions.
+func (c *Controller) IngestionStatus(ctx context.Context) ([]ingester.PartitionStatus, error) {
+	if c.ingester == nil {
+		return nil, ErrNoIngester
+	}
+	p, ok := c.ingester.(interface {
+		Partitions(ctx context.Context) ([]ingester.PartitionStatus, error)
+	})
+	if !ok {
+		return nil, errors.ErrUnsupported
+	}
+	return p.Partitions(ctx)
+}
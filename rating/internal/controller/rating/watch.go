@@ -0,0 +1,192 @@
+package rating
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+)
+
+// ErrShutdown is returned when a watch is requested after the controller was shut down.
+var ErrShutdown = errors.New("rating controller is shut down")
+
+// watchBufferSize is the number of undelivered updates kept per watcher
+// when updates are not coalesced. The oldest update is dropped on overflow.
+const watchBufferSize = 16
+
+// WatchOptions configures how aggregated rating updates are delivered to a watcher.
+type WatchOptions struct {
+	// MinInterval is the minimum time between two consecutive updates.
+	MinInterval time.Duration
+	// Coalesce delivers only the latest aggregate if several updates
+	// happen before the previous one was delivered.
+	Coalesce bool
+}
+
+type watchKey struct {
+	recordID   model.RecordID
+	recordType model.RecordType
+}
+
+// watchHub fans out aggregated rating updates to the watchers of a record.
+type watchHub struct {
+	mu     sync.Mutex
+	subs   map[watchKey]map[chan float64]struct{}
+	done   chan struct{}
+	closed bool
+}
+
+func newWatchHub() *watchHub {
+	return &watchHub{subs: map[watchKey]map[chan float64]struct{}{}, done: make(chan struct{})}
+}
+
+func (h *watchHub) subscribe(key watchKey) (chan float64, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, ErrShutdown
+	}
+	if _, ok := h.subs[key]; !ok {
+		h.subs[key] = map[chan float64]struct{}{}
+	}
+	ch := make(chan float64, watchBufferSize)
+	h.subs[key][ch] = struct{}{}
+	return ch, nil
+}
+
+func (h *watchHub) unsubscribe(key watchKey, ch chan float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subs[key], ch)
+	if len(h.subs[key]) == 0 {
+		delete(h.subs, key)
+	}
+}
+
+func (h *watchHub) hasSubscribers(key watchKey) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs[key]) > 0
+}
+
+// publish delivers an update to all watchers of a record without blocking.
+func (h *watchHub) publish(key watchKey, v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs[key] {
+		for {
+			select {
+			case ch <- v:
+			default:
+				// Drop the oldest update to make room for the new one.
+				select {
+				case <-ch:
+				default:
+				}
+				continue
+			}
+			break
+		}
+	}
+}
+
+// close stops all watchers. No new watchers can be added afterwards.
+func (h *watchHub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	close(h.done)
+}
+
+// WatchAggregatedRating returns a channel receiving the aggregated rating of a
+// record, starting with the current value if there is one, and then every time
+// the ratings of the record change. The channel is closed when ctx is done or
+// the controller is shut down.
+func (c *Controller) WatchAggregatedRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType, opts WatchOptions) (<-chan float64, error) {
+	key := watchKey{recordID, recordType}
+	updates, err := c.watchers.subscribe(key)
+	if err != nil {
+		return nil, err
+	}
+	if v, err := c.GetAggregatedRating(ctx, recordID, recordType); err == nil {
+		updates <- v
+	} else if !errors.Is(err, ErrNotFound) {
+		c.watchers.unsubscribe(key, updates)
+		return nil, err
+	}
+
+	out := make(chan float64)
+	go func() {
+		defer close(out)
+		defer c.watchers.unsubscribe(key, updates)
+		var lastSent time.Time
+		for {
+			var v float64
+			select {
+			case <-ctx.Done():
+				return
+			case <-c.watchers.done:
+				return
+			case v = <-updates:
+			}
+			if wait := opts.MinInterval - time.Since(lastSent); wait > 0 {
+				t := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					t.Stop()
+					return
+				case <-c.watchers.done:
+					t.Stop()
+					return
+				case <-t.C:
+				}
+			}
+			if opts.Coalesce {
+				v = latest(updates, v)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-c.watchers.done:
+				return
+			case out <- v:
+				lastSent = time.Now()
+			}
+		}
+	}()
+	return out, nil
+}
+
+// Shutdown closes all active watch channels and rejects new watches.
+func (c *Controller) Shutdown() {
+	c.watchers.close()
+}
+
+func (c *Controller) notifyWatchers(ctx context.Context, recordID model.RecordID, recordType model.RecordType) {
+	key := watchKey{recordID, recordType}
+	if !c.watchers.hasSubscribers(key) {
+		return
+	}
+	v, err := c.GetAggregatedRating(ctx, recordID, recordType)
+	if err != nil {
+		return
+	}
+	c.watchers.publish(key, v)
+}
+
+// latest drains all buffered updates and returns the most recent one.
+func latest(ch chan float64, v float64) float64 {
+	for {
+		select {
+		case next := <-ch:
+			v = next
+		default:
+			return v
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package review
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/abhishek622/movieapp/rating/internal/gateway"
+	"github.com/abhishek622/movieapp/rating/internal/repository"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNotFound is returned when a review does not exist.
+	ErrNotFound = errors.New("review not found")
+	// ErrUnauthenticated is returned when a review is written without a valid auth token.
+	ErrUnauthenticated = errors.New("missing or invalid auth token")
+	// ErrPermissionDenied is returned when a user changes, moderates or votes for a review they are not allowed to.
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrInvalidReview is returned when a review or a moderation decision is not valid.
+	ErrInvalidReview = errors.New("invalid review")
+	// ErrAlreadyVoted is returned when a user votes for the same review twice.
+	ErrAlreadyVoted = errors.New("user already voted for the review")
+	// ErrInvalidPageToken is returned when a page token cannot be decoded.
+	ErrInvalidPageToken = errors.New("invalid page token")
+)
+
+const (
+	// MaxTextLength is the maximum number of characters in a review.
+	MaxTextLength = 5000
+	// DefaultPageSize is the number of reviews returned by ListApproved when no page size is set.
+	DefaultPageSize = 20
+	// MaxPageSize is the maximum number of reviews returned by ListApproved.
+	MaxPageSize = 100
+)
+
+type reviewRepository interface {
+	PutReview(ctx context.Context, review *model.Review) error
+	GetReview(ctx context.Context, id model.ReviewID) (*model.Review, error)
+	DeleteReview(ctx context.Context, id model.ReviewID) error
+	ListReviews(ctx context.Context, recordID model.RecordID, recordType model.RecordType, status model.ReviewStatus, after *model.ReviewCursor, limit int) ([]model.Review, error)
+	UpdateReview(ctx context.Context, id model.ReviewID, update model.ReviewUpdate) (*model.Review, error)
+	AddHelpfulVote(ctx context.Context, id model.ReviewID, userID model.UserID) (int, error)
+}
+
+type authGateway interface {
+	ValidateToken(ctx context.Context, token string) (string, error)
+}
+
+// Controller defines a review controller.
+type Controller struct {
+	repo       reviewRepository
+	auth       authGateway
+	moderators map[model.UserID]bool
+}
+
+// New creates a review controller. Users are authenticated by the auth
+// gateway, and only the listed moderators can moderate reviews.
+func New(repo reviewRepository, auth authGateway, moderators []model.UserID) *Controller {
+	c := &Controller{repo: repo, auth: auth, moderators: map[model.UserID]bool{}}
+	for _, m := range moderators {
+		c.moderators[m] = true
+	}
+	return c
+}
+
+// Authenticate returns the user of an auth token validated by the auth
+// service. It returns ErrUnauthenticated if the token is missing or rejected.
+func (c *Controller) Authenticate(ctx context.Context, token string) (model.UserID, error) {
+	if token == "" {
+		return "", ErrUnauthenticated
+	}
+	username, err := c.auth.ValidateToken(ctx, token)
+	if err != nil && errors.Is(err, gateway.ErrUnauthenticated) {
+		return "", ErrUnauthenticated
+	} else if err != nil {
+		return "", err
+	}
+	if username == "" {
+		// Tokens without a username cannot be attributed to a user.
+		return "", ErrUnauthenticated
+	}
+	return model.UserID(username), nil
+}
+
+// Create stores a new review. New reviews are pending until a moderator approves them.
+func (c *Controller) Create(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID, text string) (*model.Review, error) {
+	if err := validateText(text); err != nil {
+		return nil, err
+	}
+	createdAt := now()
+	review := &model.Review{
+		ID:         model.ReviewID(uuid.NewString()),
+		RecordID:   recordID,
+		RecordType: recordType,
+		UserID:     userID,
+		Text:       text,
+		Status:     model.ReviewStatusPending,
+		CreatedAt:  createdAt,
+		UpdatedAt:  createdAt,
+	}
+	if err := c.repo.PutReview(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// Edit changes the text of a review written by userID. The edited review
+// goes back to the pending state.
+func (c *Controller) Edit(ctx context.Context, id model.ReviewID, userID model.UserID, text string) (*model.Review, error) {
+	if err := validateText(text); err != nil {
+		return nil, err
+	}
+	review, err := c.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID != userID {
+		return nil, ErrPermissionDenied
+	}
+	status := model.ReviewStatusPending
+	return c.update(ctx, id, model.ReviewUpdate{Text: &text, Status: &status, UpdatedAt: now()})
+}
+
+// Delete removes a review written by userID.
+func (c *Controller) Delete(ctx context.Context, id model.ReviewID, userID model.UserID) error {
+	review, err := c.get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if review.UserID != userID {
+		return ErrPermissionDenied
+	}
+	if err := c.repo.DeleteReview(ctx, id); err != nil && errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Moderate approves or rejects a review on behalf of a moderator.
+func (c *Controller) Moderate(ctx context.Context, moderator model.UserID, id model.ReviewID, status model.ReviewStatus) (*model.Review, error) {
+	if !c.moderators[moderator] {
+		return nil, ErrPermissionDenied
+	}
+	if status != model.ReviewStatusApproved && status != model.ReviewStatusRejected {
+		return nil, fmt.Errorf("%w: unsupported moderation status %q", ErrInvalidReview, status)
+	}
+	return c.update(ctx, id, model.ReviewUpdate{Status: &status, UpdatedAt: now()})
+}
+
+// ListApproved returns a page of approved reviews of a record, from the newest
+// to the oldest one, and the token of the next page if there is one.
+func (c *Controller) ListApproved(ctx context.Context, recordID model.RecordID, recordType model.RecordType, pageSize int, pageToken string) ([]model.Review, string, error) {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	} else if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+	var after *model.ReviewCursor
+	if pageToken != "" {
+		cursor, err := decodePageToken(pageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		after = cursor
+	}
+	// Fetch one extra review to find out whether there is a next page.
+	reviews, err := c.repo.ListReviews(ctx, recordID, recordType, model.ReviewStatusApproved, after, pageSize+1)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(reviews) <= pageSize {
+		return reviews, "", nil
+	}
+	reviews = reviews[:pageSize]
+	last := reviews[len(reviews)-1]
+	return reviews, encodePageToken(model.ReviewCursor{CreatedAt: last.CreatedAt, ID: last.ID}), nil
+}
+
+// VoteHelpful marks an approved review as helpful for userID and returns the
+// updated number of helpful votes. Users cannot vote for their own reviews.
+func (c *Controller) VoteHelpful(ctx context.Context, id model.ReviewID, userID model.UserID) (int, error) {
+	review, err := c.get(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if review.Status != model.ReviewStatusApproved {
+		return 0, ErrNotFound
+	}
+	if review.UserID == userID {
+		return 0, ErrPermissionDenied
+	}
+	votes, err := c.repo.AddHelpfulVote(ctx, id, userID)
+	if err != nil && errors.Is(err, repository.ErrAlreadyExists) {
+		return 0, ErrAlreadyVoted
+	} else if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return 0, ErrNotFound
+	}
+	return votes, err
+}
+
+// update changes the fields of a review set in the update only, so that
+// concurrent helpful votes are not lost.
+func (c *Controller) update(ctx context.Context, id model.ReviewID, update model.ReviewUpdate) (*model.Review, error) {
+	review, err := c.repo.UpdateReview(ctx, id, update)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	return review, err
+}
+
+func (c *Controller) get(ctx context.Context, id model.ReviewID) (*model.Review, error) {
+	review, err := c.repo.GetReview(ctx, id)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	return review, err
+}
+
+// now returns the current time truncated to the precision kept by the repositories.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+func validateText(text string) error {
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("%w: empty text", ErrInvalidReview)
+	}
+	if utf8.RuneCountInString(text) > MaxTextLength {
+		return fmt.Errorf("%w: text longer than %d characters", ErrInvalidReview, MaxTextLength)
+	}
+	return nil
+}
+
+// encodePageToken returns an opaque token for a review list position.
+func encodePageToken(c model.ReviewCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + ":" + string(c.ID)))
+}
+
+func decodePageToken(token string) (*model.ReviewCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	ts, id, ok := strings.Cut(string(b), ":")
+	if !ok || id == "" {
+		return nil, ErrInvalidPageToken
+	}
+	nanos, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	return &model.ReviewCursor{CreatedAt: time.Unix(0, nanos).UTC(), ID: model.ReviewID(id)}, nil
+}
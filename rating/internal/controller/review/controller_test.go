@@ -0,0 +1,138 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/abhishek622/movieapp/rating/internal/gateway"
+	"github.com/abhishek622/movieapp/rating/internal/repository/memory"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAuthGateway accepts the tokens of its map and returns their user.
+type fakeAuthGateway map[string]string
+
+func (g fakeAuthGateway) ValidateToken(ctx context.Context, token string) (string, error) {
+	username, ok := g[token]
+	if !ok {
+		return "", gateway.ErrUnauthenticated
+	}
+	return username, nil
+}
+
+func newTestController() *Controller {
+	return New(memory.New(), fakeAuthGateway{"author-token": "author", "anonymous-token": ""}, []model.UserID{"moderator"})
+}
+
+func TestAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	c := newTestController()
+	userID, err := c.Authenticate(ctx, "author-token")
+	require.NoError(t, err)
+	assert.Equal(t, model.UserID("author"), userID)
+	for _, token := range []string{"", "unknown-token", "anonymous-token"} {
+		_, err := c.Authenticate(ctx, token)
+		assert.ErrorIs(t, err, ErrUnauthenticated, token)
+	}
+}
+
+func TestModerationWorkflow(t *testing.T) {
+	ctx := context.Background()
+	c := newTestController()
+
+	r, err := c.Create(ctx, "1", model.RecordTypeMovie, "author", "Great movie")
+	require.NoError(t, err)
+	assert.Equal(t, model.ReviewStatusPending, r.Status)
+
+	reviews, _, err := c.ListApproved(ctx, "1", model.RecordTypeMovie, 0, "")
+	require.NoError(t, err)
+	assert.Empty(t, reviews, "pending reviews must not be listed")
+	_, err = c.VoteHelpful(ctx, r.ID, "reader")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = c.Moderate(ctx, "author", r.ID, model.ReviewStatusApproved)
+	assert.ErrorIs(t, err, ErrPermissionDenied, "only moderators can moderate reviews")
+	_, err = c.Moderate(ctx, "moderator", r.ID, model.ReviewStatusApproved)
+	require.NoError(t, err)
+	votes, err := c.VoteHelpful(ctx, r.ID, "reader")
+	require.NoError(t, err)
+	assert.Equal(t, 1, votes)
+	_, err = c.VoteHelpful(ctx, r.ID, "reader")
+	assert.ErrorIs(t, err, ErrAlreadyVoted)
+	_, err = c.VoteHelpful(ctx, r.ID, "author")
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+
+	_, err = c.Edit(ctx, r.ID, "reader", "Not my review")
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	edited, err := c.Edit(ctx, r.ID, "author", "Great movie, great cast")
+	require.NoError(t, err)
+	assert.Equal(t, model.ReviewStatusPending, edited.Status, "edited reviews must be moderated again")
+	assert.Equal(t, 1, edited.HelpfulVotes, "edits must keep the helpful votes")
+
+	require.NoError(t, c.Delete(ctx, r.ID, "author"))
+	assert.ErrorIs(t, c.Delete(ctx, r.ID, "author"), ErrNotFound)
+}
+
+func TestListApprovedPagination(t *testing.T) {
+	ctx := context.Background()
+	c := newTestController()
+	var ids []model.ReviewID
+	for _, user := range []model.UserID{"a", "b", "c", "d", "e"} {
+		r, err := c.Create(ctx, "1", model.RecordTypeMovie, user, "Review by "+string(user))
+		require.NoError(t, err)
+		_, err = c.Moderate(ctx, "moderator", r.ID, model.ReviewStatusApproved)
+		require.NoError(t, err)
+		ids = append(ids, r.ID)
+	}
+
+	var got []model.ReviewID
+	token := ""
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 3)
+		reviews, next, err := c.ListApproved(ctx, "1", model.RecordTypeMovie, 2, token)
+		require.NoError(t, err)
+		for _, r := range reviews {
+			got = append(got, r.ID)
+		}
+		if next == "" {
+			break
+		}
+		token = next
+	}
+	assert.ElementsMatch(t, ids, got)
+
+	_, _, err := c.ListApproved(ctx, "1", model.RecordTypeMovie, 2, "not a token")
+	assert.ErrorIs(t, err, ErrInvalidPageToken)
+}
+
+func TestModerateKeepsConcurrentVotes(t *testing.T) {
+	ctx := context.Background()
+	c := newTestController()
+	r, err := c.Create(ctx, "1", model.RecordTypeMovie, "author", "Great movie")
+	require.NoError(t, err)
+	_, err = c.Moderate(ctx, "moderator", r.ID, model.ReviewStatusApproved)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := c.VoteHelpful(ctx, r.ID, model.UserID(fmt.Sprintf("reader-%d", i)))
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := c.Moderate(ctx, "moderator", r.ID, model.ReviewStatusApproved)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	moderated, err := c.Moderate(ctx, "moderator", r.ID, model.ReviewStatusApproved)
+	require.NoError(t, err)
+	assert.Equal(t, 20, moderated.HelpfulVotes)
+}
@@ -0,0 +1,69 @@
+package eventlog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"os"
+	"time"
+
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+)
+
+// Log is an append-only log of rating events.
+type Log interface {
+	Append(ctx context.Context, events ...model.RatingEvent) error
+	// Replay calls fn for every event from a position up to the end of the
+	// log and returns the position after the last event.
+	Replay(ctx context.Context, from Position, fn func(model.RatingEvent) error) (Position, error)
+}
+
+// Position is a position in an event log: the offset of the next event to
+// read in every partition. Partitions that are not set are read from the start.
+type Position map[int32]int64
+
+// Clone returns a copy of the position.
+func (p Position) Clone() Position {
+	if p == nil {
+		return Position{}
+	}
+	return maps.Clone(p)
+}
+
+// Snapshot holds the ratings built from the events of a log up to a position,
+// and the tombstones of the ratings deleted by them.
+type Snapshot struct {
+	Position   Position          `json:"position"`
+	CreatedAt  time.Time         `json:"createdAt"`
+	Ratings    []model.Rating    `json:"ratings"`
+	Tombstones []model.Tombstone `json:"tombstones,omitempty"`
+}
+
+// ReadSnapshot reads a snapshot file. It returns nil if the file does not exist.
+func ReadSnapshot(path string) (*Snapshot, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	s := &Snapshot{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// WriteSnapshot atomically replaces a snapshot file.
+func WriteSnapshot(path string, s *Snapshot) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,41 @@
+// Package eventlogutil opens the rating event log selected by a configuration.
+package eventlogutil
+
+import (
+	"fmt"
+
+	"github.com/abhishek622/movieapp/rating/internal/eventlog"
+	"github.com/abhishek622/movieapp/rating/internal/eventlog/file"
+	"github.com/abhishek622/movieapp/rating/internal/eventlog/kafka"
+)
+
+// Options selects an event log.
+type Options struct {
+	// Type is the event log type, file or kafka. It defaults to file.
+	Type string
+	// FilePath is the path of a file log.
+	FilePath string
+	// KafkaAddress and KafkaTopic are the bootstrap servers and topic of a Kafka log.
+	KafkaAddress string
+	KafkaTopic   string
+}
+
+// Open opens the event log selected by opts and returns a function closing it.
+func Open(opts Options) (eventlog.Log, func(), error) {
+	switch opts.Type {
+	case "", "file":
+		l, err := file.Open(opts.FilePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		return l, func() { l.Close() }, nil
+	case "kafka":
+		l, err := kafka.New(opts.KafkaAddress, opts.KafkaTopic)
+		if err != nil {
+			return nil, nil, err
+		}
+		return l, l.Close, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported event log type %q", opts.Type)
+	}
+}
@@ -0,0 +1,58 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abhishek622/movieapp/rating/internal/eventlog"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendReplay(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "ratings.log")
+	l, err := Open(path)
+	require.NoError(t, err)
+
+	event := func(userID model.UserID, v model.RatingValue) model.RatingEvent {
+		return model.RatingEvent{Rating: model.Rating{RecordID: "1", RecordType: "movie", UserID: userID, Value: v}, EventType: model.RatingEventTypePut}
+	}
+	replay := func(l *Log, from eventlog.Position) ([]model.UserID, eventlog.Position) {
+		var users []model.UserID
+		pos, err := l.Replay(ctx, from, func(e model.RatingEvent) error {
+			users = append(users, e.UserID)
+			return nil
+		})
+		require.NoError(t, err)
+		return users, pos
+	}
+
+	require.NoError(t, l.Append(ctx, event("user1", 5), event("user2", 4)))
+	require.NoError(t, l.Append(ctx, event("user3", 3)))
+	users, pos := replay(l, nil)
+	assert.Equal(t, []model.UserID{"user1", "user2", "user3"}, users)
+	assert.Equal(t, eventlog.Position{0: 3}, pos)
+	users, _ = replay(l, eventlog.Position{0: 2})
+	assert.Equal(t, []model.UserID{"user3"}, users)
+	_, err = l.Replay(ctx, eventlog.Position{0: 4}, func(model.RatingEvent) error { return nil })
+	assert.Error(t, err, "positions after the end of the log must be rejected")
+	require.NoError(t, l.Close())
+
+	// A partially written last event is dropped when the log is reopened.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"userId":"user4"`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	l, err = Open(path)
+	require.NoError(t, err)
+	defer l.Close()
+	require.NoError(t, l.Append(ctx, event("user5", 1)))
+	users, pos = replay(l, nil)
+	assert.Equal(t, []model.UserID{"user1", "user2", "user3", "user5"}, users)
+	assert.Equal(t, eventlog.Position{0: 4}, pos)
+}
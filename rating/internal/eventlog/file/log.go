@@ -0,0 +1,128 @@
+package file
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/abhishek622/movieapp/rating/internal/eventlog"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+)
+
+// partition is the only partition of a file log.
+const partition = 0
+
+// Log defines an event log stored in a file as newline-delimited JSON events.
+// The offset of an event is its line number, starting at zero.
+type Log struct {
+	mu   sync.Mutex
+	f    *os.File
+	next int64
+}
+
+// Open opens or creates the log file at path.
+func Open(path string) (*Log, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	l := &Log{f: f}
+	if err := l.count(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Append writes events to the end of the log and returns once they are
+// synced to disk.
+func (l *Log) Append(ctx context.Context, events ...model.RatingEvent) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var buf []byte
+	for i := range events {
+		b, err := json.Marshal(&events[i])
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, b...), '\n')
+	}
+	if _, err := l.f.Write(buf); err != nil {
+		return err
+	}
+	if err := l.f.Sync(); err != nil {
+		return err
+	}
+	l.next += int64(len(events))
+	return nil
+}
+
+// Replay calls fn for every event from the given position up to the end of
+// the log, in order, and returns the position after the last event.
+func (l *Log) Replay(ctx context.Context, from eventlog.Position, fn func(model.RatingEvent) error) (eventlog.Position, error) {
+	l.mu.Lock()
+	end := l.next
+	l.mu.Unlock()
+	start := from[partition]
+	if start > end {
+		return nil, fmt.Errorf("position %d is after the end of the log at %d", start, end)
+	}
+	f, err := os.Open(l.f.Name())
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	for offset := int64(0); offset < end; offset++ {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			return nil, fmt.Errorf("read event %d: %w", offset, err)
+		}
+		if offset < start {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var e model.RatingEvent
+		if err := json.Unmarshal(line, &e); err != nil {
+			return nil, fmt.Errorf("decode event %d: %w", offset, err)
+		}
+		if err := fn(e); err != nil {
+			return nil, err
+		}
+	}
+	return eventlog.Position{partition: end}, nil
+}
+
+// count counts the events of the log file. A partially written last event is
+// truncated.
+func (l *Log) count() error {
+	if _, err := l.f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	r := bufio.NewReader(l.f)
+	var size int64
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) > 0 {
+				return l.f.Truncate(size)
+			}
+			return nil
+		} else if err != nil {
+			return err
+		}
+		size += int64(len(line))
+		l.next++
+	}
+}
+
+// Close closes the log file.
+func (l *Log) Close() error {
+	return l.f.Close()
+}
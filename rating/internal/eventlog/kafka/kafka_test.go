@@ -0,0 +1,68 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/abhishek622/movieapp/rating/internal/eventlog"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendReplay(t *testing.T) {
+	ctx := context.Background()
+	cluster, err := kafka.NewMockCluster(1)
+	require.NoError(t, err)
+	defer cluster.Close()
+	const topic = "ratings"
+	l, err := New(cluster.BootstrapServers(), topic)
+	require.NoError(t, err)
+	defer l.Close()
+
+	event := func(recordID string, v model.RatingValue) model.RatingEvent {
+		return model.RatingEvent{
+			Rating:    model.Rating{RecordID: recordID, RecordType: "movie", UserID: "user1", Value: v},
+			EventID:   fmt.Sprintf("%s-%d", recordID, v),
+			EventType: model.RatingEventTypePut,
+		}
+	}
+	replay := func(from eventlog.Position) (map[string][]model.RatingValue, eventlog.Position) {
+		values := map[string][]model.RatingValue{}
+		pos, err := l.Replay(ctx, from, func(e model.RatingEvent) error {
+			values[e.RecordID] = append(values[e.RecordID], e.Value)
+			return nil
+		})
+		require.NoError(t, err)
+		return values, pos
+	}
+
+	var events []model.RatingEvent
+	for _, recordID := range []string{"1", "2", "3", "4"} {
+		for v := range model.RatingValue(3) {
+			events = append(events, event(recordID, v))
+		}
+	}
+	require.NoError(t, l.Append(ctx, events...))
+	values, pos := replay(nil)
+	for _, recordID := range []string{"1", "2", "3", "4"} {
+		assert.Equal(t, []model.RatingValue{0, 1, 2}, values[recordID], "events of a record must keep their order")
+	}
+	var total int64
+	for _, offset := range pos {
+		total += offset
+	}
+	assert.Equal(t, int64(len(events)), total)
+
+	// Only the events appended after a position are replayed from it.
+	require.NoError(t, l.Append(ctx, event("2", 5)))
+	values, next := replay(pos)
+	assert.Equal(t, map[string][]model.RatingValue{"2": {5}}, values)
+	values, _ = replay(next)
+	assert.Empty(t, values)
+
+	_, err = l.Replay(ctx, eventlog.Position{0: 1000}, func(model.RatingEvent) error { return nil })
+	assert.Error(t, err, "positions after the end of the log must be rejected")
+}
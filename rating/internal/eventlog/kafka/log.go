@@ -0,0 +1,173 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/abhishek622/movieapp/rating/internal/eventlog"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/abhishek622/movieapp/rating/pkg/ratingevent"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/google/uuid"
+)
+
+const (
+	// requestTimeout is the timeout of metadata and watermark requests.
+	requestTimeout = 10 * time.Second
+	// pollTimeout is the maximum time to wait for a message before checking whether a replay is stopped.
+	pollTimeout = 100 * time.Millisecond
+)
+
+// Log defines an event log stored in a Kafka topic. Events of the same record
+// are written to the same partition so that they keep their order.
+type Log struct {
+	addr     string
+	topic    string
+	producer *kafka.Producer
+}
+
+// New creates a new Kafka event log.
+func New(addr string, topic string) (*Log, error) {
+	producer, err := kafka.NewProducer(&kafka.ConfigMap{
+		"bootstrap.servers":  addr,
+		"enable.idempotence": true,
+		"acks":               "all",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Log{addr, topic, producer}, nil
+}
+
+// Append writes protobuf-encoded events to the topic and returns once all of
+// them are acknowledged by the brokers.
+func (l *Log) Append(ctx context.Context, events ...model.RatingEvent) error {
+	delivery := make(chan kafka.Event, len(events))
+	for i := range events {
+		value, err := ratingevent.Encode(&events[i], ratingevent.ContentTypeProtobuf)
+		if err != nil {
+			return err
+		}
+		if err := l.producer.Produce(&kafka.Message{
+			TopicPartition: kafka.TopicPartition{Topic: &l.topic, Partition: kafka.PartitionAny},
+			Key:            ratingevent.Key(&events[i]),
+			Value:          value,
+			Headers:        []kafka.Header{{Key: ratingevent.HeaderContentType, Value: []byte(ratingevent.ContentTypeProtobuf)}},
+		}, delivery); err != nil {
+			return err
+		}
+	}
+	var err error
+	for range events {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case e := <-delivery:
+			if m, ok := e.(*kafka.Message); ok && m.TopicPartition.Error != nil && err == nil {
+				err = m.TopicPartition.Error
+			}
+		}
+	}
+	return err
+}
+
+// Replay calls fn for every event from the given position up to the end of
+// every partition at the time of the call, and returns the position after the
+// last event. Events are read in order within a partition.
+func (l *Log) Replay(ctx context.Context, from eventlog.Position, fn func(model.RatingEvent) error) (eventlog.Position, error) {
+	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers":  l.addr,
+		"group.id":           "rating-replay-" + uuid.NewString(),
+		"enable.auto.commit": false,
+		// End of partition events stop the replay of partitions whose last
+		// offsets do not hold events, such as transaction markers.
+		"enable.partition.eof": true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer consumer.Close()
+
+	md, err := consumer.GetMetadata(&l.topic, false, int(requestTimeout.Milliseconds()))
+	if err != nil {
+		return nil, err
+	}
+	topic, ok := md.Topics[l.topic]
+	if !ok || topic.Error.Code() != kafka.ErrNoError {
+		return nil, fmt.Errorf("topic %s not found", l.topic)
+	}
+	pos := eventlog.Position{}
+	end := map[int32]int64{}
+	var assignment []kafka.TopicPartition
+	for _, p := range topic.Partitions {
+		low, high, err := consumer.QueryWatermarkOffsets(l.topic, p.ID, int(requestTimeout.Milliseconds()))
+		if err != nil {
+			return nil, err
+		}
+		start := max(from[p.ID], low)
+		if start > high {
+			return nil, fmt.Errorf("position %d of partition %d is after the end of the log at %d", start, p.ID, high)
+		}
+		pos[p.ID] = start
+		if start < high {
+			end[p.ID] = high
+			assignment = append(assignment, kafka.TopicPartition{Topic: &l.topic, Partition: p.ID, Offset: kafka.Offset(start)})
+		}
+	}
+	if len(assignment) == 0 {
+		return pos, nil
+	}
+	if err := consumer.Assign(assignment); err != nil {
+		return nil, err
+	}
+
+	for len(end) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		switch ev := consumer.Poll(int(pollTimeout.Milliseconds())).(type) {
+		case kafka.PartitionEOF:
+			if high, ok := end[ev.Partition]; ok && int64(ev.Offset) >= high {
+				pos[ev.Partition] = high
+				delete(end, ev.Partition)
+			}
+		case kafka.Error:
+			if ev.IsFatal() {
+				return nil, ev
+			}
+		case *kafka.Message:
+			partition, offset := ev.TopicPartition.Partition, int64(ev.TopicPartition.Offset)
+			if _, ok := end[partition]; !ok || offset >= end[partition] {
+				continue
+			}
+			e, err := ratingevent.Decode(ev.Value, header(ev, ratingevent.HeaderContentType))
+			if err != nil {
+				return nil, fmt.Errorf("decode event %d of partition %d: %w", offset, partition, err)
+			}
+			if err := fn(*e); err != nil {
+				return nil, err
+			}
+			pos[partition] = offset + 1
+			if offset+1 >= end[partition] {
+				delete(end, partition)
+			}
+		}
+	}
+	return pos, nil
+}
+
+func header(msg *kafka.Message, key string) string {
+	for _, h := range msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Close waits for pending events to be delivered and closes the producer.
+func (l *Log) Close() {
+	l.producer.Flush(int(requestTimeout.Milliseconds()))
+	l.producer.Close()
+}
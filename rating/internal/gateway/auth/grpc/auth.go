@@ -0,0 +1,56 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/abhishek622/movieapp/gen"
+	"github.com/abhishek622/movieapp/rating/internal/gateway"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+// Gateway defines a gRPC gateway for the auth service. The auth service is
+// not registered in service discovery, so it is called at a fixed address
+// with a single long-lived connection.
+type Gateway struct {
+	addr  string
+	creds credentials.TransportCredentials
+
+	once    sync.Once
+	conn    *grpc.ClientConn
+	connErr error
+}
+
+// New creates a new gRPC gateway for the auth service at addr.
+func New(addr string, creds credentials.TransportCredentials) *Gateway {
+	return &Gateway{addr: addr, creds: creds}
+}
+
+// connection returns the connection to the auth service, created on first use.
+func (g *Gateway) connection() (*grpc.ClientConn, error) {
+	g.once.Do(func() {
+		g.conn, g.connErr = grpc.NewClient(g.addr, grpc.WithTransportCredentials(g.creds), grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
+	})
+	return g.conn, g.connErr
+}
+
+// ValidateToken returns the username of a valid auth token, or
+// ErrUnauthenticated if the token is rejected.
+func (g *Gateway) ValidateToken(ctx context.Context, token string) (string, error) {
+	conn, err := g.connection()
+	if err != nil {
+		return "", err
+	}
+	client := gen.NewAuthServiceClient(conn)
+	resp, err := client.ValidateToken(ctx, &gen.ValidateTokenRequest{Token: token})
+	if err != nil && status.Code(err) == codes.Unauthenticated {
+		return "", gateway.ErrUnauthenticated
+	} else if err != nil {
+		return "", err
+	}
+	return resp.Username, nil
+}
@@ -0,0 +1,6 @@
+package gateway
+
+import "errors"
+
+// ErrUnauthenticated is returned when an auth token is rejected.
+var ErrUnauthenticated = errors.New("unauthenticated")
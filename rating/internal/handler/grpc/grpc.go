@@ -3,37 +3,55 @@ package grpc
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/abhishek622/movieapp/gen"
 	"github.com/abhishek622/movieapp/rating/internal/controller/rating"
+	"github.com/abhishek622/movieapp/rating/internal/controller/review"
 	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // Handler defines a gRPC rating API handler.
 type Handler struct {
 	gen.UnimplementedRatingServiceServer
-	ctrl *rating.Controller
+	ctrl    *rating.Controller
+	reviews *review.Controller
 }
 
 // New creates a new rating gRPC handler.
-func New(ctrl *rating.Controller) *Handler {
-	return &Handler{ctrl: ctrl}
+func New(ctrl *rating.Controller, reviews *review.Controller) *Handler {
+	return &Handler{ctrl: ctrl, reviews: reviews}
 }
 
-// GetAggregatedRating returns the aggregated rating for a record.
+// GetAggregatedRating returns the aggregated rating for a record, or for its
+// child records if rolled up, and the aggregated rating of every provider if requested.
 func (h *Handler) GetAggregatedRating(ctx context.Context, req *gen.GetAggregatedRatingRequest) (*gen.GetAggregatedRatingResponse, error) {
 	if req == nil || req.RecordId == "" || req.RecordType == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty id/type")
 	}
-	v, err := h.ctrl.GetAggregatedRating(ctx, model.RecordID(req.RecordId), model.RecordType(req.RecordType))
+	get := h.ctrl.GetAggregatedRatingByProvider
+	if req.RollUp {
+		get = h.ctrl.GetRolledUpRating
+	}
+	agg, err := get(ctx, model.RecordID(req.RecordId), model.RecordType(req.RecordType))
 	if err != nil && errors.Is(err, rating.ErrNotFound) {
 		return nil, status.Error(codes.NotFound, err.Error())
+	} else if err != nil && errors.Is(err, rating.ErrInvalidRecord) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	} else if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
-	return &gen.GetAggregatedRatingResponse{RatingValue: v}, nil
+	res := &gen.GetAggregatedRatingResponse{RatingValue: agg.Rating, VoteCount: int32(agg.VoteCount)}
+	if req.IncludeProviders {
+		for i := range agg.Providers {
+			res.Providers = append(res.Providers, model.ProviderRatingToProto(&agg.Providers[i]))
+		}
+	}
+	return res, nil
 }
 
 // PutRating writes a rating for a given record.
@@ -41,8 +59,121 @@ func (h *Handler) PutRating(ctx context.Context, req *gen.PutRatingRequest) (*ge
 	if req == nil || req.RecordId == "" || req.UserId == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty user id or record id")
 	}
-	if err := h.ctrl.PutRating(ctx, model.RecordID(req.RecordId), model.RecordType(req.RecordType), &model.Rating{UserID: model.UserID(req.UserId), Value: model.RatingValue(req.RatingValue)}); err != nil {
+	r := &model.Rating{UserID: model.UserID(req.UserId), Value: model.RatingValue(req.RatingValue), ProviderID: req.ProviderId}
+	if req.UpdatedAt != nil {
+		r.UpdatedAt = req.UpdatedAt.AsTime()
+	}
+	if err := h.ctrl.PutRating(ctx, model.RecordID(req.RecordId), model.RecordType(req.RecordType), r); err != nil && errors.Is(err, rating.ErrInvalidRecord) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 	return &gen.PutRatingResponse{}, nil
 }
+
+// DeleteRating removes the rating of a user for a record.
+func (h *Handler) DeleteRating(ctx context.Context, req *gen.DeleteRatingRequest) (*gen.DeleteRatingResponse, error) {
+	if req == nil || req.RecordId == "" || req.RecordType == "" || req.UserId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty user id, record id or record type")
+	}
+	if err := h.ctrl.DeleteRating(ctx, model.RecordID(req.RecordId), model.RecordType(req.RecordType), model.UserID(req.UserId)); err != nil && errors.Is(err, rating.ErrInvalidRecord) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &gen.DeleteRatingResponse{}, nil
+}
+
+// GetAggregatedRatings returns the aggregated ratings of several records of a
+// given type. Records without ratings are not returned.
+func (h *Handler) GetAggregatedRatings(ctx context.Context, req *gen.GetAggregatedRatingsRequest) (*gen.GetAggregatedRatingsResponse, error) {
+	if req == nil || req.RecordType == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty type")
+	}
+	ids := make([]model.RecordID, len(req.RecordIds))
+	for i, id := range req.RecordIds {
+		ids[i] = model.RecordID(id)
+	}
+	records, err := h.ctrl.GetAggregatedRatings(ctx, ids, model.RecordType(req.RecordType))
+	if err != nil && errors.Is(err, rating.ErrInvalidRecord) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	res := &gen.GetAggregatedRatingsResponse{}
+	for i := range records {
+		res.Records = append(res.Records, model.RatedRecordToProto(&records[i]))
+	}
+	return res, nil
+}
+
+// GetTopRated returns the records of a given type with the highest aggregated rating.
+func (h *Handler) GetTopRated(ctx context.Context, req *gen.GetTopRatedRequest) (*gen.GetTopRatedResponse, error) {
+	if req == nil || req.RecordType == "" || req.Limit < 0 || req.MinVoteCount < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req, empty type or negative limit/min vote count")
+	}
+	records, err := h.ctrl.GetTopRated(ctx, model.RecordType(req.RecordType), int(req.Limit), int(req.MinVoteCount))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	res := &gen.GetTopRatedResponse{}
+	for i := range records {
+		res.Records = append(res.Records, model.RatedRecordToProto(&records[i]))
+	}
+	return res, nil
+}
+
+// WatchAggregatedRating streams the aggregated rating of a record every time it changes.
+func (h *Handler) WatchAggregatedRating(req *gen.WatchAggregatedRatingRequest, stream grpc.ServerStreamingServer[gen.WatchAggregatedRatingResponse]) error {
+	if req == nil || req.RecordId == "" || req.RecordType == "" || req.MinIntervalMs < 0 {
+		return status.Errorf(codes.InvalidArgument, "nil req, empty id/type or negative min interval")
+	}
+	updates, err := h.ctrl.WatchAggregatedRating(stream.Context(), model.RecordID(req.RecordId), model.RecordType(req.RecordType), rating.WatchOptions{
+		MinInterval: time.Duration(req.MinIntervalMs) * time.Millisecond,
+		Coalesce:    req.Coalesce,
+	})
+	if err != nil && errors.Is(err, rating.ErrShutdown) {
+		return status.Error(codes.Unavailable, err.Error())
+	} else if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	for v := range updates {
+		if err := stream.Send(&gen.WatchAggregatedRatingResponse{RatingValue: v}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportRatings streams all ratings matching the request filters in batches.
+func (h *Handler) ExportRatings(req *gen.ExportRatingsRequest, stream grpc.ServerStreamingServer[gen.ExportRatingsResponse]) error {
+	if req == nil {
+		return status.Errorf(codes.InvalidArgument, "nil req")
+	}
+	filter := rating.ExportFilter{RecordType: model.RecordType(req.RecordType), Cursor: req.Cursor}
+	if req.StartTime != nil {
+		filter.StartTime = req.StartTime.AsTime()
+	}
+	if req.EndTime != nil {
+		filter.EndTime = req.EndTime.AsTime()
+	}
+	if req.SnapshotTime != nil {
+		filter.SnapshotTime = req.SnapshotTime.AsTime()
+	}
+	if !filter.StartTime.IsZero() && !filter.EndTime.IsZero() && filter.EndTime.Before(filter.StartTime) {
+		return status.Errorf(codes.InvalidArgument, "end time before start time")
+	}
+	err := h.ctrl.ExportRatings(stream.Context(), filter, func(b *rating.ExportBatch) error {
+		res := &gen.ExportRatingsResponse{Cursor: b.Cursor, SnapshotTime: timestamppb.New(b.SnapshotTime)}
+		for i := range b.Ratings {
+			res.Ratings = append(res.Ratings, model.RatingToExportProto(&b.Ratings[i]))
+		}
+		return stream.Send(res)
+	})
+	if err != nil && errors.Is(err, rating.ErrInvalidCursor) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
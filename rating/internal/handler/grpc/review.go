@@ -0,0 +1,155 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/abhishek622/movieapp/gen"
+	"github.com/abhishek622/movieapp/rating/internal/controller/review"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// CreateReview writes a new review for a record as the user of the bearer
+// token sent in the authorization metadata of the call.
+func (h *Handler) CreateReview(ctx context.Context, req *gen.CreateReviewRequest) (*gen.CreateReviewResponse, error) {
+	if req == nil || req.RecordId == "" || req.RecordType == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty record id or type")
+	}
+	userID, err := h.authenticate(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	r, err := h.reviews.Create(ctx, model.RecordID(req.RecordId), model.RecordType(req.RecordType), userID, req.Text)
+	if err != nil {
+		return nil, reviewError(err)
+	}
+	return &gen.CreateReviewResponse{Review: model.ReviewToProto(r)}, nil
+}
+
+// EditReview changes the text of a review of the authenticated user.
+func (h *Handler) EditReview(ctx context.Context, req *gen.EditReviewRequest) (*gen.EditReviewResponse, error) {
+	if req == nil || req.ReviewId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty review id")
+	}
+	userID, err := h.authenticate(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	r, err := h.reviews.Edit(ctx, model.ReviewID(req.ReviewId), userID, req.Text)
+	if err != nil {
+		return nil, reviewError(err)
+	}
+	return &gen.EditReviewResponse{Review: model.ReviewToProto(r)}, nil
+}
+
+// DeleteReview removes a review of the authenticated user.
+func (h *Handler) DeleteReview(ctx context.Context, req *gen.DeleteReviewRequest) (*gen.DeleteReviewResponse, error) {
+	if req == nil || req.ReviewId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty review id")
+	}
+	userID, err := h.authenticate(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.reviews.Delete(ctx, model.ReviewID(req.ReviewId), userID); err != nil {
+		return nil, reviewError(err)
+	}
+	return &gen.DeleteReviewResponse{}, nil
+}
+
+// ModerateReview approves or rejects a review. The authenticated user must be
+// a moderator.
+func (h *Handler) ModerateReview(ctx context.Context, req *gen.ModerateReviewRequest) (*gen.ModerateReviewResponse, error) {
+	if req == nil || req.ReviewId == "" || req.Status == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty review id or status")
+	}
+	moderator, err := h.authenticate(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	r, err := h.reviews.Moderate(ctx, moderator, model.ReviewID(req.ReviewId), model.ReviewStatus(req.Status))
+	if err != nil {
+		return nil, reviewError(err)
+	}
+	return &gen.ModerateReviewResponse{Review: model.ReviewToProto(r)}, nil
+}
+
+// ListReviews returns a page of approved reviews for a record.
+func (h *Handler) ListReviews(ctx context.Context, req *gen.ListReviewsRequest) (*gen.ListReviewsResponse, error) {
+	if req == nil || req.RecordId == "" || req.RecordType == "" || req.PageSize < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req, empty id/type or negative page size")
+	}
+	reviews, next, err := h.reviews.ListApproved(ctx, model.RecordID(req.RecordId), model.RecordType(req.RecordType), int(req.PageSize), req.PageToken)
+	if err != nil {
+		return nil, reviewError(err)
+	}
+	res := &gen.ListReviewsResponse{NextPageToken: next}
+	for i := range reviews {
+		res.Reviews = append(res.Reviews, model.ReviewToProto(&reviews[i]))
+	}
+	return res, nil
+}
+
+// VoteReviewHelpful marks a review as helpful for the authenticated user.
+func (h *Handler) VoteReviewHelpful(ctx context.Context, req *gen.VoteReviewHelpfulRequest) (*gen.VoteReviewHelpfulResponse, error) {
+	if req == nil || req.ReviewId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty review id")
+	}
+	userID, err := h.authenticate(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	votes, err := h.reviews.VoteHelpful(ctx, model.ReviewID(req.ReviewId), userID)
+	if err != nil {
+		return nil, reviewError(err)
+	}
+	return &gen.VoteReviewHelpfulResponse{HelpfulVotes: int32(votes)}, nil
+}
+
+// authenticate returns the user of the bearer token of an incoming call. The
+// user id sent in a request, which older clients still set, must be the
+// authenticated user if it is not empty.
+func (h *Handler) authenticate(ctx context.Context, requestUserID string) (model.UserID, error) {
+	userID, err := h.reviews.Authenticate(ctx, bearerToken(ctx))
+	if err != nil {
+		return "", reviewError(err)
+	}
+	if requestUserID != "" && model.UserID(requestUserID) != userID {
+		return "", status.Error(codes.PermissionDenied, "user id does not match the auth token")
+	}
+	return userID, nil
+}
+
+// bearerToken returns the token of the "authorization: Bearer <token>"
+// metadata of an incoming call, or an empty string.
+func bearerToken(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get("authorization") {
+		if token, ok := strings.CutPrefix(v, "Bearer "); ok {
+			return token
+		}
+	}
+	return ""
+}
+
+// reviewError converts a review controller error into a gRPC status error.
+func reviewError(err error) error {
+	switch {
+	case errors.Is(err, review.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, review.ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, review.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, review.ErrInvalidReview), errors.Is(err, review.ErrInvalidPageToken):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, review.ErrAlreadyVoted):
+		return status.Error(codes.AlreadyExists, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -1,61 +1,217 @@
 package http
 
 import (
-	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
 
+	"github.com/abhishek622/movieapp/gen"
+	"github.com/abhishek622/movieapp/internal/httputil"
 	"github.com/abhishek622/movieapp/rating/internal/controller/rating"
 	"github.com/abhishek622/movieapp/rating/pkg/model"
 )
 
 type Handler struct {
 	ctrl *rating.Controller
+	// writes enables the PUT and DELETE methods of /rating.
+	writes bool
 }
 
-func New(ctrl *rating.Controller) *Handler {
-	return &Handler{ctrl}
+// New creates a rating HTTP handler. The HTTP API does not authenticate its
+// clients, so ratings are only written through it if writes is set.
+func New(ctrl *rating.Controller, writes bool) *Handler {
+	return &Handler{ctrl, writes}
 }
 
 func (h *Handler) Handle(w http.ResponseWriter, req *http.Request) {
 	recordID := model.RecordID(req.FormValue("id"))
 	if recordID == "" {
-		w.WriteHeader(http.StatusBadRequest)
+		httputil.Error(w, req, http.StatusBadRequest, "empty id")
 		return
 	}
 
 	recordType := model.RecordType(req.FormValue("type"))
 	if recordType == "" {
-		w.WriteHeader(http.StatusBadRequest)
+		httputil.Error(w, req, http.StatusBadRequest, "empty type")
+		return
+	}
+
+	if (req.Method == http.MethodPut || req.Method == http.MethodDelete) && !h.writes {
+		httputil.Error(w, req, http.StatusForbidden, "ratings are not written over HTTP")
 		return
 	}
 
 	switch req.Method {
 	case http.MethodGet:
-		v, err := h.ctrl.GetAggregatedRating(req.Context(), recordID, recordType)
+		// The ratings of the child records, such as the episodes of a series, are aggregated if rolled up.
+		get := h.ctrl.GetAggregatedRatingByProvider
+		if req.FormValue("rollup") == "true" {
+			get = h.ctrl.GetRolledUpRating
+		}
+		agg, err := get(req.Context(), recordID, recordType)
 		if err != nil && errors.Is(err, rating.ErrNotFound) {
-			w.WriteHeader(http.StatusNotFound)
+			httputil.Error(w, req, http.StatusNotFound, err.Error())
 			return
-		}
-		if err := json.NewEncoder(w).Encode(v); err != nil {
-			log.Printf("Response encode error: %v\n", err)
-			w.WriteHeader(http.StatusInternalServerError)
+		} else if err != nil && errors.Is(err, rating.ErrInvalidRecord) {
+			httputil.Error(w, req, http.StatusBadRequest, err.Error())
+			return
+		} else if err != nil {
+			log.Printf("Repository get error: %v\n", err)
+			httputil.Error(w, req, http.StatusInternalServerError, "")
 			return
 		}
+		// The breakdown by provider is only returned if requested.
+		providers := req.FormValue("providers") == "true"
+		if !providers {
+			agg.Providers = nil
+		}
+		msg := &gen.GetAggregatedRatingResponse{RatingValue: agg.Rating, VoteCount: int32(agg.VoteCount)}
+		for i := range agg.Providers {
+			msg.Providers = append(msg.Providers, model.ProviderRatingToProto(&agg.Providers[i]))
+		}
+		// JSON clients get the bare rating unless they request the breakdown.
+		var v any = agg
+		if format, err := httputil.Negotiate(req); err == nil && format == httputil.FormatJSON && !providers {
+			v = agg.Rating
+		}
+		httputil.Respond(w, req, "aggregatedRating", v, msg)
 	case http.MethodPut:
 		userID := model.UserID(req.FormValue("userId"))
 		v, err := strconv.ParseFloat(req.FormValue("value"), 64)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			httputil.Error(w, req, http.StatusBadRequest, "invalid value")
 			return
 		}
-		if err := h.ctrl.PutRating(req.Context(), recordID, recordType, &model.Rating{UserID: userID, Value: model.RatingValue(v)}); err != nil {
+		if err := h.ctrl.PutRating(req.Context(), recordID, recordType, &model.Rating{UserID: userID, Value: model.RatingValue(v)}); err != nil && errors.Is(err, rating.ErrInvalidRecord) {
+			httputil.Error(w, req, http.StatusBadRequest, err.Error())
+		} else if err != nil {
 			log.Printf("Repository put error: %v\n", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			httputil.Error(w, req, http.StatusInternalServerError, "")
+		}
+	case http.MethodDelete:
+		userID := model.UserID(req.FormValue("userId"))
+		if userID == "" {
+			httputil.Error(w, req, http.StatusBadRequest, "empty userId")
+			return
+		}
+		if err := h.ctrl.DeleteRating(req.Context(), recordID, recordType, userID); err != nil && errors.Is(err, rating.ErrInvalidRecord) {
+			httputil.Error(w, req, http.StatusBadRequest, err.Error())
+		} else if err != nil {
+			log.Printf("Repository delete error: %v\n", err)
+			httputil.Error(w, req, http.StatusInternalServerError, "")
 		}
 	default:
-		w.WriteHeader(http.StatusBadRequest)
+		httputil.Error(w, req, http.StatusBadRequest, fmt.Sprintf("unsupported method %s", req.Method))
+	}
+}
+
+// GetBatch handles GET /rating/batch requests. It returns the aggregated
+// ratings of the records of a given type whose ids are set by repeated id
+// parameters. Records without ratings are not returned.
+func (h *Handler) GetBatch(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		httputil.Error(w, req, http.StatusBadRequest, fmt.Sprintf("unsupported method %s", req.Method))
+		return
+	}
+	recordType := model.RecordType(req.FormValue("type"))
+	if recordType == "" {
+		httputil.Error(w, req, http.StatusBadRequest, "empty type")
+		return
+	}
+	var ids []model.RecordID
+	for _, id := range req.Form["id"] {
+		ids = append(ids, model.RecordID(id))
+	}
+	records, err := h.ctrl.GetAggregatedRatings(req.Context(), ids, recordType)
+	if err != nil && errors.Is(err, rating.ErrInvalidRecord) {
+		httputil.Error(w, req, http.StatusBadRequest, err.Error())
+		return
+	} else if err != nil {
+		log.Printf("Repository get error: %v\n", err)
+		httputil.Error(w, req, http.StatusInternalServerError, "")
+		return
+	}
+	if records == nil {
+		records = []model.RatedRecord{}
+	}
+	msg := &gen.GetAggregatedRatingsResponse{}
+	for i := range records {
+		msg.Records = append(msg.Records, model.RatedRecordToProto(&records[i]))
+	}
+	httputil.Respond(w, req, "ratedRecords>ratedRecord", records, msg)
+}
+
+// GetTopRated handles GET /rating/top requests.
+func (h *Handler) GetTopRated(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		httputil.Error(w, req, http.StatusBadRequest, fmt.Sprintf("unsupported method %s", req.Method))
+		return
+	}
+	recordType := model.RecordType(req.FormValue("type"))
+	if recordType == "" {
+		httputil.Error(w, req, http.StatusBadRequest, "empty type")
+		return
+	}
+	limit, err := intFormValue(req, "limit")
+	if err != nil {
+		httputil.Error(w, req, http.StatusBadRequest, err.Error())
+		return
+	}
+	minVoteCount, err := intFormValue(req, "minVotes")
+	if err != nil {
+		httputil.Error(w, req, http.StatusBadRequest, err.Error())
+		return
+	}
+	records, err := h.ctrl.GetTopRated(req.Context(), recordType, limit, minVoteCount)
+	if err != nil {
+		log.Printf("Repository get error: %v\n", err)
+		httputil.Error(w, req, http.StatusInternalServerError, "")
+		return
+	}
+	if records == nil {
+		records = []model.RatedRecord{}
+	}
+	msg := &gen.GetTopRatedResponse{}
+	for i := range records {
+		msg.Records = append(msg.Records, model.RatedRecordToProto(&records[i]))
+	}
+	httputil.Respond(w, req, "ratedRecords>ratedRecord", records, msg)
+}
+
+// IngestionStatus handles GET /admin/ingestion requests. It returns the
+// partitions assigned to the ingester and their committed offsets. The
+// status has no protobuf representation.
+func (h *Handler) IngestionStatus(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		httputil.Error(w, req, http.StatusBadRequest, fmt.Sprintf("unsupported method %s", req.Method))
+		return
+	}
+	partitions, err := h.ctrl.IngestionStatus(req.Context())
+	if err != nil && (errors.Is(err, rating.ErrNoIngester) || errors.Is(err, errors.ErrUnsupported)) {
+		httputil.Error(w, req, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		log.Printf("Ingestion status error: %v\n", err)
+		httputil.Error(w, req, http.StatusInternalServerError, "")
+		return
+	}
+	httputil.Respond(w, req, "partitions>partition", partitions, nil)
+}
+
+// intFormValue parses an optional non-negative integer form value.
+func intFormValue(req *http.Request, key string) (int, error) {
+	v := req.FormValue(key)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("negative %s: %d", key, n)
 	}
+	return n, nil
 }
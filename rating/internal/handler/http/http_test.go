@@ -0,0 +1,70 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abhishek622/movieapp/rating/internal/controller/rating"
+	"github.com/abhishek622/movieapp/rating/internal/repository/memory"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRating(t *testing.T) {
+	ctrl := rating.New(memory.New(), nil, nil)
+	for _, r := range []model.Rating{{UserID: "u1", Value: 4}, {UserID: "u2", Value: 5}} {
+		require.NoError(t, ctrl.PutRating(context.Background(), "1", model.RecordTypeMovie, &r))
+	}
+	h := New(ctrl, false)
+	get := func(query, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/rating?"+query, nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		h.Handle(w, req)
+		return w
+	}
+
+	w := get("id=1&type=movie", "application/json")
+	require.Equal(t, http.StatusOK, w.Code)
+	var v float64
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&v))
+	assert.Equal(t, 4.5, v)
+
+	w = get("id=1&type=movie&providers=true", "application/json")
+	require.Equal(t, http.StatusOK, w.Code)
+	var agg model.AggregatedRating
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&agg))
+	assert.Equal(t, 4.5, agg.Rating)
+	assert.Equal(t, 2, agg.VoteCount)
+	assert.Len(t, agg.Providers, 1)
+
+	w = get("id=1&type=movie", "application/xml")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "<aggregatedRating><rating>4.5</rating><voteCount>2</voteCount>")
+
+	w = get("id=2&type=movie", "application/json")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPutRating(t *testing.T) {
+	ctrl := rating.New(memory.New(), nil, nil)
+	put := func(h *Handler) int {
+		w := httptest.NewRecorder()
+		h.Handle(w, httptest.NewRequest(http.MethodPut, "/rating?id=1&type=movie&userId=u1&value=4", nil))
+		return w.Code
+	}
+
+	// Ratings are only written over HTTP if writes are enabled.
+	assert.Equal(t, http.StatusForbidden, put(New(ctrl, false)))
+	_, err := ctrl.GetAggregatedRating(context.Background(), "1", model.RecordTypeMovie)
+	assert.ErrorIs(t, err, rating.ErrNotFound)
+
+	assert.Equal(t, http.StatusOK, put(New(ctrl, true)))
+	v, err := ctrl.GetAggregatedRating(context.Background(), "1", model.RecordTypeMovie)
+	require.NoError(t, err)
+	assert.Equal(t, 4.0, v)
+}
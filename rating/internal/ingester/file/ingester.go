@@ -0,0 +1,131 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/abhishek622/movieapp/rating/internal/ingester"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"go.uber.org/zap"
+)
+
+// Ingester defines an ingester reading rating events from a file. The file
+// contains either a JSON array of events or newline-delimited JSON events.
+type Ingester struct {
+	path   string
+	logger *zap.Logger
+}
+
+// NewIngester creates a new file ingester.
+func NewIngester(path string, logger *zap.Logger) *Ingester {
+	return &Ingester{path: path, logger: logger.With(zap.String("component", "file_ingester"), zap.String("path", path))}
+}
+
+// Ingest starts ingestion from the file and returns a channel containing
+// messages with the rating events read from it. The channel is closed once the
+// whole file is read. Rejected events are logged.
+func (i *Ingester) Ingest(ctx context.Context) (chan ingester.Message, error) {
+	i.logger.Info("Starting file ingester")
+	f, err := os.Open(i.path)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan ingester.Message, 1)
+	go func() {
+		defer close(ch)
+		defer f.Close()
+		r := bufio.NewReader(f)
+		send := func(event model.RatingEvent) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case ch <- ingester.NewMessage(event, nil, i.reject(event)):
+				return true
+			}
+		}
+		array, err := isArray(r)
+		if err != nil {
+			i.logger.Error("Failed to read file", zap.Error(err))
+			return
+		}
+		if array {
+			i.readArray(r, send)
+			return
+		}
+		i.readLines(r, send)
+	}()
+	return ch, nil
+}
+
+func (i *Ingester) reject(event model.RatingEvent) func(error) error {
+	return func(reason error) error {
+		i.logger.Warn("Rejected event", zap.Any("event", event), zap.NamedError("reason", reason))
+		return nil
+	}
+}
+
+// isArray reports whether the first non-space character of r starts a JSON array.
+func isArray(r *bufio.Reader) (bool, error) {
+	for {
+		b, err := r.Peek(1)
+		if err == io.EOF {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			r.ReadByte()
+		default:
+			return b[0] == '[', nil
+		}
+	}
+}
+
+func (i *Ingester) readArray(r io.Reader, send func(model.RatingEvent) bool) {
+	dec := json.NewDecoder(r)
+	if _, err := dec.Token(); err != nil {
+		i.logger.Error("Failed to decode event array", zap.Error(err))
+		return
+	}
+	for dec.More() {
+		var event model.RatingEvent
+		if err := dec.Decode(&event); err != nil {
+			i.logger.Error("Failed to decode event", zap.Error(err))
+			return
+		}
+		if !send(event) {
+			return
+		}
+	}
+}
+
+// readLines reads newline-delimited events. Malformed lines are skipped.
+func (i *Ingester) readLines(r io.Reader, send func(model.RatingEvent) bool) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		b := bytes.TrimSpace(scanner.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		var event model.RatingEvent
+		if err := json.Unmarshal(b, &event); err != nil {
+			i.logger.Warn("Skipping malformed event", zap.Int("line", line), zap.Error(err))
+			continue
+		}
+		if !send(event) {
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		i.logger.Error("Failed to read file", zap.Error(err))
+	}
+}
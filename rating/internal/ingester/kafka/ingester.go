@@ -2,62 +2,393 @@ package kafka
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"strconv"
+	"sync"
+	"time"
 
+	"github.com/abhishek622/movieapp/pkg/schemaregistry"
+	"github.com/abhishek622/movieapp/rating/internal/ingester"
 	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/abhishek622/movieapp/rating/pkg/ratingevent"
 	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/uber-go/tally/v4"
+	"go.uber.org/zap"
+)
+
+// Dead-letter message headers describing why and where from a message was rejected.
+const (
+	HeaderError             = "x-error"
+	HeaderOriginalTopic     = "x-original-topic"
+	HeaderOriginalPartition = "x-original-partition"
+	HeaderOriginalOffset    = "x-original-offset"
+	HeaderFailedAt          = "x-failed-at"
+)
+
+const (
+	// deadLetterRetryInterval is the time between attempts to write to the dead-letter topic.
+	deadLetterRetryInterval = time.Second
+	// pollTimeout is the maximum time to wait for a message before checking whether ingestion is stopped.
+	pollTimeout = 100 * time.Millisecond
+	// messageBufferSize is the number of consumed messages waiting to be processed.
+	messageBufferSize = 64
+	// shutdownTimeout is the maximum time to wait for consumed messages to be
+	// processed after ingestion is stopped so that their offsets can be committed.
+	shutdownTimeout = 10 * time.Second
 )
 
 // Ingester defines a Kafka ingester.
 type Ingester struct {
-	consumer *kafka.Consumer
-	topic    string
+	consumer        *kafka.Consumer
+	producer        *kafka.Producer
+	topic           string
+	deadLetterTopic string
+	offsets         *offsetTracker
+	metrics         *metrics
+	logger          *zap.Logger
+	registry        *schemaregistry.Registry
+	// schemaErrors caches the compatibility of schema versions read from message headers.
+	schemaErrors map[int]error
+
+	commitMu  sync.Mutex
+	committed map[int32]int64
+}
+
+type metrics struct {
+	scope            tally.Scope
+	consumed         tally.Counter
+	consumerErrors   tally.Counter
+	decodeErrors     tally.Counter
+	deadLettered     tally.Counter
+	deadLetterErrors tally.Counter
+	commits          tally.Counter
+	commitErrors     tally.Counter
+}
+
+func newMetrics(scope tally.Scope) *metrics {
+	scope = scope.Tagged(map[string]string{"component": "kafka_ingester"})
+	return &metrics{
+		scope:            scope,
+		consumed:         scope.Counter("consumed"),
+		consumerErrors:   scope.Tagged(map[string]string{"error": "consumer"}).Counter("error"),
+		decodeErrors:     scope.Tagged(map[string]string{"error": "decode"}).Counter("error"),
+		deadLettered:     scope.Counter("dead_lettered"),
+		deadLetterErrors: scope.Tagged(map[string]string{"error": "dead_letter"}).Counter("error"),
+		commits:          scope.Counter("commit"),
+		commitErrors:     scope.Tagged(map[string]string{"error": "commit"}).Counter("error"),
+	}
 }
 
-// NewIngester creates a new Kafka ingester.
-func NewIngester(addr string, groupID string, topic string) (*Ingester, error) {
+// lag returns the consumer lag gauge of a partition.
+func (m *metrics) lag(partition int32) tally.Gauge {
+	return m.scope.Tagged(map[string]string{"partition": strconv.Itoa(int(partition))}).Gauge("consumer_lag")
+}
+
+// NewIngester creates a new Kafka ingester. Messages that cannot be processed
+// are written to deadLetterTopic, or skipped if it is empty. If registry is
+// set, messages written with a schema version that is not compatible with the
+// rating event schema of the ingester are rejected.
+func NewIngester(addr string, groupID string, topic string, deadLetterTopic string, registry *schemaregistry.Registry, scope tally.Scope, logger *zap.Logger) (*Ingester, error) {
 	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
-		"bootstrap.servers": addr,
-		"group.id":          groupID,
-		"auto.offset.reset": "earliest",
+		"bootstrap.servers":  addr,
+		"group.id":           groupID,
+		"auto.offset.reset":  "earliest",
+		"enable.auto.commit": false,
 	})
 	if err != nil {
 		return nil, err
 	}
-	return &Ingester{consumer, topic}, nil
+	var producer *kafka.Producer
+	if deadLetterTopic != "" {
+		producer, err = kafka.NewProducer(&kafka.ConfigMap{
+			"bootstrap.servers":  addr,
+			"enable.idempotence": true,
+		})
+		if err != nil {
+			consumer.Close()
+			return nil, err
+		}
+	}
+	return &Ingester{
+		consumer:        consumer,
+		producer:        producer,
+		topic:           topic,
+		deadLetterTopic: deadLetterTopic,
+		offsets:         newOffsetTracker(),
+		metrics:         newMetrics(scope),
+		registry:        registry,
+		schemaErrors:    map[int]error{},
+		logger:          logger.With(zap.String("component", "kafka_ingester"), zap.String("topic", topic)),
+		committed:       map[int32]int64{},
+	}, nil
 }
 
-// Ingest starts ingestion from Kafka and returns a channel containing rating events
-// representing the data consumed from the topic.
-func (i *Ingester) Ingest(ctx context.Context) (chan model.RatingEvent, error) {
-	fmt.Println("Starting Kafka ingester")
-	if err := i.consumer.SubscribeTopics([]string{i.topic}, nil); err != nil {
+// Ingest starts ingestion from Kafka and returns a channel containing messages
+// with the rating events consumed from the topic. Messages may be acknowledged
+// in any order. The offset of a message is committed once it and all messages
+// before it in its partition are acknowledged or rejected.
+func (i *Ingester) Ingest(ctx context.Context) (chan ingester.Message, error) {
+	i.logger.Info("Starting Kafka ingester")
+	if err := i.consumer.SubscribeTopics([]string{i.topic}, i.rebalance); err != nil {
 		return nil, err
 	}
 
-	ch := make(chan model.RatingEvent, 1)
+	ch := make(chan ingester.Message, messageBufferSize)
 	go func() {
+		defer i.close(ch)
 		for {
 			select {
 			case <-ctx.Done():
-				close(ch)
-				i.consumer.Close()
 				return
 			default:
 			}
-			msg, err := i.consumer.ReadMessage(-1)
+			msg, err := i.consumer.ReadMessage(pollTimeout)
 			if err != nil {
-				fmt.Println("Consumer error: " + err.Error())
+				if kerr, ok := err.(kafka.Error); ok && kerr.Code() == kafka.ErrTimedOut {
+					continue
+				}
+				i.metrics.consumerErrors.Inc(1)
+				i.logger.Warn("Failed to read message", zap.Error(err))
 				continue
 			}
-			var event model.RatingEvent
-			if err := json.Unmarshal(msg.Value, &event); err != nil {
-				fmt.Println("Unmarshal error: " + err.Error())
+			i.metrics.consumed.Inc(1)
+			i.reportLag(msg)
+			offsets := i.offsets.track(msg.TopicPartition.Partition, int64(msg.TopicPartition.Offset))
+			event, err := i.decode(msg)
+			if err != nil {
+				i.metrics.decodeErrors.Inc(1)
+				i.logger.Warn("Failed to decode message", zap.Int32("partition", msg.TopicPartition.Partition), zap.Stringer("offset", msg.TopicPartition.Offset), zap.Error(err))
+				if err := i.reject(ctx, msg, offsets, err); err != nil {
+					return
+				}
 				continue
 			}
-			ch <- event
+			m := ingester.NewMessage(*event,
+				func() error { return i.commit(msg, offsets) },
+				func(reason error) error { return i.reject(ctx, msg, offsets, reason) },
+			)
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- m:
+			}
 		}
 	}()
 	return ch, nil
 }
+
+// decode decodes the rating event of a message according to its content type.
+func (i *Ingester) decode(msg *kafka.Message) (*model.RatingEvent, error) {
+	if v := header(msg, ratingevent.HeaderSchemaVersion); v != "" && i.registry != nil {
+		version, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema version %q", v)
+		}
+		if err := i.checkSchema(version); err != nil {
+			return nil, err
+		}
+	}
+	return ratingevent.Decode(msg.Value, header(msg, ratingevent.HeaderContentType))
+}
+
+// checkSchema verifies that the rating event schema of the ingester can read
+// messages written with a registered schema version.
+func (i *Ingester) checkSchema(version int) error {
+	if err, ok := i.schemaErrors[version]; ok {
+		return err
+	}
+	md, err := i.registry.Get(ratingevent.Subject, version)
+	if err == nil {
+		err = schemaregistry.CheckCompatibility(md, ratingevent.Descriptor())
+	}
+	if err != nil {
+		err = fmt.Errorf("schema version %d: %w", version, err)
+		i.logger.Error("Incompatible rating event schema", zap.Int("version", version), zap.Error(err))
+	}
+	i.schemaErrors[version] = err
+	return err
+}
+
+func header(msg *kafka.Message, key string) string {
+	for _, h := range msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// commit marks a message as processed and commits the offsets that are
+// no longer preceded by unprocessed messages. Offsets stay pending until they
+// are committed, so that a failed commit is retried by the next commit of the
+// partition, or when commit is called again for the message.
+func (i *Ingester) commit(msg *kafka.Message, offsets *partitionOffsets) error {
+	partition := msg.TopicPartition.Partition
+	i.commitMu.Lock()
+	defer i.commitMu.Unlock()
+	offset, ok := i.offsets.complete(partition, offsets, int64(msg.TopicPartition.Offset))
+	if !ok {
+		return nil
+	}
+	if committed, ok := i.committed[partition]; ok && committed >= offset {
+		i.offsets.advance(partition, offsets, offset)
+		return nil
+	}
+	if _, err := i.consumer.CommitOffsets([]kafka.TopicPartition{{
+		Topic:     msg.TopicPartition.Topic,
+		Partition: partition,
+		Offset:    kafka.Offset(offset + 1),
+	}}); err != nil {
+		i.metrics.commitErrors.Inc(1)
+		i.logger.Warn("Failed to commit offset", zap.Int32("partition", partition), zap.Int64("offset", offset), zap.Error(err))
+		return err
+	}
+	i.metrics.commits.Inc(1)
+	i.committed[partition] = offset
+	i.offsets.advance(partition, offsets, offset)
+	return nil
+}
+
+// rebalance resets the offsets of the partitions assigned to or revoked from
+// the consumer. The messages of a revoked partition that are still processed
+// are not committed, and are redelivered to the consumer it is assigned to.
+// The library assigns the partitions after the callback returns.
+func (i *Ingester) rebalance(_ *kafka.Consumer, ev kafka.Event) error {
+	var partitions []kafka.TopicPartition
+	switch e := ev.(type) {
+	case kafka.AssignedPartitions:
+		partitions = e.Partitions
+	case kafka.RevokedPartitions:
+		partitions = e.Partitions
+	default:
+		return nil
+	}
+	i.logger.Info("Rebalanced partitions", zap.Stringer("event", ev))
+	i.commitMu.Lock()
+	defer i.commitMu.Unlock()
+	for _, tp := range partitions {
+		i.offsets.reset(tp.Partition)
+		delete(i.committed, tp.Partition)
+	}
+	return nil
+}
+
+// reportLag updates the lag of the partition of a consumed message from the
+// high watermark known to the consumer.
+func (i *Ingester) reportLag(msg *kafka.Message) {
+	_, high, err := i.consumer.GetWatermarkOffsets(*msg.TopicPartition.Topic, msg.TopicPartition.Partition)
+	if err != nil || high < 0 {
+		return
+	}
+	i.metrics.lag(msg.TopicPartition.Partition).Update(float64(max(high-int64(msg.TopicPartition.Offset)-1, 0)))
+}
+
+// Partitions returns the status of the partitions currently assigned to the ingester.
+func (i *Ingester) Partitions(ctx context.Context) ([]ingester.PartitionStatus, error) {
+	assigned, err := i.consumer.Assignment()
+	if err != nil {
+		return nil, err
+	}
+	if len(assigned) == 0 {
+		return []ingester.PartitionStatus{}, nil
+	}
+	timeout := 5 * time.Second
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	committed, err := i.consumer.Committed(assigned, int(timeout.Milliseconds()))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]ingester.PartitionStatus, 0, len(committed))
+	for _, tp := range committed {
+		status := ingester.PartitionStatus{
+			Topic:           *tp.Topic,
+			Partition:       tp.Partition,
+			CommittedOffset: int64(tp.Offset),
+			HighWatermark:   -1,
+			InFlight:        i.offsets.partitionInFlight(tp.Partition),
+		}
+		if tp.Offset < 0 {
+			status.CommittedOffset = -1
+		}
+		if _, high, err := i.consumer.GetWatermarkOffsets(*tp.Topic, tp.Partition); err == nil {
+			status.HighWatermark = high
+			if status.CommittedOffset >= 0 {
+				status.Lag = max(high-status.CommittedOffset, 0)
+			}
+		}
+		res = append(res, status)
+	}
+	return res, nil
+}
+
+// reject writes a message to the dead-letter topic and commits its offset.
+// Writing is retried until it succeeds or ctx is done.
+func (i *Ingester) reject(ctx context.Context, msg *kafka.Message, offsets *partitionOffsets, reason error) error {
+	if i.producer == nil {
+		i.logger.Warn("Skipping message", zap.Int32("partition", msg.TopicPartition.Partition), zap.Stringer("offset", msg.TopicPartition.Offset), zap.NamedError("reason", reason))
+		return i.commit(msg, offsets)
+	}
+	for {
+		err := i.deadLetter(msg, reason)
+		if err == nil {
+			i.metrics.deadLettered.Inc(1)
+			i.logger.Warn("Moved message to the dead-letter topic", zap.Int32("partition", msg.TopicPartition.Partition), zap.Stringer("offset", msg.TopicPartition.Offset), zap.NamedError("reason", reason))
+			return i.commit(msg, offsets)
+		}
+		i.metrics.deadLetterErrors.Inc(1)
+		i.logger.Error("Failed to write to the dead-letter topic", zap.String("deadLetterTopic", i.deadLetterTopic), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(deadLetterRetryInterval):
+		}
+	}
+}
+
+func (i *Ingester) deadLetter(msg *kafka.Message, reason error) error {
+	headers := append([]kafka.Header{}, msg.Headers...)
+	headers = append(headers,
+		kafka.Header{Key: HeaderError, Value: []byte(reason.Error())},
+		kafka.Header{Key: HeaderOriginalTopic, Value: []byte(*msg.TopicPartition.Topic)},
+		kafka.Header{Key: HeaderOriginalPartition, Value: []byte(strconv.Itoa(int(msg.TopicPartition.Partition)))},
+		kafka.Header{Key: HeaderOriginalOffset, Value: []byte(msg.TopicPartition.Offset.String())},
+		kafka.Header{Key: HeaderFailedAt, Value: []byte(time.Now().UTC().Format(time.RFC3339Nano))},
+	)
+	delivery := make(chan kafka.Event, 1)
+	if err := i.producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &i.deadLetterTopic, Partition: kafka.PartitionAny},
+		Key:            msg.Key,
+		Value:          msg.Value,
+		Headers:        headers,
+	}, delivery); err != nil {
+		return err
+	}
+	report, ok := (<-delivery).(*kafka.Message)
+	if !ok {
+		return fmt.Errorf("unexpected delivery report for the dead-letter topic")
+	}
+	return report.TopicPartition.Error
+}
+
+// close closes the message channel, waits for the messages that were already
+// received from it to be processed and closes the consumer.
+func (i *Ingester) close(ch chan ingester.Message) {
+	close(ch)
+	// Messages left in the channel are never processed. They are consumed again
+	// after a restart because their offsets are not committed.
+	unprocessed := 0
+	for range ch {
+		unprocessed++
+	}
+	deadline := time.Now().Add(shutdownTimeout)
+	for i.offsets.inFlight() > unprocessed && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	i.consumer.Close()
+	if i.producer != nil {
+		i.producer.Flush(5000)
+		i.producer.Close()
+	}
+}
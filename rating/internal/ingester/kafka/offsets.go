@@ -0,0 +1,104 @@
+package kafka
+
+import "sync"
+
+// offsetTracker tracks the consumed messages of each partition that are not
+// committed yet. Messages can be processed out of order, so an offset is only
+// committed once all messages before it in the partition are processed.
+type offsetTracker struct {
+	mu         sync.Mutex
+	partitions map[int32]*partitionOffsets
+}
+
+// partitionOffsets are the offsets of a partition consumed since it was
+// assigned to the consumer.
+type partitionOffsets struct {
+	// pending holds the consumed offsets that are not committed, in the order
+	// they were consumed.
+	pending []int64
+	done    map[int64]bool
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{partitions: map[int32]*partitionOffsets{}}
+}
+
+// track records a consumed message. It returns the offsets of its partition,
+// with which the message is completed.
+func (t *offsetTracker) track(partition int32, offset int64) *partitionOffsets {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.partitions[partition]
+	if !ok {
+		p = &partitionOffsets{done: map[int64]bool{}}
+		t.partitions[partition] = p
+	}
+	p.pending = append(p.pending, offset)
+	return p
+}
+
+// complete marks a message as processed. It returns the offset of the last
+// message of the partition that can be committed, if any. The offsets stay
+// pending until advance is called once they are committed. Messages of a
+// partition that was reset since they were consumed cannot be committed.
+func (t *offsetTracker) complete(partition int32, p *partitionOffsets, offset int64) (int64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.partitions[partition] != p {
+		return 0, false
+	}
+	p.done[offset] = true
+	last, ok := int64(0), false
+	for _, o := range p.pending {
+		if !p.done[o] {
+			break
+		}
+		last, ok = o, true
+	}
+	return last, ok
+}
+
+// advance drops the offsets of a partition up to a committed offset.
+func (t *offsetTracker) advance(partition int32, p *partitionOffsets, committed int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.partitions[partition] != p {
+		return
+	}
+	for len(p.pending) > 0 && p.done[p.pending[0]] && p.pending[0] <= committed {
+		delete(p.done, p.pending[0])
+		p.pending = p.pending[1:]
+	}
+}
+
+// reset forgets the offsets of partitions, such as when they are assigned to
+// or revoked from the consumer. Messages consumed before are redelivered
+// from the committed offset.
+func (t *offsetTracker) reset(partitions ...int32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, partition := range partitions {
+		delete(t.partitions, partition)
+	}
+}
+
+// inFlight returns the number of consumed messages that are not committed yet.
+func (t *offsetTracker) inFlight() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n := 0
+	for _, p := range t.partitions {
+		n += len(p.pending)
+	}
+	return n
+}
+
+// partitionInFlight returns the number of consumed messages of a partition that are not committed yet.
+func (t *offsetTracker) partitionInFlight(partition int32) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if p, ok := t.partitions[partition]; ok {
+		return len(p.pending)
+	}
+	return 0
+}
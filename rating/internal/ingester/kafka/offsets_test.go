@@ -0,0 +1,51 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOffsetTracker(t *testing.T) {
+	tr := newOffsetTracker()
+	var p0 *partitionOffsets
+	for _, offset := range []int64{3, 4, 7, 8} {
+		p0 = tr.track(0, offset)
+	}
+	p1 := tr.track(1, 10)
+
+	// Offset 4 cannot be committed while offset 3 is still being processed.
+	_, ok := tr.complete(0, p0, 4)
+	assert.False(t, ok)
+	offset, ok := tr.complete(0, p0, 3)
+	assert.True(t, ok)
+	assert.Equal(t, int64(4), offset)
+
+	// Offsets stay pending until they are committed, so a failed commit is
+	// retried when the message is completed again.
+	offset, ok = tr.complete(0, p0, 3)
+	assert.True(t, ok)
+	assert.Equal(t, int64(4), offset)
+	tr.advance(0, p0, 4)
+	assert.Equal(t, 2, tr.partitionInFlight(0))
+
+	// Gaps between consumed offsets do not block commits.
+	offset, ok = tr.complete(0, p0, 7)
+	assert.True(t, ok)
+	assert.Equal(t, int64(7), offset)
+	tr.advance(0, p0, 7)
+	assert.Equal(t, 2, tr.inFlight())
+
+	// The offsets of a reset partition are forgotten, and the messages
+	// consumed before the reset are not committed.
+	tr.reset(1)
+	assert.Equal(t, 1, tr.inFlight())
+	p1 = tr.track(1, 10)
+	_, ok = tr.complete(1, p0, 10)
+	assert.False(t, ok)
+	offset, ok = tr.complete(1, p1, 10)
+	assert.True(t, ok)
+	assert.Equal(t, int64(10), offset)
+	tr.advance(1, p1, 10)
+	assert.Equal(t, 1, tr.inFlight())
+}
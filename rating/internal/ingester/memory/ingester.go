@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/abhishek622/movieapp/rating/internal/ingester"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+)
+
+// ErrClosed is returned when an event is published to a closed ingester.
+var ErrClosed = errors.New("ingester is closed")
+
+// DeadLetter is an event that was rejected by the consumer of an ingester.
+type DeadLetter struct {
+	Event  model.RatingEvent
+	Reason error
+}
+
+// Ingester defines an in-memory ingester delivering the rating events passed to Publish.
+type Ingester struct {
+	events    chan model.RatingEvent
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mu          sync.Mutex
+	acked       int
+	deadLetters []DeadLetter
+}
+
+// NewIngester creates a new in-memory ingester buffering up to size events.
+func NewIngester(size int) *Ingester {
+	return &Ingester{events: make(chan model.RatingEvent, size), done: make(chan struct{})}
+}
+
+// Publish adds an event to the ingester. It blocks while the buffer is full.
+func (i *Ingester) Publish(ctx context.Context, event model.RatingEvent) error {
+	select {
+	case <-i.done:
+		return ErrClosed
+	default:
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-i.done:
+		return ErrClosed
+	case i.events <- event:
+		return nil
+	}
+}
+
+// Close stops accepting new events. Events published before are still delivered.
+func (i *Ingester) Close() {
+	i.closeOnce.Do(func() { close(i.done) })
+}
+
+// Acked returns the number of acknowledged events.
+func (i *Ingester) Acked() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.acked
+}
+
+// DeadLetters returns the rejected events.
+func (i *Ingester) DeadLetters() []DeadLetter {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return append([]DeadLetter(nil), i.deadLetters...)
+}
+
+// Ingest returns a channel containing messages with the published rating
+// events. The channel is closed when ctx is done or the ingester is closed.
+func (i *Ingester) Ingest(ctx context.Context) (chan ingester.Message, error) {
+	ch := make(chan ingester.Message, 1)
+	go func() {
+		defer close(ch)
+		send := func(event model.RatingEvent) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case ch <- i.message(event):
+				return true
+			}
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-i.events:
+				if !send(event) {
+					return
+				}
+			case <-i.done:
+				// Deliver the events that were published before the ingester was closed.
+				for {
+					select {
+					case event := <-i.events:
+						if !send(event) {
+							return
+						}
+					default:
+						return
+					}
+				}
+			}
+		}
+	}()
+	return ch, nil
+}
+
+func (i *Ingester) message(event model.RatingEvent) ingester.Message {
+	return ingester.NewMessage(event,
+		func() error {
+			i.mu.Lock()
+			defer i.mu.Unlock()
+			i.acked++
+			return nil
+		},
+		func(reason error) error {
+			i.mu.Lock()
+			defer i.mu.Unlock()
+			i.deadLetters = append(i.deadLetters, DeadLetter{event, reason})
+			return nil
+		},
+	)
+}
@@ -0,0 +1,33 @@
+package ingester
+
+import "github.com/abhishek622/movieapp/rating/pkg/model"
+
+// Message is a rating event delivered by an ingester. Each message must be
+// acknowledged once its event is persisted or rejected if it cannot be.
+type Message struct {
+	Event model.RatingEvent
+	ack   func() error
+	nack  func(reason error) error
+}
+
+// NewMessage creates a message. ack and nack may be nil if the source of the
+// event does not track deliveries.
+func NewMessage(event model.RatingEvent, ack func() error, nack func(reason error) error) Message {
+	return Message{Event: event, ack: ack, nack: nack}
+}
+
+// Ack marks the message as processed so that it is not delivered again.
+func (m Message) Ack() error {
+	if m.ack == nil {
+		return nil
+	}
+	return m.ack()
+}
+
+// Nack rejects the message because it cannot be processed.
+func (m Message) Nack(reason error) error {
+	if m.nack == nil {
+		return nil
+	}
+	return m.nack(reason)
+}
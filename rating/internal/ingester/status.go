@@ -0,0 +1,16 @@
+package ingester
+
+// PartitionStatus describes the consumption of a partition assigned to an ingester.
+type PartitionStatus struct {
+	Topic     string `json:"topic" xml:"topic"`
+	Partition int32  `json:"partition" xml:"partition"`
+	// CommittedOffset is the offset of the next message to consume after a
+	// restart, or -1 if no offset was committed yet.
+	CommittedOffset int64 `json:"committedOffset" xml:"committedOffset"`
+	HighWatermark   int64 `json:"highWatermark" xml:"highWatermark"`
+	// Lag is the number of messages in the partition after the committed offset.
+	Lag int64 `json:"lag" xml:"lag"`
+	// InFlight is the number of consumed messages that are not processed or
+	// whose offsets are not committed yet.
+	InFlight int `json:"inFlight" xml:"inFlight"`
+}
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Provider describes a source of ratings and how much its ratings count in
+// aggregated ratings.
+type Provider struct {
+	ID string `json:"id"`
+	// Weight is the weight of every rating of the provider relative to the
+	// ratings of other providers.
+	Weight float64 `json:"weight"`
+	// Enabled tells whether the ratings of the provider are aggregated.
+	Enabled bool `json:"enabled"`
+}
+
+// Registry holds the known rating providers. A registry does not change once
+// created and is safe for concurrent use.
+type Registry struct {
+	def       Provider
+	providers map[string]Provider
+}
+
+// NewRegistry creates a provider registry. Providers that are not registered
+// use the settings of def.
+func NewRegistry(def Provider, providers ...Provider) *Registry {
+	r := &Registry{def: def, providers: map[string]Provider{}}
+	for _, p := range providers {
+		r.providers[p.ID] = p
+	}
+	return r
+}
+
+// Default returns a registry in which all providers are enabled and weigh the same.
+func Default() *Registry {
+	return NewRegistry(Provider{Weight: 1, Enabled: true})
+}
+
+// Get returns the settings of a provider.
+func (r *Registry) Get(id string) Provider {
+	p, ok := r.providers[id]
+	if !ok {
+		p = r.def
+		p.ID = id
+	}
+	return p
+}
+
+// Unregistered returns the settings of the providers that are not registered.
+func (r *Registry) Unregistered() Provider {
+	return r.def
+}
+
+// List returns the registered providers ordered by id.
+func (r *Registry) List() []Provider {
+	return slices.SortedFunc(maps.Values(r.providers), func(a, b Provider) int { return strings.Compare(a.ID, b.ID) })
+}
@@ -3,3 +3,5 @@ package repository
 import "errors"
 
 var ErrNotFound = errors.New("not found")
+
+var ErrAlreadyExists = errors.New("already exists")
@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+)
+
+// Export calls fn for every rating of the given type, or of all types if
+// recordType is empty, that was updated within [since, until]. Zero times
+// leave the range open. Ratings are ordered by their key and start after the
+// given key if set. They are read from a snapshot taken at the beginning of the export.
+func (r *Repository) Export(ctx context.Context, recordType model.RecordType, since time.Time, until time.Time, after *model.RatingKey, fn func(*model.Rating) error) error {
+	type keyedRating struct {
+		key    model.RatingKey
+		rating model.Rating
+	}
+	var snapshot []keyedRating
+	r.RLock()
+	for t, records := range r.data {
+		if recordType != "" && t != recordType {
+			continue
+		}
+		for id, ratings := range records {
+			for _, rating := range ratings {
+				key := model.RatingKey{RecordID: id, RecordType: t, UserID: rating.UserID, ProviderID: rating.ProviderID}
+				if after != nil && !after.Less(key) {
+					continue
+				}
+				if !since.IsZero() && rating.UpdatedAt.Before(since) || !until.IsZero() && rating.UpdatedAt.After(until) {
+					continue
+				}
+				rating.RecordID = string(id)
+				rating.RecordType = string(t)
+				snapshot = append(snapshot, keyedRating{key, rating})
+			}
+		}
+	}
+	r.RUnlock()
+
+	sort.SliceStable(snapshot, func(i, j int) bool { return snapshot[i].key.Less(snapshot[j].key) })
+	for i := range snapshot {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(&snapshot[i].rating); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Tombstones calls fn for every remembered deletion, ordered by rating key.
+func (r *Repository) Tombstones(ctx context.Context, fn func(*model.Tombstone) error) error {
+	r.RLock()
+	tombstones := make([]model.Tombstone, 0, len(r.tombstones))
+	for key, deletedAt := range r.tombstones {
+		tombstones = append(tombstones, model.Tombstone{RatingKey: key, DeletedAt: deletedAt})
+	}
+	r.RUnlock()
+
+	sort.Slice(tombstones, func(i, j int) bool { return tombstones[i].Less(tombstones[j].RatingKey) })
+	for i := range tombstones {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(&tombstones[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package memory
+
+import (
+	"maps"
+	"slices"
+	"sort"
+
+	"github.com/abhishek622/movieapp/rating/internal/repository"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+)
+
+// topRatedIndex keeps the rating sum and count of every provider of the
+// records of a single record type, and the records ranked by their weighted
+// aggregated rating. It is updated on every write so that leaderboard queries
+// read the top of the ranking instead of scanning all the stored ratings.
+// The ranking is built for the provider weights of the queries, which only
+// change with the configuration, and is rebuilt if they change.
+type topRatedIndex struct {
+	byID map[model.RecordID]map[string]*providerAggregate
+	// weights are the provider weights of the ranking, or nil if the records
+	// are not ranked yet.
+	weights *repository.ProviderWeights
+	// ranking holds the records with ratings of enabled providers, best first.
+	ranking []*indexEntry
+	entries map[model.RecordID]*indexEntry
+}
+
+type providerAggregate struct {
+	sum   int64
+	count int
+}
+
+// indexEntry holds the weighted aggregated rating of a record.
+type indexEntry struct {
+	recordID model.RecordID
+	sum      float64
+	weight   float64
+	count    int
+}
+
+func (e *indexEntry) average() float64 {
+	if e.weight == 0 {
+		return 0
+	}
+	return e.sum / e.weight
+}
+
+// less reports whether e ranks before o: higher average first, then more
+// votes, then record id to keep the order stable.
+func (e *indexEntry) less(o *indexEntry) bool {
+	if a, b := e.average(), o.average(); a != b {
+		return a > b
+	}
+	if e.count != o.count {
+		return e.count > o.count
+	}
+	return e.recordID < o.recordID
+}
+
+func newTopRatedIndex() *topRatedIndex {
+	return &topRatedIndex{byID: map[model.RecordID]map[string]*providerAggregate{}}
+}
+
+// add applies a change of the rating sum and count of the ratings of a
+// provider for a record, and moves the record in the ranking.
+func (idx *topRatedIndex) add(recordID model.RecordID, providerID string, sum int64, count int) {
+	providers, ok := idx.byID[recordID]
+	if !ok {
+		providers = map[string]*providerAggregate{}
+		idx.byID[recordID] = providers
+	}
+	p, ok := providers[providerID]
+	if !ok {
+		p = &providerAggregate{}
+		providers[providerID] = p
+	}
+	p.sum += sum
+	p.count += count
+	if p.count <= 0 {
+		delete(providers, providerID)
+		if len(providers) == 0 {
+			delete(idx.byID, recordID)
+		}
+	}
+	if idx.weights != nil {
+		idx.remove(recordID)
+		idx.insert(recordID)
+	}
+}
+
+// entry returns the weighted aggregated rating of a record, or nil if it has
+// no ratings of enabled providers.
+func (idx *topRatedIndex) entry(recordID model.RecordID) *indexEntry {
+	e := &indexEntry{recordID: recordID}
+	for providerID, p := range idx.byID[recordID] {
+		w := idx.weights.Get(providerID)
+		if w <= 0 {
+			continue
+		}
+		e.sum += w * float64(p.sum)
+		e.weight += w * float64(p.count)
+		e.count += p.count
+	}
+	if e.count == 0 {
+		return nil
+	}
+	return e
+}
+
+// insert ranks a record.
+func (idx *topRatedIndex) insert(recordID model.RecordID) {
+	e := idx.entry(recordID)
+	if e == nil {
+		return
+	}
+	i := sort.Search(len(idx.ranking), func(i int) bool { return e.less(idx.ranking[i]) })
+	idx.ranking = slices.Insert(idx.ranking, i, e)
+	idx.entries[recordID] = e
+}
+
+// remove drops a record from the ranking.
+func (idx *topRatedIndex) remove(recordID model.RecordID) {
+	e, ok := idx.entries[recordID]
+	if !ok {
+		return
+	}
+	i := sort.Search(len(idx.ranking), func(i int) bool { return !idx.ranking[i].less(e) })
+	idx.ranking = slices.Delete(idx.ranking, i, i+1)
+	delete(idx.entries, recordID)
+}
+
+// ranked reports whether the records are ranked with the given weights.
+func (idx *topRatedIndex) ranked(weights repository.ProviderWeights) bool {
+	return idx.weights != nil && idx.weights.Default == weights.Default && maps.Equal(idx.weights.Weights, weights.Weights)
+}
+
+// rank ranks all the records with the given weights.
+func (idx *topRatedIndex) rank(weights repository.ProviderWeights) {
+	weights.Weights = maps.Clone(weights.Weights)
+	idx.weights = &weights
+	idx.ranking = nil
+	idx.entries = map[model.RecordID]*indexEntry{}
+	for id := range idx.byID {
+		if e := idx.entry(id); e != nil {
+			idx.ranking = append(idx.ranking, e)
+			idx.entries[id] = e
+		}
+	}
+	sort.Slice(idx.ranking, func(i, j int) bool { return idx.ranking[i].less(idx.ranking[j]) })
+}
+
+// top returns the best ranked records with at least minVoteCount ratings.
+// The records must be ranked.
+func (idx *topRatedIndex) top(recordType model.RecordType, limit int, minVoteCount int) []model.RatedRecord {
+	var res []model.RatedRecord
+	for _, e := range idx.ranking {
+		if len(res) == limit {
+			break
+		}
+		if e.count < minVoteCount {
+			continue
+		}
+		res = append(res, model.RatedRecord{
+			RecordID:   e.recordID,
+			RecordType: recordType,
+			Rating:     e.average(),
+			VoteCount:  e.count,
+		})
+	}
+	return res
+}
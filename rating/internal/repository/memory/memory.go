@@ -2,6 +2,10 @@ package memory
 
 import (
 	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/abhishek622/movieapp/rating/internal/repository"
 	"github.com/abhishek622/movieapp/rating/pkg/model"
@@ -9,30 +13,184 @@ import (
 
 // Repository defines a rating repository.
 type Repository struct {
-	data map[model.RecordType]map[model.RecordID][]model.Rating
+	sync.RWMutex
+	data  map[model.RecordType]map[model.RecordID][]model.Rating
+	index map[model.RecordType]*topRatedIndex
+	// tombstones holds the time of the latest deletion of every deleted rating.
+	tombstones  map[model.RatingKey]time.Time
+	reviews     map[model.ReviewID]*model.Review
+	reviewVotes map[model.ReviewID]map[model.UserID]struct{}
 }
 
 // New creates a new memory repository.
 func New() *Repository {
-	return &Repository{map[model.RecordType]map[model.RecordID][]model.Rating{}}
+	return &Repository{
+		data:        map[model.RecordType]map[model.RecordID][]model.Rating{},
+		index:       map[model.RecordType]*topRatedIndex{},
+		tombstones:  map[model.RatingKey]time.Time{},
+		reviews:     map[model.ReviewID]*model.Review{},
+		reviewVotes: map[model.ReviewID]map[model.UserID]struct{}{},
+	}
 }
 
 // Get retrieves all ratings for a given record.
 func (r *Repository) Get(ctx context.Context, recordID model.RecordID, recordType model.RecordType) ([]model.Rating, error) {
+	r.RLock()
+	defer r.RUnlock()
 	if _, ok := r.data[recordType]; !ok {
 		return nil, repository.ErrNotFound
 	}
 	if ratings, ok := r.data[recordType][recordID]; !ok || len(ratings) == 0 {
 		return nil, repository.ErrNotFound
 	}
-	return r.data[recordType][recordID], nil
+	return slices.Clone(r.data[recordType][recordID]), nil
+}
+
+// GetByPrefix retrieves the ratings of all records of a given type whose id
+// starts with prefix. The record of each rating is set.
+func (r *Repository) GetByPrefix(ctx context.Context, recordType model.RecordType, prefix string) ([]model.Rating, error) {
+	r.RLock()
+	defer r.RUnlock()
+	var res []model.Rating
+	for id, ratings := range r.data[recordType] {
+		if !strings.HasPrefix(string(id), prefix) {
+			continue
+		}
+		for _, rating := range ratings {
+			rating.RecordID = string(id)
+			rating.RecordType = string(recordType)
+			res = append(res, rating)
+		}
+	}
+	return res, nil
 }
 
-// Put adds a rating for a given record.
+// GetBatch retrieves the ratings of several records of a given type. The
+// record of each rating is set. Records without ratings are skipped.
+func (r *Repository) GetBatch(ctx context.Context, recordType model.RecordType, recordIDs []model.RecordID) ([]model.Rating, error) {
+	r.RLock()
+	defer r.RUnlock()
+	var res []model.Rating
+	for _, id := range recordIDs {
+		for _, rating := range r.data[recordType][id] {
+			rating.RecordID = string(id)
+			rating.RecordType = string(recordType)
+			res = append(res, rating)
+		}
+	}
+	return res, nil
+}
+
+// Put adds or replaces the rating of a user of a provider for a given record.
+// The rating is ignored if the stored rating of the user was updated after it,
+// or if the rating of the user was deleted after it.
 func (r *Repository) Put(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) error {
+	r.Lock()
+	defer r.Unlock()
+	r.put(recordID, recordType, rating)
+	return nil
+}
+
+// PutBatch adds or replaces several ratings at once. The record of each rating
+// is set by its RecordID and RecordType fields.
+func (r *Repository) PutBatch(ctx context.Context, ratings []model.Rating) error {
+	r.Lock()
+	defer r.Unlock()
+	for i := range ratings {
+		r.put(model.RecordID(ratings[i].RecordID), model.RecordType(ratings[i].RecordType), &ratings[i])
+	}
+	return nil
+}
+
+func (r *Repository) put(recordID model.RecordID, recordType model.RecordType, rating *model.Rating) {
+	if deletedAt, ok := r.tombstones[model.RatingKey{RecordID: recordID, RecordType: recordType, UserID: rating.UserID, ProviderID: rating.ProviderID}]; ok && deletedAt.After(rating.UpdatedAt) {
+		return
+	}
 	if _, ok := r.data[recordType]; !ok {
 		r.data[recordType] = map[model.RecordID][]model.Rating{}
+		r.index[recordType] = newTopRatedIndex()
 	}
-	r.data[recordType][recordID] = append(r.data[recordType][recordID], *rating)
+	ratings := r.data[recordType][recordID]
+	for i := range ratings {
+		if ratings[i].UserID != rating.UserID || ratings[i].ProviderID != rating.ProviderID {
+			continue
+		}
+		if ratings[i].UpdatedAt.After(rating.UpdatedAt) {
+			return
+		}
+		r.index[recordType].add(recordID, rating.ProviderID, int64(rating.Value-ratings[i].Value), 0)
+		ratings[i] = *rating
+		return
+	}
+	r.data[recordType][recordID] = append(ratings, *rating)
+	r.index[recordType].add(recordID, rating.ProviderID, int64(rating.Value), 1)
+}
+
+// Delete removes the rating of a user of a provider for a given record unless
+// it was updated after deletedAt. Deleting a rating that does not exist is not
+// an error. The deletion is remembered so that older ratings received later
+// are ignored.
+func (r *Repository) Delete(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID, providerID string, deletedAt time.Time) error {
+	r.Lock()
+	defer r.Unlock()
+	ratings := r.data[recordType][recordID]
+	for i := range ratings {
+		if ratings[i].UserID != userID || ratings[i].ProviderID != providerID {
+			continue
+		}
+		if ratings[i].UpdatedAt.After(deletedAt) {
+			return nil
+		}
+		r.index[recordType].add(recordID, providerID, -int64(ratings[i].Value), -1)
+		if len(ratings) == 1 {
+			delete(r.data[recordType], recordID)
+		} else {
+			r.data[recordType][recordID] = slices.Delete(ratings, i, i+1)
+		}
+		break
+	}
+	key := model.RatingKey{RecordID: recordID, RecordType: recordType, UserID: userID, ProviderID: providerID}
+	if t, ok := r.tombstones[key]; !ok || deletedAt.After(t) {
+		r.tombstones[key] = deletedAt
+	}
+	return nil
+}
+
+// Rebuild builds new ratings with build and replaces the ratings, aggregates
+// and tombstones of the repository with them once build succeeds. The current
+// ratings are served until then. Reviews are kept.
+func (r *Repository) Rebuild(ctx context.Context, build func(repository.Projection) error) error {
+	staged := New()
+	if err := build(staged); err != nil {
+		return err
+	}
+	r.Lock()
+	defer r.Unlock()
+	r.data, r.index, r.tombstones = staged.data, staged.index, staged.tombstones
 	return nil
 }
+
+// GetTopRated returns up to limit records of the given type with the highest
+// average rating weighted by the weights of their providers, skipping records
+// with less than minVoteCount weighted ratings. The records are read from a
+// ranking maintained on writes, which is only built by the first query with
+// given weights.
+func (r *Repository) GetTopRated(ctx context.Context, recordType model.RecordType, limit int, minVoteCount int, weights repository.ProviderWeights) ([]model.RatedRecord, error) {
+	r.RLock()
+	idx, ok := r.index[recordType]
+	if ok && idx.ranked(weights) {
+		defer r.RUnlock()
+		return idx.top(recordType, limit, minVoteCount), nil
+	}
+	r.RUnlock()
+	r.Lock()
+	defer r.Unlock()
+	idx, ok = r.index[recordType]
+	if !ok {
+		return nil, nil
+	}
+	if !idx.ranked(weights) {
+		idx.rank(weights)
+	}
+	return idx.top(recordType, limit, minVoteCount), nil
+}
@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/abhishek622/movieapp/rating/internal/repository"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTopRated(t *testing.T) {
+	type put struct {
+		recordID model.RecordID
+		value    model.RatingValue
+	}
+	tests := []struct {
+		name         string
+		puts         []put
+		limit        int
+		minVoteCount int
+		want         []model.RatedRecord
+	}{
+		{
+			name:  "empty",
+			limit: 10,
+		},
+		{
+			name: "ordered by average",
+			puts: []put{
+				{"1", 3}, {"2", 5}, {"3", 4}, {"1", 5},
+			},
+			limit: 10,
+			want: []model.RatedRecord{
+				{RecordID: "2", RecordType: model.RecordTypeMovie, Rating: 5, VoteCount: 1},
+				{RecordID: "1", RecordType: model.RecordTypeMovie, Rating: 4, VoteCount: 2},
+				{RecordID: "3", RecordType: model.RecordTypeMovie, Rating: 4, VoteCount: 1},
+			},
+		},
+		{
+			name: "limit and min vote count",
+			puts: []put{
+				{"1", 3}, {"2", 5}, {"3", 4}, {"1", 5}, {"3", 2}, {"4", 1}, {"4", 1},
+			},
+			limit:        1,
+			minVoteCount: 2,
+			want: []model.RatedRecord{
+				{RecordID: "1", RecordType: model.RecordTypeMovie, Rating: 4, VoteCount: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			r := New()
+			for i, p := range tt.puts {
+				assert.NoError(t, r.Put(ctx, p.recordID, model.RecordTypeMovie, &model.Rating{UserID: model.UserID(fmt.Sprintf("user%d", i)), Value: p.value}))
+			}
+			got, err := r.GetTopRated(ctx, model.RecordTypeMovie, tt.limit, tt.minVoteCount, repository.ProviderWeights{Default: 1})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetTopRatedAfterWrites(t *testing.T) {
+	ctx := context.Background()
+	r := New()
+	weights := repository.ProviderWeights{Default: 1, Weights: map[string]float64{"partner": 0.5, "disabled": 0}}
+	providers := []string{"", "partner", "disabled"}
+	rnd := rand.New(rand.NewPCG(1, 2))
+	now := time.Now()
+	for i := range 2000 {
+		recordID := model.RecordID(fmt.Sprint(rnd.IntN(50)))
+		userID := model.UserID(fmt.Sprint(rnd.IntN(10)))
+		providerID := providers[rnd.IntN(len(providers))]
+		at := now.Add(time.Duration(i) * time.Millisecond)
+		if rnd.IntN(4) == 0 {
+			assert.NoError(t, r.Delete(ctx, recordID, model.RecordTypeMovie, userID, providerID, at))
+		} else {
+			assert.NoError(t, r.Put(ctx, recordID, model.RecordTypeMovie, &model.Rating{UserID: userID, ProviderID: providerID, Value: model.RatingValue(rnd.IntN(5) + 1), UpdatedAt: at}))
+		}
+		if i%100 != 0 {
+			continue
+		}
+		// The ranking maintained on writes matches a ranking built from scratch.
+		got, err := r.GetTopRated(ctx, model.RecordTypeMovie, 10, 3, weights)
+		assert.NoError(t, err)
+		fresh := &topRatedIndex{byID: r.index[model.RecordTypeMovie].byID}
+		fresh.rank(weights)
+		assert.Equal(t, fresh.top(model.RecordTypeMovie, 10, 3), got, "after %d writes", i+1)
+		assert.Equal(t, len(fresh.ranking), len(r.index[model.RecordTypeMovie].ranking))
+	}
+
+	// Records are ranked again when the weights change.
+	weights = repository.ProviderWeights{Default: 1}
+	got, err := r.GetTopRated(ctx, model.RecordTypeMovie, 10, 0, weights)
+	assert.NoError(t, err)
+	fresh := &topRatedIndex{byID: r.index[model.RecordTypeMovie].byID}
+	fresh.rank(weights)
+	assert.Equal(t, fresh.top(model.RecordTypeMovie, 10, 0), got)
+}
+
+func TestPutLastWriterWins(t *testing.T) {
+	ctx := context.Background()
+	r := New()
+	now := time.Now()
+	put := func(value model.RatingValue, updatedAt time.Time) {
+		assert.NoError(t, r.Put(ctx, "1", model.RecordTypeMovie, &model.Rating{UserID: "user", Value: value, UpdatedAt: updatedAt}))
+	}
+	put(3, now)
+	put(5, now.Add(time.Second))
+	// An older rating arriving late does not replace the newer one.
+	put(1, now.Add(-time.Second))
+
+	got, err := r.Get(ctx, "1", model.RecordTypeMovie)
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+	assert.Equal(t, model.RatingValue(5), got[0].Value)
+	top, err := r.GetTopRated(ctx, model.RecordTypeMovie, 10, 0, repository.ProviderWeights{Default: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []model.RatedRecord{{RecordID: "1", RecordType: model.RecordTypeMovie, Rating: 5, VoteCount: 1}}, top)
+}
+
+func TestDeleteTombstone(t *testing.T) {
+	ctx := context.Background()
+	r := New()
+	now := time.Now()
+	put := func(value model.RatingValue, updatedAt time.Time) {
+		assert.NoError(t, r.Put(ctx, "1", model.RecordTypeMovie, &model.Rating{UserID: "user", Value: value, UpdatedAt: updatedAt}))
+	}
+	put(3, now)
+	assert.NoError(t, r.Delete(ctx, "1", model.RecordTypeMovie, "user", "", now.Add(time.Second)))
+	// A rating older than the deletion arriving late is ignored.
+	put(4, now.Add(time.Millisecond))
+	_, err := r.Get(ctx, "1", model.RecordTypeMovie)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	// A deletion older than the stored rating does not remove it.
+	put(5, now.Add(2*time.Second))
+	assert.NoError(t, r.Delete(ctx, "1", model.RecordTypeMovie, "user", "", now))
+	got, err := r.Get(ctx, "1", model.RecordTypeMovie)
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+
+	var tombstones []model.Tombstone
+	assert.NoError(t, r.Tombstones(ctx, func(t *model.Tombstone) error {
+		tombstones = append(tombstones, *t)
+		return nil
+	}))
+	assert.Equal(t, []model.Tombstone{{RatingKey: model.RatingKey{RecordID: "1", RecordType: model.RecordTypeMovie, UserID: "user"}, DeletedAt: now.Add(time.Second)}}, tombstones)
+}
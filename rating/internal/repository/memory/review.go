@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/abhishek622/movieapp/rating/internal/repository"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+)
+
+// PutReview creates or replaces a review.
+func (r *Repository) PutReview(ctx context.Context, review *model.Review) error {
+	r.Lock()
+	defer r.Unlock()
+	v := *review
+	r.reviews[review.ID] = &v
+	return nil
+}
+
+// GetReview retrieves a review by id.
+func (r *Repository) GetReview(ctx context.Context, id model.ReviewID) (*model.Review, error) {
+	r.RLock()
+	defer r.RUnlock()
+	review, ok := r.reviews[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	v := *review
+	return &v, nil
+}
+
+// UpdateReview changes the fields of a review set in the update and returns
+// the updated review.
+func (r *Repository) UpdateReview(ctx context.Context, id model.ReviewID, update model.ReviewUpdate) (*model.Review, error) {
+	r.Lock()
+	defer r.Unlock()
+	review, ok := r.reviews[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	if update.Text != nil {
+		review.Text = *update.Text
+	}
+	if update.Status != nil {
+		review.Status = *update.Status
+	}
+	review.UpdatedAt = update.UpdatedAt
+	v := *review
+	return &v, nil
+}
+
+// DeleteReview removes a review together with its helpful votes.
+func (r *Repository) DeleteReview(ctx context.Context, id model.ReviewID) error {
+	r.Lock()
+	defer r.Unlock()
+	if _, ok := r.reviews[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.reviews, id)
+	delete(r.reviewVotes, id)
+	return nil
+}
+
+// ListReviews returns up to limit reviews of a record with a given status,
+// ordered from the newest to the oldest one and starting after the cursor if set.
+func (r *Repository) ListReviews(ctx context.Context, recordID model.RecordID, recordType model.RecordType, status model.ReviewStatus, after *model.ReviewCursor, limit int) ([]model.Review, error) {
+	r.RLock()
+	defer r.RUnlock()
+	var res []model.Review
+	for _, review := range r.reviews {
+		if review.RecordID != recordID || review.RecordType != recordType || review.Status != status {
+			continue
+		}
+		if after != nil && !after.Before(review) {
+			continue
+		}
+		res = append(res, *review)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return model.ReviewCursor{CreatedAt: res[i].CreatedAt, ID: res[i].ID}.Before(&res[j])
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
+// AddHelpfulVote records a helpful vote of a user for a review and returns
+// the updated number of helpful votes.
+func (r *Repository) AddHelpfulVote(ctx context.Context, id model.ReviewID, userID model.UserID) (int, error) {
+	r.Lock()
+	defer r.Unlock()
+	review, ok := r.reviews[id]
+	if !ok {
+		return 0, repository.ErrNotFound
+	}
+	if _, ok := r.reviewVotes[id]; !ok {
+		r.reviewVotes[id] = map[model.UserID]struct{}{}
+	}
+	if _, ok := r.reviewVotes[id][userID]; ok {
+		return 0, repository.ErrAlreadyExists
+	}
+	r.reviewVotes[id][userID] = struct{}{}
+	review.HelpfulVotes++
+	return review.HelpfulVotes, nil
+}
@@ -3,7 +3,14 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/abhishek622/movieapp/rating/internal/repository"
 	"github.com/abhishek622/movieapp/rating/pkg/model"
 	_ "github.com/go-sql-driver/mysql"
 )
@@ -15,7 +22,7 @@ type Repository struct {
 
 // New creates a new MySQL-based rating repository.
 func New() (*Repository, error) {
-	db, err := sql.Open("mysql", "root:root@/movieapp")
+	db, err := sql.Open("mysql", "root:root@/movieapp?parseTime=true")
 	if err != nil {
 		return nil, err
 	}
@@ -24,7 +31,7 @@ func New() (*Repository, error) {
 
 // Get retrieves all ratings for a given record.
 func (r *Repository) Get(ctx context.Context, recordID model.RecordID, recordType model.RecordType) ([]model.Rating, error) {
-	rows, err := r.db.QueryContext(ctx, "SELECT user_id, value FROM ratings WHERE record_id = ? AND record_type = ?", recordID, recordType)
+	rows, err := r.db.QueryContext(ctx, "SELECT user_id, value, updated_at, provider_id FROM ratings WHERE record_id = ? AND record_type = ?", recordID, recordType)
 	if err != nil {
 		return nil, err
 	}
@@ -33,20 +40,419 @@ func (r *Repository) Get(ctx context.Context, recordID model.RecordID, recordTyp
 	for rows.Next() {
 		var userID string
 		var value int32
-		if err := rows.Scan(&userID, &value); err != nil {
+		var updatedAt time.Time
+		var providerID string
+		if err := rows.Scan(&userID, &value, &updatedAt, &providerID); err != nil {
 			return nil, err
 		}
 		res = append(res, model.Rating{
-			UserID: model.UserID(userID),
-			Value:  model.RatingValue(value),
+			UserID:     model.UserID(userID),
+			Value:      model.RatingValue(value),
+			UpdatedAt:  updatedAt,
+			ProviderID: providerID,
 		})
 	}
 	return res, nil
 }
 
-// Put adds a rating for a given record.
+// GetByPrefix retrieves the ratings of all records of a given type whose id
+// starts with prefix. The record of each rating is set.
+func (r *Repository) GetByPrefix(ctx context.Context, recordType model.RecordType, prefix string) ([]model.Rating, error) {
+	pattern := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(prefix) + "%"
+	rows, err := r.db.QueryContext(ctx, "SELECT record_id, user_id, value, updated_at, provider_id FROM ratings WHERE record_type = ? AND record_id LIKE ?", recordType, pattern)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []model.Rating
+	for rows.Next() {
+		var rating model.Rating
+		var userID string
+		if err := rows.Scan(&rating.RecordID, &userID, &rating.Value, &rating.UpdatedAt, &rating.ProviderID); err != nil {
+			return nil, err
+		}
+		rating.RecordType = string(recordType)
+		rating.UserID = model.UserID(userID)
+		res = append(res, rating)
+	}
+	return res, rows.Err()
+}
+
+// GetBatch retrieves the ratings of several records of a given type with a
+// single query. The record of each rating is set.
+func (r *Repository) GetBatch(ctx context.Context, recordType model.RecordType, recordIDs []model.RecordID) ([]model.Rating, error) {
+	if len(recordIDs) == 0 {
+		return nil, nil
+	}
+	args := []any{recordType}
+	for _, id := range recordIDs {
+		args = append(args, id)
+	}
+	query := "SELECT record_id, user_id, value, updated_at, provider_id FROM ratings WHERE record_type = ? AND record_id IN (?" +
+		strings.Repeat(", ?", len(recordIDs)-1) + ")"
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []model.Rating
+	for rows.Next() {
+		var rating model.Rating
+		var userID string
+		if err := rows.Scan(&rating.RecordID, &userID, &rating.Value, &rating.UpdatedAt, &rating.ProviderID); err != nil {
+			return nil, err
+		}
+		rating.RecordType = string(recordType)
+		rating.UserID = model.UserID(userID)
+		res = append(res, rating)
+	}
+	return res, rows.Err()
+}
+
+// Put adds or replaces the rating of a user of a provider for a given record
+// and updates the aggregated rating of the record. The rating is ignored if
+// the stored rating of the user was updated after it, or if the rating of the
+// user was deleted after it.
 func (r *Repository) Put(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) error {
-	_, err := r.db.ExecContext(ctx, "INSERT INTO ratings (record_id, record_type, user_id, value) VALUES (?, ?, ?, ?)",
-		recordID, recordType, rating.UserID, rating.Value)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := put(ctx, tx, recordID, recordType, rating); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// PutBatch adds or replaces several ratings in a single transaction. The
+// record of each rating is set by its RecordID and RecordType fields.
+func (r *Repository) PutBatch(ctx context.Context, ratings []model.Rating) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for i := range ratings {
+		if err := put(ctx, tx, model.RecordID(ratings[i].RecordID), model.RecordType(ratings[i].RecordType), &ratings[i]); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func put(ctx context.Context, tx *sql.Tx, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) error {
+	var deletedAt time.Time
+	err := tx.QueryRowContext(ctx, "SELECT deleted_at FROM rating_tombstones WHERE record_id = ? AND record_type = ? AND user_id = ? AND provider_id = ? FOR UPDATE",
+		recordID, recordType, rating.UserID, rating.ProviderID).Scan(&deletedAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	} else if err == nil && deletedAt.After(rating.UpdatedAt) {
+		return nil
+	}
+	var oldValue int
+	var oldUpdatedAt time.Time
+	err = tx.QueryRowContext(ctx, "SELECT value, updated_at FROM ratings WHERE record_id = ? AND record_type = ? AND user_id = ? AND provider_id = ? FOR UPDATE",
+		recordID, recordType, rating.UserID, rating.ProviderID).Scan(&oldValue, &oldUpdatedAt)
+	exists := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if exists && oldUpdatedAt.After(rating.UpdatedAt) {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO ratings (record_id, record_type, user_id, value, updated_at, provider_id) VALUES (?, ?, ?, ?, ?, ?) "+
+		"ON DUPLICATE KEY UPDATE value = VALUES(value), updated_at = VALUES(updated_at)",
+		recordID, recordType, rating.UserID, rating.Value, rating.UpdatedAt, rating.ProviderID); err != nil {
+		return err
+	}
+	sum, count := int(rating.Value), 1
+	if exists {
+		sum, count = int(rating.Value)-oldValue, 0
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO rating_aggregates (record_id, record_type, provider_id, rating_sum, vote_count) VALUES (?, ?, ?, ?, ?) "+
+		"ON DUPLICATE KEY UPDATE rating_sum = rating_sum + VALUES(rating_sum), vote_count = vote_count + VALUES(vote_count)",
+		recordID, recordType, rating.ProviderID, sum, count); err != nil {
+		return err
+	}
+	return updateScore(ctx, tx, recordID, recordType)
+}
+
+// Delete removes the rating of a user of a provider for a given record and
+// updates the aggregated rating of the record. The rating is kept if it was
+// updated after deletedAt. Deleting a rating that does not exist is not an
+// error. The deletion is remembered so that older ratings received later are
+// ignored.
+func (r *Repository) Delete(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID, providerID string, deletedAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := del(ctx, tx, recordID, recordType, userID, providerID, deletedAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func del(ctx context.Context, tx *sql.Tx, recordID model.RecordID, recordType model.RecordType, userID model.UserID, providerID string, deletedAt time.Time) error {
+	var value int
+	var updatedAt time.Time
+	err := tx.QueryRowContext(ctx, "SELECT value, updated_at FROM ratings WHERE record_id = ? AND record_type = ? AND user_id = ? AND provider_id = ? FOR UPDATE",
+		recordID, recordType, userID, providerID).Scan(&value, &updatedAt)
+	exists := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if exists && updatedAt.After(deletedAt) {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO rating_tombstones (record_id, record_type, user_id, provider_id, deleted_at) VALUES (?, ?, ?, ?, ?) "+
+		"ON DUPLICATE KEY UPDATE deleted_at = GREATEST(deleted_at, VALUES(deleted_at))",
+		recordID, recordType, userID, providerID, deletedAt); err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM ratings WHERE record_id = ? AND record_type = ? AND user_id = ? AND provider_id = ?", recordID, recordType, userID, providerID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE rating_aggregates SET rating_sum = rating_sum - ?, vote_count = vote_count - 1 WHERE record_id = ? AND record_type = ? AND provider_id = ?",
+		value, recordID, recordType, providerID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM rating_aggregates WHERE record_id = ? AND record_type = ? AND provider_id = ? AND vote_count <= 0", recordID, recordType, providerID); err != nil {
+		return err
+	}
+	return updateScore(ctx, tx, recordID, recordType)
+}
+
+// updateScore computes the score of a record in the ranking of the top rated
+// records from its aggregated ratings, with the weights of the ranking. The
+// weights are read in share mode, so that the records are not ranked again
+// while the score is written. Nothing is written before the records are
+// ranked.
+func updateScore(ctx context.Context, tx *sql.Tx, recordID model.RecordID, recordType model.RecordType) error {
+	var encoded string
+	if err := tx.QueryRowContext(ctx, "SELECT weights FROM rating_score_weights WHERE id = 1 LOCK IN SHARE MODE").Scan(&encoded); err != nil {
+		return err
+	}
+	if encoded == "" {
+		return nil
+	}
+	var weights repository.ProviderWeights
+	if err := json.Unmarshal([]byte(encoded), &weights); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM rating_scores WHERE record_id = ? AND record_type = ?", recordID, recordType); err != nil {
+		return err
+	}
+	query, args := insertScores(weights, "WHERE record_id = ? AND record_type = ?")
+	_, err := tx.ExecContext(ctx, query, append(args, recordID, recordType)...)
 	return err
 }
+
+// insertScores returns the statement and arguments inserting the scores of
+// the records whose aggregated ratings match the given condition, which takes
+// its arguments after the returned ones.
+func insertScores(weights repository.ProviderWeights, cond string) (string, []any) {
+	weight := "?"
+	var args []any
+	if len(weights.Weights) > 0 {
+		weight = "CASE provider_id" + strings.Repeat(" WHEN ? THEN ?", len(weights.Weights)) + " ELSE ? END"
+		for _, id := range slices.Sorted(maps.Keys(weights.Weights)) {
+			args = append(args, id, weights.Weights[id])
+		}
+	}
+	args = append(args, weights.Default)
+	return "INSERT INTO rating_scores (record_id, record_type, weighted_sum, weight, vote_count, average) " +
+		"SELECT record_id, record_type, SUM(weight * rating_sum), SUM(weight * vote_count), SUM(vote_count), SUM(weight * rating_sum) / SUM(weight * vote_count) " +
+		"FROM (SELECT record_id, record_type, rating_sum, vote_count, " + weight + " AS weight FROM rating_aggregates " + cond + ") AS a " +
+		"WHERE weight > 0 GROUP BY record_id, record_type", args
+}
+
+// Rebuild builds new ratings with build and replaces the ratings, aggregates
+// and tombstones of the repository with them once build succeeds. They are
+// built in a single transaction, so that the current ratings are served until
+// it commits. Reviews are kept.
+func (r *Repository) Rebuild(ctx context.Context, build func(repository.Projection) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, table := range []string{"ratings", "rating_aggregates", "rating_scores", "rating_tombstones"} {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table); err != nil {
+			return err
+		}
+	}
+	if err := build(&projection{tx}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// projection writes the ratings of a rebuild within its transaction.
+type projection struct {
+	tx *sql.Tx
+}
+
+func (p *projection) PutBatch(ctx context.Context, ratings []model.Rating) error {
+	for i := range ratings {
+		if err := put(ctx, p.tx, model.RecordID(ratings[i].RecordID), model.RecordType(ratings[i].RecordType), &ratings[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *projection) Delete(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID, providerID string, deletedAt time.Time) error {
+	return del(ctx, p.tx, recordID, recordType, userID, providerID, deletedAt)
+}
+
+// GetTopRated returns up to limit records of the given type with the highest
+// average rating weighted by the weights of their providers, skipping records
+// with less than minVoteCount weighted ratings. The records are read from the
+// ranking maintained on writes, which is only computed again when the weights
+// change.
+func (r *Repository) GetTopRated(ctx context.Context, recordType model.RecordType, limit int, minVoteCount int, weights repository.ProviderWeights) ([]model.RatedRecord, error) {
+	if err := r.rank(ctx, weights); err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, "SELECT record_id, average, vote_count FROM rating_scores "+
+		"WHERE record_type = ? AND vote_count >= ? ORDER BY average DESC, vote_count DESC, record_id LIMIT ?", recordType, minVoteCount, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []model.RatedRecord
+	for rows.Next() {
+		var recordID string
+		var average float64
+		var voteCount int
+		if err := rows.Scan(&recordID, &average, &voteCount); err != nil {
+			return nil, err
+		}
+		res = append(res, model.RatedRecord{
+			RecordID:   model.RecordID(recordID),
+			RecordType: recordType,
+			Rating:     average,
+			VoteCount:  voteCount,
+		})
+	}
+	return res, rows.Err()
+}
+
+// rank computes the scores of all the records with the given weights, unless
+// they are the weights of the current ranking.
+func (r *Repository) rank(ctx context.Context, weights repository.ProviderWeights) error {
+	encoded, err := json.Marshal(weights)
+	if err != nil {
+		return err
+	}
+	var current string
+	if err := r.db.QueryRowContext(ctx, "SELECT weights FROM rating_score_weights WHERE id = 1").Scan(&current); err != nil {
+		return err
+	}
+	if current == string(encoded) {
+		return nil
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := tx.QueryRowContext(ctx, "SELECT weights FROM rating_score_weights WHERE id = 1 FOR UPDATE").Scan(&current); err != nil {
+		return err
+	}
+	if current == string(encoded) {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM rating_scores"); err != nil {
+		return err
+	}
+	query, args := insertScores(weights, "")
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE rating_score_weights SET weights = ? WHERE id = 1", string(encoded)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Export calls fn for every rating of the given type, or of all types if
+// recordType is empty, that was updated within [since, until]. Zero times
+// leave the range open. Ratings are ordered by their key and start after the
+// given key if set. They are read within a single read-only transaction so
+// that the export is a consistent snapshot.
+func (r *Repository) Export(ctx context.Context, recordType model.RecordType, since time.Time, until time.Time, after *model.RatingKey, fn func(*model.Rating) error) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var conds []string
+	var args []any
+	if recordType != "" {
+		conds = append(conds, "record_type = ?")
+		args = append(args, recordType)
+	}
+	if !since.IsZero() {
+		conds = append(conds, "updated_at >= ?")
+		args = append(args, since)
+	}
+	if !until.IsZero() {
+		conds = append(conds, "updated_at <= ?")
+		args = append(args, until)
+	}
+	if after != nil {
+		conds = append(conds, "(record_id, record_type, user_id, provider_id) > (?, ?, ?, ?)")
+		args = append(args, after.RecordID, after.RecordType, after.UserID, after.ProviderID)
+	}
+	query := "SELECT record_id, record_type, user_id, value, updated_at, provider_id FROM ratings"
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	query += " ORDER BY record_id, record_type, user_id, provider_id"
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var rating model.Rating
+		var userID string
+		if err := rows.Scan(&rating.RecordID, &rating.RecordType, &userID, &rating.Value, &rating.UpdatedAt, &rating.ProviderID); err != nil {
+			return err
+		}
+		rating.UserID = model.UserID(userID)
+		if err := fn(&rating); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Tombstones calls fn for every remembered deletion, ordered by rating key.
+func (r *Repository) Tombstones(ctx context.Context, fn func(*model.Tombstone) error) error {
+	rows, err := r.db.QueryContext(ctx, "SELECT record_id, record_type, user_id, provider_id, deleted_at FROM rating_tombstones ORDER BY record_id, record_type, user_id, provider_id")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t model.Tombstone
+		var recordID, recordType, userID string
+		if err := rows.Scan(&recordID, &recordType, &userID, &t.ProviderID, &t.DeletedAt); err != nil {
+			return err
+		}
+		t.RecordID, t.RecordType, t.UserID = model.RecordID(recordID), model.RecordType(recordType), model.UserID(userID)
+		if err := fn(&t); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,170 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/abhishek622/movieapp/rating/internal/repository"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	mysqldriver "github.com/go-sql-driver/mysql"
+)
+
+// errDuplicateEntry is the MySQL error number of a primary key violation.
+const errDuplicateEntry = 1062
+
+// PutReview creates or replaces a review. The helpful votes of an existing
+// review are kept.
+func (r *Repository) PutReview(ctx context.Context, review *model.Review) error {
+	_, err := r.db.ExecContext(ctx, "INSERT INTO reviews (id, record_id, record_type, user_id, text, status, helpful_votes, created_at, updated_at) "+
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) "+
+		"ON DUPLICATE KEY UPDATE text = VALUES(text), status = VALUES(status), updated_at = VALUES(updated_at)",
+		review.ID, review.RecordID, review.RecordType, review.UserID, review.Text, review.Status, review.HelpfulVotes, review.CreatedAt, review.UpdatedAt)
+	return err
+}
+
+// GetReview retrieves a review by id.
+func (r *Repository) GetReview(ctx context.Context, id model.ReviewID) (*model.Review, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT id, record_id, record_type, user_id, text, status, helpful_votes, created_at, updated_at FROM reviews WHERE id = ?", id)
+	review, err := scanReview(row)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// UpdateReview changes the columns of a review set in the update and returns
+// the updated review.
+func (r *Repository) UpdateReview(ctx context.Context, id model.ReviewID, update model.ReviewUpdate) (*model.Review, error) {
+	query := "UPDATE reviews SET updated_at = ?"
+	args := []any{update.UpdatedAt}
+	if update.Text != nil {
+		query += ", text = ?"
+		args = append(args, *update.Text)
+	}
+	if update.Status != nil {
+		query += ", status = ?"
+		args = append(args, *update.Status)
+	}
+	query += " WHERE id = ?"
+	args = append(args, id)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	// Rows whose values do not change are not counted as affected, so the
+	// review is read back to find out whether it exists.
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return nil, err
+	}
+	row := tx.QueryRowContext(ctx, "SELECT id, record_id, record_type, user_id, text, status, helpful_votes, created_at, updated_at FROM reviews WHERE id = ?", id)
+	review, err := scanReview(row)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return review, tx.Commit()
+}
+
+// DeleteReview removes a review together with its helpful votes.
+func (r *Repository) DeleteReview(ctx context.Context, id model.ReviewID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	res, err := tx.ExecContext(ctx, "DELETE FROM reviews WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return repository.ErrNotFound
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM review_votes WHERE review_id = ?", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListReviews returns up to limit reviews of a record with a given status,
+// ordered from the newest to the oldest one and starting after the cursor if set.
+func (r *Repository) ListReviews(ctx context.Context, recordID model.RecordID, recordType model.RecordType, status model.ReviewStatus, after *model.ReviewCursor, limit int) ([]model.Review, error) {
+	query := "SELECT id, record_id, record_type, user_id, text, status, helpful_votes, created_at, updated_at FROM reviews " +
+		"WHERE record_id = ? AND record_type = ? AND status = ?"
+	args := []any{recordID, recordType, status}
+	if after != nil {
+		query += " AND (created_at < ? OR (created_at = ? AND id < ?))"
+		args = append(args, after.CreatedAt, after.CreatedAt, after.ID)
+	}
+	query += " ORDER BY created_at DESC, id DESC LIMIT ?"
+	args = append(args, limit)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []model.Review
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *review)
+	}
+	return res, rows.Err()
+}
+
+// AddHelpfulVote records a helpful vote of a user for a review and returns
+// the updated number of helpful votes.
+func (r *Repository) AddHelpfulVote(ctx context.Context, id model.ReviewID, userID model.UserID) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	res, err := tx.ExecContext(ctx, "UPDATE reviews SET helpful_votes = helpful_votes + 1 WHERE id = ?", id)
+	if err != nil {
+		return 0, err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return 0, err
+	} else if n == 0 {
+		return 0, repository.ErrNotFound
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO review_votes (review_id, user_id) VALUES (?, ?)", id, userID); err != nil {
+		var mysqlErr *mysqldriver.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == errDuplicateEntry {
+			return 0, repository.ErrAlreadyExists
+		}
+		return 0, err
+	}
+	var votes int
+	if err := tx.QueryRowContext(ctx, "SELECT helpful_votes FROM reviews WHERE id = ?", id).Scan(&votes); err != nil {
+		return 0, err
+	}
+	return votes, tx.Commit()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanReview(s scanner) (*model.Review, error) {
+	var review model.Review
+	var id, recordID, recordType, userID, status string
+	if err := s.Scan(&id, &recordID, &recordType, &userID, &review.Text, &status, &review.HelpfulVotes, &review.CreatedAt, &review.UpdatedAt); err != nil {
+		return nil, err
+	}
+	review.ID = model.ReviewID(id)
+	review.RecordID = model.RecordID(recordID)
+	review.RecordType = model.RecordType(recordType)
+	review.UserID = model.UserID(userID)
+	review.Status = model.ReviewStatus(status)
+	return &review, nil
+}
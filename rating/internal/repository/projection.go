@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+)
+
+// Projection receives the ratings of a repository while it is rebuilt.
+type Projection interface {
+	PutBatch(ctx context.Context, ratings []model.Rating) error
+	Delete(ctx context.Context, recordID model.RecordID, recordType model.RecordType, userID model.UserID, providerID string, deletedAt time.Time) error
+}
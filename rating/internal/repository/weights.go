@@ -0,0 +1,17 @@
+package repository
+
+// ProviderWeights holds the weights of the ratings of each provider in
+// aggregated ratings. Providers without a weight weigh Default. Ratings
+// weighing zero are not aggregated.
+type ProviderWeights struct {
+	Default float64
+	Weights map[string]float64
+}
+
+// Get returns the weight of the ratings of a provider.
+func (w ProviderWeights) Get(providerID string) float64 {
+	if v, ok := w.Weights[providerID]; ok {
+		return v
+	}
+	return w.Default
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"github.com/abhishek622/movieapp/gen"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// RatedRecordToProto converts a RatedRecord struct into a generated proto counterpart.
+func RatedRecordToProto(r *RatedRecord) *gen.RatedRecord {
+	return &gen.RatedRecord{
+		RecordId:    string(r.RecordID),
+		RecordType:  string(r.RecordType),
+		RatingValue: r.Rating,
+		VoteCount:   int32(r.VoteCount),
+	}
+}
+
+// RatedRecordFromProto converts a generated proto counterpart into a RatedRecord struct.
+func RatedRecordFromProto(r *gen.RatedRecord) *RatedRecord {
+	return &RatedRecord{
+		RecordID:   RecordID(r.RecordId),
+		RecordType: RecordType(r.RecordType),
+		Rating:     r.RatingValue,
+		VoteCount:  int(r.VoteCount),
+	}
+}
+
+// ProviderRatingToProto converts a ProviderRating struct into a generated proto counterpart.
+func ProviderRatingToProto(r *ProviderRating) *gen.ProviderRating {
+	return &gen.ProviderRating{
+		ProviderId:  r.ProviderID,
+		RatingValue: r.Rating,
+		VoteCount:   int32(r.VoteCount),
+		Weight:      r.Weight,
+		Enabled:     r.Enabled,
+	}
+}
+
+// ReviewToProto converts a Review struct into a generated proto counterpart.
+func ReviewToProto(r *Review) *gen.Review {
+	return &gen.Review{
+		Id:           string(r.ID),
+		RecordId:     string(r.RecordID),
+		RecordType:   string(r.RecordType),
+		UserId:       string(r.UserID),
+		Text:         r.Text,
+		Status:       string(r.Status),
+		HelpfulVotes: int32(r.HelpfulVotes),
+		CreatedAt:    r.CreatedAt.Unix(),
+		UpdatedAt:    r.UpdatedAt.Unix(),
+	}
+}
+
+// RatingToExportProto converts a Rating struct into an exported rating proto.
+func RatingToExportProto(r *Rating) *gen.ExportedRating {
+	return &gen.ExportedRating{
+		RecordId:    r.RecordID,
+		RecordType:  r.RecordType,
+		UserId:      string(r.UserID),
+		RatingValue: int32(r.Value),
+		UpdatedAt:   timestamppb.New(r.UpdatedAt),
+		ProviderId:  r.ProviderID,
+	}
+}
+
+// RatingFromExportProto converts an exported rating proto into a Rating struct.
+func RatingFromExportProto(r *gen.ExportedRating) *Rating {
+	return &Rating{
+		RecordID:   r.RecordId,
+		RecordType: r.RecordType,
+		UserID:     UserID(r.UserId),
+		Value:      RatingValue(r.RatingValue),
+		UpdatedAt:  r.UpdatedAt.AsTime(),
+		ProviderID: r.ProviderId,
+	}
+}
+
+// RatingEventToProto converts a RatingEvent struct into a generated proto counterpart.
+func RatingEventToProto(e *RatingEvent) *gen.RatingEvent {
+	p := &gen.RatingEvent{
+		EventId:    e.EventID,
+		ProviderId: e.ProviderID,
+		EventType:  string(e.EventType),
+		RecordId:   e.RecordID,
+		RecordType: e.RecordType,
+		UserId:     string(e.UserID),
+		Value:      int32(e.Value),
+	}
+	if !e.Timestamp.IsZero() {
+		p.Timestamp = timestamppb.New(e.Timestamp)
+	}
+	return p
+}
+
+// RatingEventFromProto converts a generated proto counterpart into a RatingEvent struct.
+func RatingEventFromProto(p *gen.RatingEvent) *RatingEvent {
+	e := &RatingEvent{
+		Rating: Rating{
+			RecordID:   p.RecordId,
+			RecordType: p.RecordType,
+			UserID:     UserID(p.UserId),
+			Value:      RatingValue(p.Value),
+			ProviderID: p.ProviderId,
+		},
+		EventID:   p.EventId,
+		EventType: RatingEventType(p.EventType),
+	}
+	if p.Timestamp != nil {
+		e.Timestamp = p.Timestamp.AsTime()
+	}
+	return e
+}
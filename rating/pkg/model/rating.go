@@ -1,10 +1,15 @@
 package model
 
+import "time"
+
 type RecordID string
 type RecordType string
 
 const (
-	RecordTypeMovie = RecordType("movie")
+	RecordTypeMovie   = RecordType("movie")
+	RecordTypeSeries  = RecordType("series")
+	RecordTypeEpisode = RecordType("episode")
+	RecordTypePerson  = RecordType("person")
 )
 
 type UserID string
@@ -15,12 +20,50 @@ type Rating struct {
 	RecordType string      `json:"recordType"`
 	UserID     UserID      `json:"userId"`
 	Value      RatingValue `json:"value"`
+	UpdatedAt  time.Time   `json:"updatedAt,omitzero"`
+	// ProviderID identifies the source of the rating.
+	ProviderID string `json:"providerId,omitempty"`
+}
+
+// RatingKey uniquely identifies the rating of a user of a provider for a
+// record. Users of different providers are different users even if their ids
+// are the same.
+type RatingKey struct {
+	RecordID   RecordID   `json:"recordId"`
+	RecordType RecordType `json:"recordType"`
+	UserID     UserID     `json:"userId"`
+	ProviderID string     `json:"providerId"`
+}
+
+// Less reports whether k is ordered before o.
+func (k RatingKey) Less(o RatingKey) bool {
+	if k.RecordID != o.RecordID {
+		return k.RecordID < o.RecordID
+	}
+	if k.RecordType != o.RecordType {
+		return k.RecordType < o.RecordType
+	}
+	if k.UserID != o.UserID {
+		return k.UserID < o.UserID
+	}
+	return k.ProviderID < o.ProviderID
+}
+
+// Tombstone records the deletion of the rating of a user for a record, so
+// that older ratings of the user received after the deletion are ignored.
+type Tombstone struct {
+	RatingKey
+	DeletedAt time.Time `json:"deletedAt"`
 }
 
 type RatingEvent struct {
 	Rating
-	ProviderID string          `json:"providerId"`
-	EventType  RatingEventType `json:"eventType"`
+	// EventID uniquely identifies the event among the events of its provider.
+	EventID   string          `json:"eventId,omitempty"`
+	EventType RatingEventType `json:"eventType"`
+	// Timestamp is the time the event was produced. It decides which of two
+	// ratings of the same user for the same record is the latest one.
+	Timestamp time.Time `json:"timestamp,omitzero"`
 }
 
 type RatingEventType string
@@ -29,3 +72,29 @@ const (
 	RatingEventTypePut    = RatingEventType("put")
 	RatingEventTypeDelete = RatingEventType("delete")
 )
+
+// RatedRecord holds the aggregated rating of a single record.
+type RatedRecord struct {
+	RecordID   RecordID   `json:"recordId" xml:"recordId"`
+	RecordType RecordType `json:"recordType" xml:"recordType"`
+	Rating     float64    `json:"rating" xml:"rating"`
+	VoteCount  int        `json:"voteCount" xml:"voteCount"`
+}
+
+// AggregatedRating holds the weighted aggregated rating of a record.
+type AggregatedRating struct {
+	Rating float64 `json:"rating" xml:"rating"`
+	// VoteCount is the number of aggregated ratings.
+	VoteCount int              `json:"voteCount" xml:"voteCount"`
+	Providers []ProviderRating `json:"providers,omitempty" xml:"providers>provider,omitempty"`
+}
+
+// ProviderRating holds the aggregated rating of a record from a single provider.
+type ProviderRating struct {
+	ProviderID string  `json:"providerId" xml:"providerId"`
+	Rating     float64 `json:"rating" xml:"rating"`
+	VoteCount  int     `json:"voteCount" xml:"voteCount"`
+	Weight     float64 `json:"weight" xml:"weight"`
+	// Enabled tells whether the ratings of the provider count in the aggregated rating.
+	Enabled bool `json:"enabled" xml:"enabled"`
+}
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+type ReviewID string
+
+// ReviewStatus defines the moderation state of a review.
+type ReviewStatus string
+
+const (
+	ReviewStatusPending  = ReviewStatus("pending")
+	ReviewStatusApproved = ReviewStatus("approved")
+	ReviewStatusRejected = ReviewStatus("rejected")
+)
+
+// Review is a written review of a record.
+type Review struct {
+	ID           ReviewID     `json:"id"`
+	RecordID     RecordID     `json:"recordId"`
+	RecordType   RecordType   `json:"recordType"`
+	UserID       UserID       `json:"userId"`
+	Text         string       `json:"text"`
+	Status       ReviewStatus `json:"status"`
+	HelpfulVotes int          `json:"helpfulVotes"`
+	CreatedAt    time.Time    `json:"createdAt"`
+	UpdatedAt    time.Time    `json:"updatedAt"`
+}
+
+// ReviewCursor identifies a position in a list of reviews ordered from the newest to the oldest one.
+type ReviewCursor struct {
+	CreatedAt time.Time
+	ID        ReviewID
+}
+
+// Before reports whether r is listed after the cursor position.
+func (c ReviewCursor) Before(r *Review) bool {
+	if !r.CreatedAt.Equal(c.CreatedAt) {
+		return r.CreatedAt.Before(c.CreatedAt)
+	}
+	return r.ID < c.ID
+}
+
+// ReviewUpdate lists the fields of a review changed by an update. Nil fields
+// are left unchanged, so that updates do not overwrite concurrent changes of
+// other fields such as the helpful votes.
+type ReviewUpdate struct {
+	Text      *string
+	Status    *ReviewStatus
+	UpdatedAt time.Time
+}
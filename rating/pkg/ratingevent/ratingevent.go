@@ -0,0 +1,95 @@
+package ratingevent
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/abhishek622/movieapp/gen"
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/abhishek622/movieapp/rating/pkg/recordtype"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// HeaderContentType is the Kafka message header holding the content type of a rating event.
+	HeaderContentType = "content-type"
+	// HeaderSchemaVersion is the Kafka message header holding the schema
+	// registry version the rating event was written with.
+	HeaderSchemaVersion = "schema-version"
+
+	// ContentTypeJSON is the content type of JSON-encoded rating events.
+	ContentTypeJSON = "application/json"
+	// ContentTypeProtobuf is the content type of protobuf-encoded rating events.
+	ContentTypeProtobuf = "application/x-protobuf"
+
+	// Subject is the schema registry subject of rating events.
+	Subject = "ratings-value"
+)
+
+// ErrUnsupportedContentType is returned when a rating event has an unknown content type.
+var ErrUnsupportedContentType = errors.New("unsupported content type")
+
+// Validate checks that a rating event identifies a rating of a registered
+// record type and has a known event type.
+func Validate(e *model.RatingEvent) error {
+	if e.RecordID == "" || e.RecordType == "" || e.UserID == "" {
+		return errors.New("record id, record type and user id are required")
+	}
+	if err := recordtype.Validate(model.RecordType(e.RecordType), model.RecordID(e.RecordID)); err != nil {
+		return err
+	}
+	switch e.EventType {
+	case "", model.RatingEventTypePut, model.RatingEventTypeDelete:
+		return nil
+	default:
+		return fmt.Errorf("unsupported event type %q", e.EventType)
+	}
+}
+
+// Key returns the Kafka message key of a rating event. Events of the same
+// record have the same key so that they are written to the same partition
+// and keep their order.
+func Key(e *model.RatingEvent) []byte {
+	return []byte(e.RecordType + "/" + e.RecordID)
+}
+
+// Descriptor returns the protobuf descriptor of rating events.
+func Descriptor() protoreflect.MessageDescriptor {
+	return (&gen.RatingEvent{}).ProtoReflect().Descriptor()
+}
+
+// Encode encodes a rating event with the given content type. JSON events
+// use the JSON mapping of the protobuf schema.
+func Encode(e *model.RatingEvent, contentType string) ([]byte, error) {
+	p := model.RatingEventToProto(e)
+	switch contentType {
+	case ContentTypeJSON:
+		return protojson.Marshal(p)
+	case ContentTypeProtobuf:
+		return proto.Marshal(p)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedContentType, contentType)
+	}
+}
+
+// Decode decodes a rating event with the given content type. Events without
+// a content type are JSON. Unknown fields are ignored so that events written
+// with a newer compatible schema can be read.
+func Decode(data []byte, contentType string) (*model.RatingEvent, error) {
+	p := &gen.RatingEvent{}
+	switch contentType {
+	case "", ContentTypeJSON:
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, p); err != nil {
+			return nil, err
+		}
+	case ContentTypeProtobuf:
+		if err := proto.Unmarshal(data, p); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedContentType, contentType)
+	}
+	return model.RatingEventFromProto(p), nil
+}
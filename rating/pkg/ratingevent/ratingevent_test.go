@@ -0,0 +1,42 @@
+package ratingevent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	event := &model.RatingEvent{
+		Rating:    model.Rating{RecordID: "1", RecordType: "movie", UserID: "105", Value: 5, ProviderID: "test-provider"},
+		EventID:   "e1",
+		EventType: model.RatingEventTypePut,
+		Timestamp: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+	for _, contentType := range []string{ContentTypeJSON, ContentTypeProtobuf} {
+		t.Run(contentType, func(t *testing.T) {
+			data, err := Encode(event, contentType)
+			require.NoError(t, err)
+			got, err := Decode(data, contentType)
+			require.NoError(t, err)
+			assert.Equal(t, event, got)
+		})
+	}
+}
+
+func TestDecodeLegacyJSON(t *testing.T) {
+	// Events written before the protobuf schema have no content type and may contain unknown fields.
+	data := []byte(`{"userId":"105","recordId":"1","recordType":"movie","value":4,"providerId":"test-provider","eventType":"put","updatedAt":"2024-05-01T10:00:00Z"}`)
+	got, err := Decode(data, "")
+	require.NoError(t, err)
+	assert.Equal(t, &model.RatingEvent{
+		Rating:    model.Rating{RecordID: "1", RecordType: "movie", UserID: "105", Value: 4, ProviderID: "test-provider"},
+		EventType: model.RatingEventTypePut,
+	}, got)
+
+	_, err = Decode(data, "text/csv")
+	assert.ErrorIs(t, err, ErrUnsupportedContentType)
+}
@@ -0,0 +1,106 @@
+// Package recordtype defines the types of records that can be rated and
+// validates record ids by type.
+package recordtype
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+)
+
+// Separator separates the id of the parent record from the rest of the id of
+// a child record, as in "<series id>/<episode>".
+const Separator = "/"
+
+var (
+	// ErrUnknownType is returned for record types that are not registered.
+	ErrUnknownType = errors.New("unknown record type")
+	// ErrInvalidID is returned for record ids that are not valid for their type.
+	ErrInvalidID = errors.New("invalid record id")
+)
+
+// Type describes a type of rated records.
+type Type struct {
+	Name model.RecordType `json:"name"`
+	// Parent is the type of the records that records of this type belong to,
+	// such as the series of an episode. The id of a record with a parent is
+	// the id of the parent record, Separator and an id within the parent.
+	Parent model.RecordType `json:"parent,omitempty"`
+}
+
+// types holds the registered record types.
+var types = map[model.RecordType]Type{
+	model.RecordTypeMovie:   {Name: model.RecordTypeMovie},
+	model.RecordTypeSeries:  {Name: model.RecordTypeSeries},
+	model.RecordTypeEpisode: {Name: model.RecordTypeEpisode, Parent: model.RecordTypeSeries},
+	model.RecordTypePerson:  {Name: model.RecordTypePerson},
+}
+
+// Get returns a registered record type or ErrUnknownType.
+func Get(name model.RecordType) (Type, error) {
+	t, ok := types[name]
+	if !ok {
+		return Type{}, fmt.Errorf("%w %q", ErrUnknownType, name)
+	}
+	return t, nil
+}
+
+// List returns the registered record types ordered by name.
+func List() []Type {
+	return slices.SortedFunc(maps.Values(types), func(a, b Type) int { return strings.Compare(string(a.Name), string(b.Name)) })
+}
+
+// Children returns the record types whose parent is the given type, ordered by name.
+func Children(name model.RecordType) []Type {
+	var res []Type
+	for _, t := range List() {
+		if t.Parent == name {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+// Validate checks that a record type is registered and that the record id is
+// valid for it. Ids may not be empty. Ids of records of types with child
+// types may not contain Separator, so that the ids of their children tell
+// which record they belong to. Records of other types, such as movies, may
+// have any id.
+func Validate(name model.RecordType, id model.RecordID) error {
+	t, err := Get(name)
+	if err != nil {
+		return err
+	}
+	if t.Parent == "" {
+		if id == "" || len(Children(name)) > 0 && strings.Contains(string(id), Separator) {
+			return fmt.Errorf("%w %q for type %s", ErrInvalidID, id, name)
+		}
+		return nil
+	}
+	parentID, ok := ParentID(id)
+	if !ok {
+		return fmt.Errorf("%w %q for type %s: want <%s id>%s<id>", ErrInvalidID, id, name, t.Parent, Separator)
+	}
+	if err := Validate(t.Parent, parentID); err != nil {
+		return fmt.Errorf("%w %q for type %s: %v", ErrInvalidID, id, name, err)
+	}
+	return nil
+}
+
+// ParentID returns the id of the parent record of a child record id.
+func ParentID(id model.RecordID) (model.RecordID, bool) {
+	i := strings.LastIndex(string(id), Separator)
+	if i <= 0 || i == len(id)-1 {
+		return "", false
+	}
+	return id[:i], true
+}
+
+// ChildPrefix returns the prefix of the ids of the child records of a record.
+func ChildPrefix(id model.RecordID) string {
+	return string(id) + Separator
+}
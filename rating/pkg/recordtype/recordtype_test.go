@@ -0,0 +1,54 @@
+package recordtype
+
+import (
+	"testing"
+
+	"github.com/abhishek622/movieapp/rating/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		recordType model.RecordType
+		id         model.RecordID
+		wantErr    error
+	}{
+		{model.RecordTypeMovie, "1", nil},
+		{model.RecordTypeMovie, "tt/0111161", nil},
+		{model.RecordTypeMovie, "", ErrInvalidID},
+		{model.RecordTypePerson, "nm/1", nil},
+		{model.RecordTypeSeries, "s1", nil},
+		{model.RecordTypeSeries, "s1/s2", ErrInvalidID},
+		{model.RecordTypeEpisode, "s1/e1", nil},
+		{model.RecordTypeEpisode, "e1", ErrInvalidID},
+		{model.RecordTypeEpisode, "s1/", ErrInvalidID},
+		{model.RecordTypeEpisode, "/e1", ErrInvalidID},
+		{model.RecordTypeEpisode, "s1/s2/e1", ErrInvalidID},
+		{"book", "1", ErrUnknownType},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.recordType, tt.id)
+		if tt.wantErr == nil {
+			assert.NoError(t, err, "%s %s", tt.recordType, tt.id)
+		} else {
+			assert.ErrorIs(t, err, tt.wantErr, "%s %s", tt.recordType, tt.id)
+		}
+	}
+}
+
+func TestChildren(t *testing.T) {
+	assert.Equal(t, []Type{{Name: model.RecordTypeEpisode, Parent: model.RecordTypeSeries}}, Children(model.RecordTypeSeries))
+	assert.Empty(t, Children(model.RecordTypeMovie))
+	assert.Empty(t, Children(model.RecordTypeEpisode))
+}
+
+func TestParentID(t *testing.T) {
+	id, ok := ParentID("s1/e1")
+	assert.True(t, ok)
+	assert.Equal(t, model.RecordID("s1"), id)
+	for _, id := range []model.RecordID{"s1", "s1/", "/e1"} {
+		_, ok := ParentID(id)
+		assert.False(t, ok, id)
+	}
+	assert.Equal(t, "s1/", ChildPrefix("s1"))
+}
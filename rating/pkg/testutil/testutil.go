@@ -3,12 +3,18 @@ package testutil
 import (
 	"github.com/abhishek622/movieapp/gen"
 	"github.com/abhishek622/movieapp/rating/internal/controller/rating"
+	"github.com/abhishek622/movieapp/rating/internal/controller/review"
+	authgateway "github.com/abhishek622/movieapp/rating/internal/gateway/auth/grpc"
 	grpchandler "github.com/abhishek622/movieapp/rating/internal/handler/grpc"
 	"github.com/abhishek622/movieapp/rating/internal/repository/memory"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
-func NewTestRatingGRPCServer() gen.RatingServiceServer {
+// NewTestRatingGRPCServer creates a new rating gRPC server to be used in
+// tests. Reviews are written with auth tokens validated by the auth service
+// at authAddr.
+func NewTestRatingGRPCServer(authAddr string) gen.RatingServiceServer {
 	r := memory.New()
-	ctrl := rating.New(r, nil)
-	return grpchandler.New(ctrl)
+	ctrl := rating.New(r, nil, nil)
+	return grpchandler.New(ctrl, review.New(r, authgateway.New(authAddr, insecure.NewCredentials()), nil))
 }
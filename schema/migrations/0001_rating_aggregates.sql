@@ -0,0 +1,15 @@
+-- Adds the aggregated ratings of the top rated leaderboard to databases
+-- created before it, and backfills them from the existing ratings.
+CREATE TABLE IF NOT EXISTS rating_aggregates (
+    record_id VARCHAR(255),
+    record_type VARCHAR(255),
+    rating_sum BIGINT NOT NULL DEFAULT 0,
+    vote_count INT NOT NULL DEFAULT 0,
+    average DOUBLE AS (rating_sum / vote_count) STORED,
+    PRIMARY KEY (record_id, record_type),
+    INDEX top_rated (record_type, average DESC, vote_count DESC)
+);
+
+INSERT INTO rating_aggregates (record_id, record_type, rating_sum, vote_count)
+SELECT record_id, record_type, SUM(value), COUNT(*) FROM ratings GROUP BY record_id, record_type
+ON DUPLICATE KEY UPDATE rating_sum = VALUES(rating_sum), vote_count = VALUES(vote_count);
@@ -0,0 +1,6 @@
+-- Adds the update time of ratings, used by rating exports and to resolve
+-- concurrent updates, to databases created before it. Existing ratings get
+-- the oldest time so that any later update of them wins.
+ALTER TABLE ratings ADD COLUMN updated_at DATETIME(6);
+
+UPDATE ratings SET updated_at = '1970-01-01 00:00:00' WHERE updated_at IS NULL;
@@ -0,0 +1,10 @@
+-- Adds the deletion times of ratings to databases created before them, so
+-- that older ratings received after a deletion are ignored. Ratings deleted
+-- before the migration have no tombstone.
+CREATE TABLE IF NOT EXISTS rating_tombstones (
+    record_id VARCHAR(255),
+    record_type VARCHAR(255),
+    user_id VARCHAR(255),
+    deleted_at DATETIME(6) NOT NULL,
+    PRIMARY KEY (record_id, record_type, user_id)
+);
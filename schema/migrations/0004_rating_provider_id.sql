@@ -0,0 +1,4 @@
+-- Adds the provider of ratings to databases created before it. Existing
+-- ratings get an empty provider, which weighs like the providers that are not
+-- configured.
+ALTER TABLE ratings ADD COLUMN provider_id VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,27 @@
+-- Adds the provider to the keys of ratings and tombstones, so that a rating
+-- of a user of one provider does not replace the rating of the user with the
+-- same id of another provider. Tombstones recorded before it are attributed
+-- to the ratings written through the rating API. Aggregated ratings are kept
+-- per provider so that top rated records are ranked with the provider
+-- weights, and are rebuilt from the existing ratings.
+ALTER TABLE ratings DROP PRIMARY KEY, ADD PRIMARY KEY (record_id, record_type, user_id, provider_id);
+
+ALTER TABLE rating_tombstones ADD COLUMN provider_id VARCHAR(255) NOT NULL DEFAULT 'rating' AFTER user_id,
+    DROP PRIMARY KEY, ADD PRIMARY KEY (record_id, record_type, user_id, provider_id);
+
+ALTER TABLE rating_tombstones ALTER COLUMN provider_id SET DEFAULT '';
+
+DROP TABLE IF EXISTS rating_aggregates;
+
+CREATE TABLE rating_aggregates (
+    record_id VARCHAR(255),
+    record_type VARCHAR(255),
+    provider_id VARCHAR(255) NOT NULL DEFAULT '',
+    rating_sum BIGINT NOT NULL DEFAULT 0,
+    vote_count INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (record_id, record_type, provider_id),
+    INDEX top_rated (record_type, record_id)
+);
+
+INSERT INTO rating_aggregates (record_id, record_type, provider_id, rating_sum, vote_count)
+SELECT record_id, record_type, provider_id, SUM(value), COUNT(*) FROM ratings GROUP BY record_id, record_type, provider_id;
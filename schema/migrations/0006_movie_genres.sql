@@ -0,0 +1,6 @@
+-- Adds the genres of movies to databases created before them, and the indexes
+-- movies are listed by. Existing movies have no genres.
+ALTER TABLE movies
+    ADD COLUMN genres VARCHAR(1024) NOT NULL DEFAULT '',
+    ADD INDEX movies_by_title (title, id),
+    ADD INDEX movies_by_director (director, title, id);
@@ -0,0 +1,22 @@
+-- Adds the ranking of the top rated leaderboard. The aggregated rating of
+-- every record, weighted by the provider weights in rating_score_weights, is
+-- maintained on rating writes, so that the top rated records are read from
+-- the top_rated index. The scores are computed by the first leaderboard query
+-- of the rating service, which sets the weights.
+CREATE TABLE IF NOT EXISTS rating_scores (
+    record_id VARCHAR(255),
+    record_type VARCHAR(255),
+    weighted_sum DOUBLE NOT NULL,
+    weight DOUBLE NOT NULL,
+    vote_count INT NOT NULL,
+    average DOUBLE NOT NULL,
+    PRIMARY KEY (record_id, record_type),
+    INDEX top_rated (record_type, average DESC, vote_count DESC, record_id)
+);
+
+CREATE TABLE IF NOT EXISTS rating_score_weights (
+    id TINYINT PRIMARY KEY,
+    weights TEXT NOT NULL
+);
+
+INSERT IGNORE INTO rating_score_weights (id, weights) VALUES (1, '');
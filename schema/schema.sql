@@ -2,7 +2,10 @@ CREATE TABLE IF NOT EXISTS movies (
     id VARCHAR(255) primary KEY,
     title VARCHAR(255),
     director VARCHAR(255),
-    description TEXT
+    description TEXT,
+    genres VARCHAR(1024) NOT NULL DEFAULT '',
+    INDEX movies_by_title (title, id),
+    INDEX movies_by_director (director, title, id)
 );
 
 CREATE TABLE IF NOT EXISTS ratings (
@@ -10,5 +13,63 @@ CREATE TABLE IF NOT EXISTS ratings (
     record_type VARCHAR(255),
     user_id VARCHAR(255),
     value INT,
-    PRIMARY KEY (record_id, record_type, user_id)
+    updated_at DATETIME(6),
+    provider_id VARCHAR(255) NOT NULL DEFAULT '',
+    PRIMARY KEY (record_id, record_type, user_id, provider_id)
+);
+
+CREATE TABLE IF NOT EXISTS rating_aggregates (
+    record_id VARCHAR(255),
+    record_type VARCHAR(255),
+    provider_id VARCHAR(255) NOT NULL DEFAULT '',
+    rating_sum BIGINT NOT NULL DEFAULT 0,
+    vote_count INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (record_id, record_type, provider_id),
+    INDEX top_rated (record_type, record_id)
+);
+
+CREATE TABLE IF NOT EXISTS rating_scores (
+    record_id VARCHAR(255),
+    record_type VARCHAR(255),
+    weighted_sum DOUBLE NOT NULL,
+    weight DOUBLE NOT NULL,
+    vote_count INT NOT NULL,
+    average DOUBLE NOT NULL,
+    PRIMARY KEY (record_id, record_type),
+    INDEX top_rated (record_type, average DESC, vote_count DESC, record_id)
+);
+
+CREATE TABLE IF NOT EXISTS rating_score_weights (
+    id TINYINT PRIMARY KEY,
+    weights TEXT NOT NULL
+);
+
+INSERT IGNORE INTO rating_score_weights (id, weights) VALUES (1, '');
+
+CREATE TABLE IF NOT EXISTS rating_tombstones (
+    record_id VARCHAR(255),
+    record_type VARCHAR(255),
+    user_id VARCHAR(255),
+    provider_id VARCHAR(255) NOT NULL DEFAULT '',
+    deleted_at DATETIME(6) NOT NULL,
+    PRIMARY KEY (record_id, record_type, user_id, provider_id)
+);
+
+CREATE TABLE IF NOT EXISTS reviews (
+    id VARCHAR(64) PRIMARY KEY,
+    record_id VARCHAR(255),
+    record_type VARCHAR(255),
+    user_id VARCHAR(255),
+    text TEXT,
+    status VARCHAR(16),
+    helpful_votes INT NOT NULL DEFAULT 0,
+    created_at DATETIME(6),
+    updated_at DATETIME(6),
+    INDEX record_reviews (record_id, record_type, status, created_at DESC, id DESC)
+);
+
+CREATE TABLE IF NOT EXISTS review_votes (
+    review_id VARCHAR(64),
+    user_id VARCHAR(255),
+    PRIMARY KEY (review_id, user_id)
 );
\ No newline at end of file
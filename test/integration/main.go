@@ -25,6 +25,9 @@ const (
 	metadataServiceAddr = "localhost:8081"
 	ratingServiceAddr   = "localhost:8082"
 	movieServiceAddr    = "localhost:8083"
+	// authServiceAddr is not served by the test, which does not rate movies
+	// or write reviews.
+	authServiceAddr = "localhost:8084"
 )
 
 func main() {
@@ -39,8 +42,13 @@ func main() {
 	defer metadataSrv.GracefulStop()
 	ratingSrv := startRatingService(ctx, registry)
 	defer ratingSrv.GracefulStop()
-	movieSrv := startMovieService(ctx, registry)
-	defer movieSrv.GracefulStop()
+	movieSrv, closeMovieSrv := startMovieService(ctx, registry)
+	defer func() {
+		movieSrv.GracefulStop()
+		if err := closeMovieSrv(); err != nil {
+			log.Printf("Movie service close error: %v\n", err)
+		}
+	}()
 
 	opts := grpc.WithTransportCredentials(insecure.NewCredentials())
 	metadataConn, err := grpc.Dial(metadataServiceAddr, opts)
@@ -90,7 +98,9 @@ func main() {
 	log.Println("Getting movie details via movie service")
 
 	wantMovieDetails := &gen.MovieDetails{
-		Metadata: m,
+		Metadata:       m,
+		MetadataStatus: gen.SectionStatus_SECTION_STATUS_OK,
+		RatingStatus:   gen.SectionStatus_SECTION_STATUS_NOT_FOUND,
 	}
 
 	getMovieDetailsResp, err := movieClient.GetMovieDetails(ctx, &gen.GetMovieDetailsRequest{MovieId: m.Id})
@@ -131,9 +141,10 @@ func main() {
 
 	log.Println("Saving second rating via rating service")
 
+	const secondUserID = "user1"
 	secondRating := int32(1)
 	if _, err = ratingClient.PutRating(ctx, &gen.PutRatingRequest{
-		UserId:      userID,
+		UserId:      secondUserID,
 		RecordId:    m.Id,
 		RecordType:  recordTypeMovie,
 		RatingValue: secondRating,
@@ -163,6 +174,7 @@ func main() {
 		log.Fatalf("get movie details: %v", err)
 	}
 	wantMovieDetails.Rating = wantRating
+	wantMovieDetails.RatingStatus = gen.SectionStatus_SECTION_STATUS_OK
 	if diff := cmp.Diff(getMovieDetailsResp.MovieDetails, wantMovieDetails, cmpopts.IgnoreUnexported(gen.MovieDetails{}, gen.Metadata{})); diff != "" {
 		log.Fatalf("get movie details after update mismatch: %v", err)
 	}
@@ -185,7 +197,7 @@ func startMetadataService(ctx context.Context, registry discovery.Registry) *grp
 		}
 	}()
 	id := discovery.GenerateInstanceID(metadataServiceName)
-	if err := registry.Register(ctx, id, metadataServiceName, metadataServiceAddr); err != nil {
+	if err := registry.Register(ctx, id, metadataServiceName, []discovery.Endpoint{{Name: discovery.EndpointGRPC, Protocol: "grpc", HostPort: metadataServiceAddr}}); err != nil {
 		panic(err)
 	}
 	return srv
@@ -193,7 +205,7 @@ func startMetadataService(ctx context.Context, registry discovery.Registry) *grp
 
 func startRatingService(ctx context.Context, registry discovery.Registry) *grpc.Server {
 	log.Println("Starting rating service on " + ratingServiceAddr)
-	h := ratingtest.NewTestRatingGRPCServer()
+	h := ratingtest.NewTestRatingGRPCServer(authServiceAddr)
 	l, err := net.Listen("tcp", ratingServiceAddr)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
@@ -206,15 +218,15 @@ func startRatingService(ctx context.Context, registry discovery.Registry) *grpc.
 		}
 	}()
 	id := discovery.GenerateInstanceID(ratingServiceName)
-	if err := registry.Register(ctx, id, ratingServiceName, ratingServiceAddr); err != nil {
+	if err := registry.Register(ctx, id, ratingServiceName, []discovery.Endpoint{{Name: discovery.EndpointGRPC, Protocol: "grpc", HostPort: ratingServiceAddr}}); err != nil {
 		panic(err)
 	}
 	return srv
 }
 
-func startMovieService(ctx context.Context, registry discovery.Registry) *grpc.Server {
+func startMovieService(ctx context.Context, registry discovery.Registry) (*grpc.Server, func() error) {
 	log.Println("Starting movie service on " + movieServiceAddr)
-	h := movietest.NewTestMovieGRPCServer(registry)
+	h, closeHandler := movietest.NewTestMovieGRPCServer(registry, authServiceAddr)
 	l, err := net.Listen("tcp", movieServiceAddr)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
@@ -227,8 +239,8 @@ func startMovieService(ctx context.Context, registry discovery.Registry) *grpc.S
 		}
 	}()
 	id := discovery.GenerateInstanceID(movieServiceName)
-	if err := registry.Register(ctx, id, movieServiceName, movieServiceAddr); err != nil {
+	if err := registry.Register(ctx, id, movieServiceName, []discovery.Endpoint{{Name: discovery.EndpointGRPC, Protocol: "grpc", HostPort: movieServiceAddr}}); err != nil {
 		panic(err)
 	}
-	return srv
+	return srv, closeHandler
 }